@@ -0,0 +1,340 @@
+package ai
+
+import (
+	"ai-reader/pkg/document"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AnthropicProviderName Anthropic提供者的名称
+const AnthropicProviderName = "anthropic"
+
+// anthropicVersion Messages API的版本
+const anthropicVersion = "2023-06-01"
+
+// AnthropicConfig Anthropic Messages API的配置
+type AnthropicConfig struct {
+	BaseURL   string
+	Model     string
+	APIKey    string
+	MaxTokens int           // 回复的最大token数
+	Timeout   time.Duration // 单次分析的时限，由服务管理器通过context控制
+
+	// WholeDocument 开启后把整个文档作为上下文发送，文档估计超过 ContextTokens 时只发送选中的文本
+	WholeDocument bool
+	ContextTokens int
+}
+
+// DefaultAnthropicConfig 默认配置
+func DefaultAnthropicConfig() AnthropicConfig {
+	return AnthropicConfig{
+		BaseURL:       "https://api.anthropic.com",
+		Model:         "claude-sonnet-4-0",
+		MaxTokens:     2048,
+		Timeout:       120 * time.Second,
+		ContextTokens: 150000,
+	}
+}
+
+// AnthropicProvider 通过 /v1/messages 接口分析文本，擅长结合长文档分析
+type AnthropicProvider struct {
+	config AnthropicConfig
+	client *http.Client
+}
+
+// NewAnthropicProvider 创建Anthropic提供者
+func NewAnthropicProvider(config AnthropicConfig) *AnthropicProvider {
+	return &AnthropicProvider{
+		config: config,
+		client: &http.Client{},
+	}
+}
+
+// anthropicBlock 消息内容块
+type anthropicBlock struct {
+	Type         string                 `json:"type"`
+	Text         string                 `json:"text"`
+	CacheControl map[string]interface{} `json:"cache_control,omitempty"`
+}
+
+// anthropicMessage 对话消息
+type anthropicMessage struct {
+	Role    string           `json:"role"`
+	Content []anthropicBlock `json:"content"`
+}
+
+// anthropicRequest Messages API 请求体
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	System    string             `json:"system,omitempty"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMessage `json:"messages"`
+	Stream    bool               `json:"stream,omitempty"`
+}
+
+// anthropicResponse Messages API 响应体
+type anthropicResponse struct {
+	ID      string           `json:"id"`
+	Model   string           `json:"model"`
+	Content []anthropicBlock `json:"content"`
+	Usage   struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// anthropicStreamEvent 流式响应中的事件，字段按事件类型使用
+type anthropicStreamEvent struct {
+	Type    string            `json:"type"`
+	Message anthropicResponse `json:"message"`
+	Delta   struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Usage struct {
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// GetName 获取AI服务名称
+func (p *AnthropicProvider) GetName() string {
+	return AnthropicProviderName
+}
+
+// IsAvailable 配置了模型和密钥时可用
+func (p *AnthropicProvider) IsAvailable() bool {
+	return p.config.BaseURL != "" && p.config.Model != "" && p.config.APIKey != ""
+}
+
+// Timeout 单次分析的时限
+func (p *AnthropicProvider) Timeout() time.Duration {
+	return p.config.Timeout
+}
+
+// ModelFor 分析类型使用的模型，所有类型使用同一个模型
+func (p *AnthropicProvider) ModelFor(analysisType string) string {
+	return p.config.Model
+}
+
+// GetSupportedAnalysisTypes 获取支持的分析类型
+func (p *AnthropicProvider) GetSupportedAnalysisTypes() []string {
+	return SupportedAnalysisTypes()
+}
+
+// AnalyzeText 分析文本，整篇文档模式下文档放在选中的文本之前作为上下文
+func (p *AnthropicProvider) AnalyzeText(ctx context.Context, request AnalysisRequest) (*AnalysisResult, error) {
+	start := time.Now()
+
+	message, wholeDocument, err := p.complete(ctx, request, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := structuredResult(ctx, message.text(), p.correction(request))
+	if err != nil {
+		return nil, err
+	}
+	result.ID = message.ID
+	result.Type = request.AnalysisType
+	result.ProcessTime = time.Since(start).Milliseconds()
+	result.Metadata = usageMetadata(message.Model, message.Usage.InputTokens, message.Usage.OutputTokens, wholeDocument)
+	return result, nil
+}
+
+// AnalyzeTextStream 流式分析文本，服务以Server-Sent Events逐段返回回复
+func (p *AnthropicProvider) AnalyzeTextStream(ctx context.Context, request AnalysisRequest) (<-chan AnalysisChunk, error) {
+	resp, wholeDocument, err := p.post(ctx, request, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan AnalysisChunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		var raw strings.Builder
+		var message anthropicResponse
+		var streamErr error
+		err := readSSE(resp.Body, func(event, data string) bool {
+			var e anthropicStreamEvent
+			if err := json.Unmarshal([]byte(data), &e); err != nil {
+				streamErr = fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+				return false
+			}
+			switch e.Type {
+			case "message_start":
+				message = e.Message
+			case "content_block_delta":
+				if e.Delta.Type == "text_delta" && e.Delta.Text != "" {
+					raw.WriteString(e.Delta.Text)
+					return emit(ctx, out, AnalysisChunk{Delta: e.Delta.Text})
+				}
+			case "message_delta":
+				message.Usage.OutputTokens = e.Usage.OutputTokens
+			case "message_stop":
+				return false
+			case "error":
+				streamErr = fmt.Errorf("%w: %s", ErrRequestFailed, e.Error.Message)
+				return false
+			}
+			return true
+		})
+		if streamErr == nil {
+			streamErr = err
+		}
+		if streamErr != nil {
+			emit(ctx, out, AnalysisChunk{Err: streamErr})
+			return
+		}
+
+		result, err := structuredResult(ctx, raw.String(), p.correction(request))
+		if err != nil {
+			emit(ctx, out, AnalysisChunk{Err: err})
+			return
+		}
+		result.ID = message.ID
+		result.Type = request.AnalysisType
+		result.Metadata = usageMetadata(message.Model, message.Usage.InputTokens, message.Usage.OutputTokens, wholeDocument)
+		emit(ctx, out, AnalysisChunk{Done: true, Result: result})
+	}()
+	return out, nil
+}
+
+// complete 发送非流式请求，返回完整的回复。wholeDocument表示是否发送了整个文档
+func (p *AnthropicProvider) complete(ctx context.Context, request AnalysisRequest, turns []chatMessage) (message *anthropicResponse, wholeDocument bool, err error) {
+	resp, wholeDocument, err := p.post(ctx, request, turns, false)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if message.text() == "" {
+		return nil, false, fmt.Errorf("%w: no text content", ErrInvalidResponse)
+	}
+	return message, wholeDocument, nil
+}
+
+// correction 在原来的对话之后请模型更正回复
+func (p *AnthropicProvider) correction(request AnalysisRequest) correctFunc {
+	return func(ctx context.Context, turns []chatMessage) (string, error) {
+		message, _, err := p.complete(ctx, request, turns)
+		if err != nil {
+			return "", err
+		}
+		return message.text(), nil
+	}
+}
+
+// text 回复中的文字
+func (r *anthropicResponse) text() string {
+	var text strings.Builder
+	for _, block := range r.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String()
+}
+
+// post 发送 Messages API 请求，非200响应转换为错误。turns是追加在分析提示之后的对话，
+// wholeDocument表示是否发送了整个文档
+func (p *AnthropicProvider) post(ctx context.Context, request AnalysisRequest, turns []chatMessage, stream bool) (resp *http.Response, wholeDocument bool, err error) {
+	prompt, err := buildPrompt(request)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var blocks []anthropicBlock
+	docText, title := p.documentContext(request)
+	if docText != "" {
+		// 同一文档的多次分析可以复用缓存的前缀
+		blocks = append(blocks, anthropicBlock{
+			Type:         "text",
+			Text:         fmt.Sprintf("<document title=%q>\n%s\n</document>", title, docText),
+			CacheControl: map[string]interface{}{"type": "ephemeral"},
+		})
+	}
+	blocks = append(blocks, anthropicBlock{Type: "text", Text: prompt})
+
+	messages := []anthropicMessage{{Role: "user", Content: blocks}}
+	for _, turn := range turns {
+		messages = append(messages, anthropicMessage{Role: turn.Role, Content: []anthropicBlock{{Type: "text", Text: turn.Content}}})
+	}
+
+	system := systemPrompt
+	if docText != "" {
+		system += "\nThe whole document is provided before the passage. Use it to explain the passage in context."
+	}
+	body, err := json.Marshal(anthropicRequest{
+		Model:     p.config.Model,
+		System:    system,
+		MaxTokens: p.config.MaxTokens,
+		Messages:  messages,
+		Stream:    stream,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(p.config.BaseURL, "/")+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.config.APIKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err = p.client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, false, responseError(resp.StatusCode, data)
+	}
+	return resp, docText != "", nil
+}
+
+// usageMetadata 模型、token用量和是否发送了整个文档
+func usageMetadata(model string, input, output int, wholeDocument bool) map[string]interface{} {
+	return map[string]interface{}{
+		MetadataModel:            model,
+		MetadataPromptTokens:     input,
+		MetadataCompletionTokens: output,
+		MetadataTotalTokens:      input + output,
+		MetadataWholeDocument:    wholeDocument,
+	}
+}
+
+// documentContext 整篇文档模式下获取要发送的文档内容，文档过长或无法读取时为空
+func (p *AnthropicProvider) documentContext(request AnalysisRequest) (text, title string) {
+	if !p.config.WholeDocument {
+		return "", ""
+	}
+	doc, ok := request.Parameters[ParamDocument].(document.Document)
+	if !ok || doc == nil {
+		return "", ""
+	}
+	content, err := doc.GetContent()
+	if err != nil || content == "" {
+		return "", ""
+	}
+	budget := p.config.ContextTokens - p.config.MaxTokens - estimateTokens(request.Text+request.Context)
+	if estimateTokens(content) > budget {
+		return "", ""
+	}
+	return content, doc.GetTitle()
+}
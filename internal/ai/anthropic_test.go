@@ -0,0 +1,152 @@
+package ai
+
+import (
+	"ai-reader/pkg/document"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeDocument 只有一页的内存文档
+type fakeDocument struct {
+	title   string
+	content string
+}
+
+func (d *fakeDocument) GetContent() (string, error)                    { return d.content, nil }
+func (d *fakeDocument) GetTitle() string                               { return d.title }
+func (d *fakeDocument) GetPages() int                                  { return 1 }
+func (d *fakeDocument) GetMetadata() document.Metadata                 { return document.Metadata{Title: d.title} }
+func (d *fakeDocument) Search(string) ([]document.SearchResult, error) { return nil, nil }
+func (d *fakeDocument) Close() error                                   { return nil }
+
+func (d *fakeDocument) GetPage(pageNum int) (string, error) {
+	if pageNum != 1 {
+		return "", document.ErrInvalidPage
+	}
+	return d.content, nil
+}
+
+// writeAnthropicStream 按Messages API的事件顺序写出回复
+func writeAnthropicStream(w http.ResponseWriter, reply string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	event := func(name, data string) {
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+	}
+	event("message_start", `{"type":"message_start","message":{"id":"msg_1","model":"test-model","content":[],"usage":{"input_tokens":25,"output_tokens":1}}}`)
+	event("content_block_start", `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`)
+	event("ping", `{"type":"ping"}`)
+	for _, part := range splitReply(reply, 9) {
+		event("content_block_delta", fmt.Sprintf(`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":%q}}`, part))
+	}
+	event("content_block_stop", `{"type":"content_block_stop","index":0}`)
+	event("message_delta", `{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":40}}`)
+	event("message_stop", `{"type":"message_stop"}`)
+}
+
+// newTestAnthropic 连接测试服务的提供者
+func newTestAnthropic(url string) *AnthropicProvider {
+	config := DefaultAnthropicConfig()
+	config.BaseURL = url
+	config.APIKey = "test-key"
+	return NewAnthropicProvider(config)
+}
+
+func TestAnthropicStream(t *testing.T) {
+	doc := &fakeDocument{title: "Tides", content: "A short book about the sea."}
+	tests := []struct {
+		name          string
+		wholeDocument bool
+		contextTokens int
+		wantDocument  bool
+	}{
+		{"selection only", false, 150000, false},
+		{"whole document", true, 150000, true},
+		{"document over the budget", true, 2050, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests recordedRequests
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/messages" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				if r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") != anthropicVersion {
+					t.Errorf("headers = %v", r.Header)
+				}
+				requests.record(t, r)
+				writeAnthropicStream(w, testReply)
+			}))
+			defer server.Close()
+
+			provider := newTestAnthropic(server.URL)
+			provider.config.WholeDocument = tt.wholeDocument
+			provider.config.ContextTokens = tt.contextTokens
+			request := testRequest
+			request.Parameters = map[string]interface{}{ParamDocument: doc}
+
+			ch, err := provider.AnalyzeTextStream(context.Background(), request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			deltas, last := collectStream(t, ch)
+			if deltas != testReply {
+				t.Fatalf("deltas = %q, want the whole reply", deltas)
+			}
+			result := last.Result
+			if result.ID != "msg_1" || result.Content != "The passage explains tides." {
+				t.Fatalf("result = %+v", result)
+			}
+			if result.Metadata[MetadataPromptTokens] != 25 || result.Metadata[MetadataCompletionTokens] != 40 {
+				t.Fatalf("metadata = %v", result.Metadata)
+			}
+			if result.Metadata[MetadataWholeDocument] != tt.wantDocument {
+				t.Fatalf("whole_document = %v, want %v", result.Metadata[MetadataWholeDocument], tt.wantDocument)
+			}
+
+			blocks := requests.all()[0]["messages"].([]interface{})[0].(map[string]interface{})["content"].([]interface{})
+			first := blocks[0].(map[string]interface{})
+			sentDocument := strings.Contains(first["text"].(string), doc.content)
+			if sentDocument != tt.wantDocument || (first["cache_control"] != nil) != tt.wantDocument {
+				t.Fatalf("first block = %v, want document %v", first, tt.wantDocument)
+			}
+		})
+	}
+}
+
+func TestAnthropicStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\"}}\n\n")
+		fmt.Fprint(w, "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n")
+	}))
+	defer server.Close()
+
+	ch, err := newTestAnthropic(server.URL).AnalyzeTextStream(context.Background(), testRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last AnalysisChunk
+	for chunk := range ch {
+		last = chunk
+	}
+	if !errors.Is(last.Err, ErrRequestFailed) || !strings.Contains(last.Err.Error(), "Overloaded") {
+		t.Fatalf("error = %v, want the error event", last.Err)
+	}
+}
+
+func TestAnthropicRequestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`)
+	}))
+	defer server.Close()
+
+	_, err := newTestAnthropic(server.URL).AnalyzeTextStream(context.Background(), testRequest)
+	if !errors.Is(err, ErrRequestFailed) || !strings.Contains(err.Error(), "invalid x-api-key") {
+		t.Fatalf("error = %v, want the server message", err)
+	}
+}
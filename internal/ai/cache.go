@@ -0,0 +1,428 @@
+package ai
+
+import (
+	"ai-reader/pkg/document"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheKeyVersion 缓存键格式的版本，键的组成变化时递增，旧的缓存自然不再命中
+const cacheKeyVersion = 2
+
+// cacheFileExt 磁盘缓存文件的扩展名
+const cacheFileExt = ".json"
+
+// CacheStats 缓存占用的报告
+type CacheStats struct {
+	MemoryEntries int
+	MemoryBytes   int64
+	DiskEntries   int
+	DiskBytes     int64
+}
+
+// cacheKeySource 参与计算缓存键的请求内容
+type cacheKeySource struct {
+	Version    int             `json:"v"`
+	Text       string          `json:"text"`
+	Context    string          `json:"context,omitempty"`
+	Type       string          `json:"type"`
+	Template   string          `json:"template"`
+	Language   string          `json:"language,omitempty"`
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+	Document   string          `json:"document,omitempty"`
+	Provider   string          `json:"provider"`
+	Model      string          `json:"model,omitempty"`
+}
+
+// CacheKey 分析结果的缓存键：规范化后的文本、分析类型及其模板的版本、语言、上下文、额外参数、提供者和模型的SHA-256。
+// 文本只合并空白，文档参数用文档的标题、页数、大小和格式代替；选区位置已经体现在上下文中，不参与计算
+func CacheKey(request AnalysisRequest, provider, model string) string {
+	source := cacheKeySource{
+		Version:  cacheKeyVersion,
+		Text:     normalizeText(request.Text),
+		Context:  normalizeText(request.Context),
+		Type:     request.AnalysisType,
+		Template: DefaultTemplates().Fingerprint(request.AnalysisType),
+		Language: strings.ToLower(request.Language),
+		Provider: provider,
+		Model:    model,
+	}
+
+	params := make(map[string]interface{}, len(request.Parameters))
+	for k, v := range request.Parameters {
+		switch k {
+		case ParamDocument:
+			if doc, ok := v.(document.Document); ok {
+				meta := doc.GetMetadata()
+				source.Document = fmt.Sprintf("%s|%d|%d|%s", doc.GetTitle(), doc.GetPages(), meta.FileSize, meta.Format)
+			}
+			continue
+		case ParamPage, ParamSelectionStart, ParamSelectionEnd:
+			continue
+		}
+		params[k] = v
+	}
+	if len(params) > 0 {
+		// encoding/json 按键排序输出map，结果是稳定的
+		data, err := json.Marshal(params)
+		if err != nil {
+			data, _ = json.Marshal(fmt.Sprintf("%v", params))
+		}
+		source.Parameters = data
+	}
+
+	data, _ := json.Marshal(source)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeText 合并连续空白并去掉首尾空白，排版不同的同一段文字得到同一个键
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// encodeResult 序列化要缓存的结果，不保存所属的请求
+func encodeResult(result *AnalysisResult) ([]byte, error) {
+	stored := *result
+	stored.RequestID = ""
+	return json.Marshal(&stored)
+}
+
+// decodeResult 反序列化缓存的结果，每次得到新的副本，调用方可以随意修改
+func decodeResult(data []byte) (*AnalysisResult, bool) {
+	var result AnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, false
+	}
+	return &result, true
+}
+
+// expired 按创建时间判断缓存是否过期，ttl不大于0时永不过期
+func expired(createdAt time.Time, ttl time.Duration) bool {
+	return ttl > 0 && time.Since(createdAt) > ttl
+}
+
+// MemoryCache 内存中的LRU缓存，条目数或总字节数超过限制时淘汰最久未使用的结果
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	ttl        time.Duration
+	entries    map[string]*list.Element
+	order      *list.List // 最近使用的在前
+	bytes      int64
+}
+
+// memoryEntry 内存缓存的条目，保存序列化后的结果以便计算占用并返回副本
+type memoryEntry struct {
+	key       string
+	data      []byte
+	createdAt time.Time
+}
+
+// NewMemoryCache 创建内存缓存，限制不大于0时不限制
+func NewMemoryCache(maxEntries int, maxBytes int64, ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ttl:        ttl,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get 获取缓存的分析结果
+func (c *MemoryCache) Get(key string) (*AnalysisResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*memoryEntry)
+	if expired(entry.createdAt, c.ttl) {
+		c.remove(e)
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return decodeResult(entry.data)
+}
+
+// Set 设置缓存
+func (c *MemoryCache) Set(key string, result *AnalysisResult) {
+	data, err := encodeResult(result)
+	if err != nil {
+		return
+	}
+	c.put(key, data, time.Now())
+}
+
+// put 保存序列化后的结果，超过单条上限的结果不缓存
+func (c *MemoryCache) put(key string, data []byte, createdAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.maxBytes > 0 && int64(len(data)) > c.maxBytes {
+		return
+	}
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, data: data, createdAt: createdAt})
+	c.bytes += int64(len(data))
+
+	for c.order.Len() > 0 && (c.maxEntries > 0 && c.order.Len() > c.maxEntries || c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove 删除条目，调用方需持有锁
+func (c *MemoryCache) remove(e *list.Element) {
+	entry := c.order.Remove(e).(*memoryEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= int64(len(entry.data))
+}
+
+// Delete 删除缓存
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+}
+
+// Clear 清空缓存
+func (c *MemoryCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.bytes = 0
+}
+
+// GetCacheKey 生成缓存键
+func (c *MemoryCache) GetCacheKey(request AnalysisRequest, provider, model string) string {
+	return CacheKey(request, provider, model)
+}
+
+// Stats 缓存占用
+func (c *MemoryCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{MemoryEntries: c.order.Len(), MemoryBytes: c.bytes}
+}
+
+// DiskCache 磁盘缓存，每个结果保存为目录下以缓存键命名的JSON文件，文件的修改时间即创建时间
+type DiskCache struct {
+	mu  sync.Mutex
+	dir string
+	ttl time.Duration
+}
+
+// NewDiskCache 创建磁盘缓存，目录在第一次写入时创建
+func NewDiskCache(dir string, ttl time.Duration) *DiskCache {
+	return &DiskCache{dir: dir, ttl: ttl}
+}
+
+// path 缓存键对应的文件
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.dir, key+cacheFileExt)
+}
+
+// Get 获取缓存的分析结果
+func (c *DiskCache) Get(key string) (*AnalysisResult, bool) {
+	data, _, ok := c.load(key)
+	if !ok {
+		return nil, false
+	}
+	return decodeResult(data)
+}
+
+// load 读取序列化后的结果和创建时间，过期的文件被删除
+func (c *DiskCache) load(key string) ([]byte, time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(c.path(key))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	if expired(info.ModTime(), c.ttl) {
+		os.Remove(c.path(key))
+		return nil, time.Time{}, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	return data, info.ModTime(), true
+}
+
+// Set 设置缓存。先写临时文件再改名，中途退出不会留下不完整的结果
+func (c *DiskCache) Set(key string, result *AnalysisResult) {
+	data, err := encodeResult(result)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, c.path(key)); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// Delete 删除缓存
+func (c *DiskCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	os.Remove(c.path(key))
+}
+
+// Clear 清空缓存
+func (c *DiskCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.walk(func(path string, info fs.FileInfo) {
+		os.Remove(path)
+	})
+}
+
+// Prune 删除过期的缓存文件，返回删除的文件数
+func (c *DiskCache) Prune() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	c.walk(func(path string, info fs.FileInfo) {
+		if expired(info.ModTime(), c.ttl) && os.Remove(path) == nil {
+			removed++
+		}
+	})
+	return removed
+}
+
+// GetCacheKey 生成缓存键
+func (c *DiskCache) GetCacheKey(request AnalysisRequest, provider, model string) string {
+	return CacheKey(request, provider, model)
+}
+
+// Stats 缓存占用
+func (c *DiskCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var stats CacheStats
+	c.walk(func(path string, info fs.FileInfo) {
+		stats.DiskEntries++
+		stats.DiskBytes += info.Size()
+	})
+	return stats
+}
+
+// walk 遍历缓存目录下的缓存文件，调用方需持有锁
+func (c *DiskCache) walk(fn func(path string, info fs.FileInfo)) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), cacheFileExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		fn(filepath.Join(c.dir, entry.Name()), info)
+	}
+}
+
+// TieredCache 内存缓存在前、磁盘缓存在后的两级缓存。
+// 内存未命中时读取磁盘，命中的结果放回内存；写入和删除同时作用于两级
+type TieredCache struct {
+	memory *MemoryCache
+	disk   *DiskCache
+}
+
+// NewTieredCache 创建两级缓存
+func NewTieredCache(memory *MemoryCache, disk *DiskCache) *TieredCache {
+	return &TieredCache{memory: memory, disk: disk}
+}
+
+// Get 获取缓存的分析结果
+func (c *TieredCache) Get(key string) (*AnalysisResult, bool) {
+	if result, ok := c.memory.Get(key); ok {
+		return result, true
+	}
+	data, createdAt, ok := c.disk.load(key)
+	if !ok {
+		return nil, false
+	}
+	result, ok := decodeResult(data)
+	if ok {
+		// 保留磁盘上的创建时间，过期时间不因读取而延长
+		c.memory.put(key, data, createdAt)
+	}
+	return result, ok
+}
+
+// Set 设置缓存
+func (c *TieredCache) Set(key string, result *AnalysisResult) {
+	c.memory.Set(key, result)
+	c.disk.Set(key, result)
+}
+
+// Delete 删除缓存
+func (c *TieredCache) Delete(key string) {
+	c.memory.Delete(key)
+	c.disk.Delete(key)
+}
+
+// Clear 清空缓存
+func (c *TieredCache) Clear() {
+	c.memory.Clear()
+	c.disk.Clear()
+}
+
+// Prune 删除磁盘上过期的缓存，返回删除的文件数
+func (c *TieredCache) Prune() int {
+	return c.disk.Prune()
+}
+
+// GetCacheKey 生成缓存键
+func (c *TieredCache) GetCacheKey(request AnalysisRequest, provider, model string) string {
+	return CacheKey(request, provider, model)
+}
+
+// Stats 两级缓存的占用
+func (c *TieredCache) Stats() CacheStats {
+	stats := c.disk.Stats()
+	memory := c.memory.Stats()
+	stats.MemoryEntries, stats.MemoryBytes = memory.MemoryEntries, memory.MemoryBytes
+	return stats
+}
@@ -0,0 +1,220 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cachedResult 测试用的分析结果，content决定序列化后的大小
+func cachedResult(content string) *AnalysisResult {
+	return &AnalysisResult{ID: "r", RequestID: "req", Type: AnalysisTypeExplain, Content: content}
+}
+
+// cacheKeys 缓存中按最近使用排列的键
+func cacheKeys(c *MemoryCache) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var keys []string
+	for e := c.order.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*memoryEntry).key)
+	}
+	return keys
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	size := func(content string) int64 {
+		data, _ := encodeResult(cachedResult(content))
+		return int64(len(data))
+	}
+	tests := []struct {
+		name       string
+		maxEntries int
+		maxBytes   int64
+		sets       []string // 依次写入的键，以 ? 开头的表示读取
+		want       []string
+	}{
+		{"entry limit", 2, 0, []string{"a", "b", "c"}, []string{"c", "b"}},
+		{"read refreshes", 2, 0, []string{"a", "b", "?a", "c"}, []string{"c", "a"}},
+		{"byte limit", 0, 2*size("a") + 1, []string{"a", "b", "c"}, []string{"c", "b"}},
+		{"overwrite", 2, 0, []string{"a", "b", "a", "c"}, []string{"c", "a"}},
+		{"no limits", 0, 0, []string{"a", "b", "c"}, []string{"c", "b", "a"}},
+		{"larger than the limit", 0, size("a") - 1, []string{"a"}, nil},
+	}
+	for _, tt := range tests {
+		c := NewMemoryCache(tt.maxEntries, tt.maxBytes, 0)
+		for _, key := range tt.sets {
+			if strings.HasPrefix(key, "?") {
+				c.Get(key[1:])
+				continue
+			}
+			c.Set(key, cachedResult(key))
+		}
+		got := cacheKeys(c)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: keys = %q, want %q", tt.name, got, tt.want)
+		}
+		if stats := c.Stats(); stats.MemoryEntries != len(tt.want) || stats.MemoryBytes != int64(len(tt.want))*size("a") {
+			t.Errorf("%s: stats = %+v", tt.name, stats)
+		}
+	}
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	c := NewMemoryCache(0, 0, time.Hour)
+	data, _ := encodeResult(cachedResult("old"))
+	c.put("old", data, time.Now().Add(-2*time.Hour))
+	c.Set("new", cachedResult("new"))
+
+	if _, ok := c.Get("old"); ok {
+		t.Fatal("expired entry returned")
+	}
+	if c.Stats().MemoryEntries != 1 {
+		t.Fatal("expired entry kept after the read")
+	}
+	result, ok := c.Get("new")
+	if !ok || result.Content != "new" {
+		t.Fatalf("Get(new) = %+v, %v", result, ok)
+	}
+	if result.RequestID != "" {
+		t.Fatalf("request ID %q stored with the result", result.RequestID)
+	}
+
+	// 调用方修改返回的结果不影响缓存
+	result.Content = "changed"
+	if again, _ := c.Get("new"); again.Content != "new" {
+		t.Fatalf("cached content = %q after changing a returned copy", again.Content)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "cache")
+	c := NewDiskCache(dir, time.Hour)
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("hit in an empty cache")
+	}
+
+	c.Set("a", cachedResult("alpha"))
+	c.Set("b", cachedResult("beta"))
+	if result, ok := c.Get("a"); !ok || result.Content != "alpha" {
+		t.Fatalf("Get(a) = %+v, %v", result, ok)
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Fatalf("temporary file %s left behind", entry.Name())
+		}
+	}
+	if stats := c.Stats(); stats.DiskEntries != 2 || stats.DiskBytes == 0 {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	// 过期的文件在读取和清理时删除
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(c.path("a"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expired file returned")
+	}
+	if _, err := os.Stat(c.path("a")); !os.IsNotExist(err) {
+		t.Fatal("expired file kept after the read")
+	}
+	if err := os.Chtimes(c.path("b"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	c.Set("c", cachedResult("gamma"))
+	if removed := c.Prune(); removed != 1 {
+		t.Fatalf("Prune() = %d, want 1", removed)
+	}
+
+	// 目录中的其他文件不受影响
+	other := filepath.Join(dir, "notes.txt")
+	os.WriteFile(other, []byte("keep"), 0644)
+	c.Clear()
+	if stats := c.Stats(); stats.DiskEntries != 0 {
+		t.Fatalf("stats after Clear = %+v", stats)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Fatal("Clear removed a file that is not a cache entry")
+	}
+}
+
+func TestTieredCache(t *testing.T) {
+	dir := t.TempDir()
+	memory := NewMemoryCache(1, 0, time.Hour)
+	disk := NewDiskCache(dir, time.Hour)
+	c := NewTieredCache(memory, disk)
+
+	c.Set("a", cachedResult("alpha"))
+	c.Set("b", cachedResult("beta"))
+	if keys := cacheKeys(memory); len(keys) != 1 || keys[0] != "b" {
+		t.Fatalf("memory keys = %q, want b", keys)
+	}
+
+	// 内存中被淘汰的结果从磁盘读回，创建时间保持磁盘上的时间
+	created := time.Now().Add(-30 * time.Minute).Truncate(time.Second)
+	if err := os.Chtimes(disk.path("a"), created, created); err != nil {
+		t.Fatal(err)
+	}
+	if result, ok := c.Get("a"); !ok || result.Content != "alpha" {
+		t.Fatalf("Get(a) = %+v, %v", result, ok)
+	}
+	memory.mu.Lock()
+	entry, ok := memory.entries["a"]
+	memory.mu.Unlock()
+	if !ok || !entry.Value.(*memoryEntry).createdAt.Equal(created) {
+		t.Fatal("result read from disk not kept in memory with its creation time")
+	}
+
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("deleted result returned")
+	}
+	c.Clear()
+	if stats := c.Stats(); stats.MemoryEntries != 0 || stats.DiskEntries != 0 {
+		t.Fatalf("stats after Clear = %+v", stats)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	base := AnalysisRequest{Text: "The moon  pulls\nthe sea.", AnalysisType: AnalysisTypeExplain}
+	with := func(change func(r *AnalysisRequest)) AnalysisRequest {
+		r := base
+		r.Parameters = map[string]interface{}{}
+		change(&r)
+		return r
+	}
+	key := CacheKey(base, "openai", "m")
+
+	same := []AnalysisRequest{
+		with(func(r *AnalysisRequest) { r.Text = " The moon pulls the sea. " }),
+		with(func(r *AnalysisRequest) { r.Parameters[ParamPage] = 3 }),
+		with(func(r *AnalysisRequest) { r.Parameters[ParamSelectionStart], r.Parameters[ParamSelectionEnd] = 10, 20 }),
+		with(func(r *AnalysisRequest) { r.ID = "another request" }),
+	}
+	for i, r := range same {
+		if got := CacheKey(r, "openai", "m"); got != key {
+			t.Errorf("request %d: key changed", i)
+		}
+	}
+
+	different := []AnalysisRequest{
+		with(func(r *AnalysisRequest) { r.Text = "The sun" }),
+		with(func(r *AnalysisRequest) { r.AnalysisType = AnalysisTypeSummarize }),
+		with(func(r *AnalysisRequest) { r.Context = "Chapter 1" }),
+		with(func(r *AnalysisRequest) { r.Language = "zh" }),
+		with(func(r *AnalysisRequest) { r.Parameters[ParamAlignedText] = "月亮" }),
+		with(func(r *AnalysisRequest) { r.Parameters[ParamDocument] = &fakeDocument{title: "Tides"} }),
+	}
+	for i, r := range different {
+		if got := CacheKey(r, "openai", "m"); got == key {
+			t.Errorf("request %d: key unchanged", i)
+		}
+	}
+	if CacheKey(base, "ollama", "m") == key || CacheKey(base, "openai", "other") == key {
+		t.Error("key does not depend on the provider and model")
+	}
+}
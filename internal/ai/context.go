@@ -0,0 +1,309 @@
+package ai
+
+import (
+	"ai-reader/pkg/annotation"
+	"ai-reader/pkg/document"
+	"ai-reader/pkg/segment"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 上下文的默认限制
+const (
+	DefaultContextTokens     = 1500 // 上下文的token预算
+	DefaultContextParagraphs = 3    // 选中文本前后各取的最多段落数
+	DefaultContextHighlights = 5    // 最多附带的之前的高亮数
+)
+
+// ContextSource 为一次选择组装上下文所需的材料
+type ContextSource struct {
+	Document   document.Document
+	Page       int
+	Start, End int                    // 选中文本在页内的rune偏移，未知时为-1，按文本在页内查找
+	Text       string                 // 选中的文本
+	Highlights []annotation.Highlight // 文档中的高亮，只使用选中文本之前的
+}
+
+// ContextBuilder 为选中的文本组装上下文：文档标题和作者、所在章节、前后的段落和之前的高亮。
+// 按这个顺序在token预算内取舍，段落由近及远，高亮由近及远
+type ContextBuilder struct {
+	Tokens     int
+	Paragraphs int
+	Highlights int
+
+	mu         sync.Mutex
+	outlineDoc document.Document
+	outline    []document.OutlineEntry
+}
+
+// NewContextBuilder 创建上下文组装器，使用默认限制
+func NewContextBuilder() *ContextBuilder {
+	return &ContextBuilder{
+		Tokens:     DefaultContextTokens,
+		Paragraphs: DefaultContextParagraphs,
+		Highlights: DefaultContextHighlights,
+	}
+}
+
+// contextBudget 剩余的token预算
+type contextBudget int
+
+// take 预算足够时扣除文本的token数
+func (b *contextBudget) take(text string) bool {
+	tokens := estimateTokens(text)
+	if tokens > int(*b) {
+		return false
+	}
+	*b -= contextBudget(tokens)
+	return true
+}
+
+// Build 组装上下文，没有可用的材料时为空
+func (cb *ContextBuilder) Build(source ContextSource) string {
+	if source.Document == nil || cb.Tokens <= 0 {
+		return ""
+	}
+	budget := contextBudget(cb.Tokens)
+
+	var header []string
+	if line := documentLine(source.Document); line != "" && budget.take(line) {
+		header = append(header, line)
+	}
+
+	runes, start, end, located := cb.locate(source)
+	if path := cb.chapterPath(source.Document, source.Page, start); path != "" {
+		if line := "Chapter: " + path; budget.take(line) {
+			header = append(header, line)
+		}
+	}
+
+	var before, after []string
+	var current string
+	if located {
+		before, current, after = cb.surrounding(source, runes, start, end, &budget)
+	}
+
+	var highlights []string
+	if located {
+		highlights = cb.earlierHighlights(source, start, &budget)
+	}
+
+	var b strings.Builder
+	for _, line := range header {
+		b.WriteString(line + "\n")
+	}
+	if len(highlights) > 0 {
+		b.WriteString("\nEarlier highlights:\n")
+		for _, h := range highlights {
+			b.WriteString(h + "\n")
+		}
+	}
+	if current != "" {
+		b.WriteString("\nSurrounding text:\n")
+		for i := len(before) - 1; i >= 0; i-- {
+			b.WriteString(before[i] + "\n\n")
+		}
+		b.WriteString(current + "\n")
+		for _, p := range after {
+			b.WriteString("\n" + p + "\n")
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// documentLine 文档的标题和作者
+func documentLine(doc document.Document) string {
+	meta := doc.GetMetadata()
+	title := meta.Title
+	if title == "" {
+		title = doc.GetTitle()
+	}
+	switch {
+	case title != "" && meta.Author != "":
+		return fmt.Sprintf("Document: %s, by %s", title, meta.Author)
+	case title != "":
+		return "Document: " + title
+	case meta.Author != "":
+		return "Author: " + meta.Author
+	}
+	return ""
+}
+
+// locate 确定选中文本在页内的位置。给出的偏移与文本不符时在页内查找文本，
+// 找不到时返回false，这时只使用页码
+func (cb *ContextBuilder) locate(source ContextSource) ([]rune, int, int, bool) {
+	content, err := source.Document.GetPage(source.Page)
+	if err != nil {
+		return nil, 0, 0, false
+	}
+	runes := []rune(content)
+	if source.Start >= 0 && source.Start < source.End && source.End <= len(runes) &&
+		(source.Text == "" || string(runes[source.Start:source.End]) == source.Text) {
+		return runes, source.Start, source.End, true
+	}
+	if source.Text == "" {
+		return runes, 0, 0, false
+	}
+	i := strings.Index(content, source.Text)
+	if i < 0 {
+		return runes, 0, 0, false
+	}
+	start := len([]rune(content[:i]))
+	return runes, start, start + len([]rune(source.Text)), true
+}
+
+// chapterPath 位置所在的章节，从上到下各级标题以 “ > ” 连接
+func (cb *ContextBuilder) chapterPath(doc document.Document, page, offset int) string {
+	cb.mu.Lock()
+	if doc != cb.outlineDoc {
+		cb.outlineDoc = doc
+		cb.outline = document.ExtractOutline(doc)
+	}
+	outline := cb.outline
+	cb.mu.Unlock()
+
+	// 每一级只保留位置之前最近的标题，出现上级标题时清除更深的级别
+	var trail []document.OutlineEntry
+	for _, entry := range outline {
+		if entry.Page > page || entry.Page == page && entry.Offset > offset {
+			break
+		}
+		for len(trail) > 0 && trail[len(trail)-1].Level >= entry.Level {
+			trail = trail[:len(trail)-1]
+		}
+		trail = append(trail, entry)
+	}
+
+	titles := make([]string, len(trail))
+	for i, entry := range trail {
+		titles[i] = entry.Title
+	}
+	return strings.Join(titles, " > ")
+}
+
+// surrounding 选中文本所在的段落及前后的段落，前后段落由近及远排列，跨页时取相邻页的段落
+func (cb *ContextBuilder) surrounding(source ContextSource, runes []rune, start, end int, budget *contextBudget) (before []string, current string, after []string) {
+	paragraphs := segment.Paragraphs(runes)
+	first, last := -1, -1
+	for i, p := range paragraphs {
+		if p.End > start && p.Start < end || p.Start <= start && start <= p.End {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return nil, "", nil
+	}
+
+	var spans []string
+	for _, p := range paragraphs[first : last+1] {
+		spans = append(spans, string(runes[p.Start:p.End]))
+	}
+	current = strings.Join(spans, "\n\n")
+	if !budget.take(current) {
+		return nil, "", nil
+	}
+
+	var prev, next []string
+	for i := first - 1; i >= 0; i-- {
+		prev = append(prev, string(runes[paragraphs[i].Start:paragraphs[i].End]))
+	}
+	for _, p := range paragraphs[last+1:] {
+		next = append(next, string(runes[p.Start:p.End]))
+	}
+	if len(prev) < cb.Paragraphs {
+		prev = append(prev, reversed(pageParagraphs(source.Document, source.Page-1))...)
+	}
+	if len(next) < cb.Paragraphs {
+		next = append(next, pageParagraphs(source.Document, source.Page+1)...)
+	}
+
+	// 前后交替由近及远加入，预算不足的一侧停止
+	beforeOpen, afterOpen := true, true
+	for i := 0; i < cb.Paragraphs && (beforeOpen || afterOpen); i++ {
+		if beforeOpen {
+			if beforeOpen = i < len(prev) && budget.take(prev[i]); beforeOpen {
+				before = append(before, prev[i])
+			}
+		}
+		if afterOpen {
+			if afterOpen = i < len(next) && budget.take(next[i]); afterOpen {
+				after = append(after, next[i])
+			}
+		}
+	}
+	return before, current, after
+}
+
+// pageParagraphs 一页的所有段落，页码无效时为空
+func pageParagraphs(doc document.Document, page int) []string {
+	if page < 1 || page > doc.GetPages() {
+		return nil
+	}
+	content, err := doc.GetPage(page)
+	if err != nil {
+		return nil
+	}
+	runes := []rune(content)
+	var paragraphs []string
+	for _, p := range segment.Paragraphs(runes) {
+		paragraphs = append(paragraphs, string(runes[p.Start:p.End]))
+	}
+	return paragraphs
+}
+
+// reversed 倒序的副本
+func reversed(items []string) []string {
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[len(items)-1-i] = item
+	}
+	return result
+}
+
+// earlierHighlights 选中文本之前的高亮，由近及远在预算内选取，按文档顺序返回
+func (cb *ContextBuilder) earlierHighlights(source ContextSource, start int, budget *contextBudget) []string {
+	if cb.Highlights <= 0 || len(source.Highlights) == 0 {
+		return nil
+	}
+
+	// 高亮的偏移是全文偏移，换算出选中文本在全文中的位置
+	position := start
+	for page := 1; page < source.Page; page++ {
+		content, err := source.Document.GetPage(page)
+		if err != nil {
+			return nil
+		}
+		position += len([]rune(content))
+	}
+
+	var earlier []annotation.Highlight
+	for _, h := range source.Highlights {
+		if h.End <= position && strings.TrimSpace(h.Text) != "" {
+			earlier = append(earlier, h)
+		}
+	}
+	sort.SliceStable(earlier, func(i, j int) bool {
+		return earlier[i].Start > earlier[j].Start
+	})
+
+	var lines []string
+	for _, h := range earlier {
+		if len(lines) >= cb.Highlights {
+			break
+		}
+		line := "- " + strings.Join(strings.Fields(h.Text), " ")
+		if h.Note != "" {
+			line += " (note: " + strings.Join(strings.Fields(h.Note), " ") + ")"
+		}
+		if !budget.take(line) {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return reversed(lines)
+}
@@ -0,0 +1,35 @@
+package ai
+
+import "errors"
+
+var (
+	// ErrProviderNotFound 提供者未注册
+	ErrProviderNotFound = errors.New("ai provider not found")
+	// ErrNoProviderAvailable 没有可用的提供者
+	ErrNoProviderAvailable = errors.New("no ai provider available")
+	// ErrNoLocalProvider 只允许本地处理时没有可用的本地提供者
+	ErrNoLocalProvider = errors.New("no local ai provider available, cloud providers are disabled")
+	// ErrRequestFailed 服务返回了错误状态
+	ErrRequestFailed = errors.New("ai request failed")
+	// ErrInvalidResponse 服务的响应无法解析
+	ErrInvalidResponse = errors.New("invalid ai response")
+	// ErrTimeout 提供者没有在时限内完成分析
+	ErrTimeout = errors.New("ai request timed out")
+	// ErrUnsupportedAnalysisType 提供者不支持请求的分析类型
+	ErrUnsupportedAnalysisType = errors.New("analysis type not supported by provider")
+)
+
+// RequestError 分析请求失败的错误，记录所属的请求，便于丢弃已过时请求的错误
+type RequestError struct {
+	RequestID string
+	Err       error
+}
+
+// Error 只返回原始错误的信息，请求ID不需要显示给用户
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,182 @@
+package ai
+
+import (
+	"ai-reader/pkg/document"
+	"ai-reader/pkg/extract"
+	"ai-reader/pkg/segment"
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HeuristicProviderName 内置离线提供者的名称
+const HeuristicProviderName = "heuristic"
+
+// 离线分析的结果数量
+const (
+	heuristicSummarySentences = 3
+	heuristicKeywords         = 8
+	heuristicPhrases          = 3
+	heuristicConcepts         = 5
+)
+
+// heuristicMaxConfidence 抽取式分析的最高置信度，结果只是原文中的句子和词，不是理解
+const heuristicMaxConfidence = 0.35
+
+// HeuristicProvider 不需要网络的内置提供者：TextRank抽取摘要，TF-IDF和RAKE抽取关键词，
+// 大写短语和重复出现的短语作为概念候选。结果是确定的，也可以作为测试用的提供者
+type HeuristicProvider struct {
+	mu        sync.Mutex
+	corpusDoc document.Document
+	corpus    *extract.Corpus
+}
+
+// NewHeuristicProvider 创建内置离线提供者
+func NewHeuristicProvider() *HeuristicProvider {
+	return &HeuristicProvider{}
+}
+
+// GetName 获取AI服务名称
+func (p *HeuristicProvider) GetName() string {
+	return HeuristicProviderName
+}
+
+// IsLocal 文本只在本机处理
+func (p *HeuristicProvider) IsLocal() bool {
+	return true
+}
+
+// IsAvailable 总是可用
+func (p *HeuristicProvider) IsAvailable() bool {
+	return true
+}
+
+// GetSupportedAnalysisTypes 获取支持的分析类型，抽取式的摘要和关键词只适合解释和概括
+func (p *HeuristicProvider) GetSupportedAnalysisTypes() []string {
+	return []string{AnalysisTypeExplain, AnalysisTypeSummarize}
+}
+
+// AnalyzeText 分析文本，填写摘要、关键词和概念，正文留空
+func (p *HeuristicProvider) AnalyzeText(ctx context.Context, request AnalysisRequest) (*AnalysisResult, error) {
+	start := time.Now()
+	runes := []rune(request.Text)
+	sentences := segment.Sentences(runes)
+
+	// 关键词可能需要为整个文档建立语料，完成后检查请求是否已经取消
+	keywords := p.keywords(runes, request)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &AnalysisResult{
+		Type:     request.AnalysisType,
+		Summary:  p.summary(runes, len(sentences)),
+		Keywords: keywords,
+		Concepts: concepts(runes, sentences),
+	}
+	result.Confidence = heuristicConfidence(len(sentences), len(strings.Fields(request.Text))+countCJK(runes))
+	result.ProcessTime = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// summary 摘要句数随文本长度增加，不超过三句；文本只有一两句时原样作为摘要
+func (p *HeuristicProvider) summary(runes []rune, sentenceCount int) string {
+	if sentenceCount <= 2 {
+		return strings.TrimSpace(string(runes))
+	}
+	count := sentenceCount / 3
+	if count < 1 {
+		count = 1
+	}
+	if count > heuristicSummarySentences {
+		count = heuristicSummarySentences
+	}
+
+	var parts []string
+	for _, s := range extract.Summarize(runes, count) {
+		parts = append(parts, s.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// keywords 多词短语在前，其余为TF-IDF关键词。有文档时以文档的段落为语料计算逆文档频率
+func (p *HeuristicProvider) keywords(runes []rune, request AnalysisRequest) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, phrase := range extract.Phrases(runes, heuristicPhrases) {
+		if strings.Contains(phrase.Text, " ") {
+			keywords = append(keywords, phrase.Text)
+			for _, w := range strings.Fields(phrase.Text) {
+				seen[w] = true
+			}
+		}
+	}
+
+	doc, _ := request.Parameters[ParamDocument].(document.Document)
+	for _, k := range extract.Keywords(runes, p.documentCorpus(doc), heuristicKeywords) {
+		if len(keywords) >= heuristicKeywords {
+			break
+		}
+		if !seen[k.Text] {
+			seen[k.Text] = true
+			keywords = append(keywords, k.Text)
+		}
+	}
+	return keywords
+}
+
+// documentCorpus 文档的段落语料，缓存最近一个文档的结果。没有文档时返回nil
+func (p *HeuristicProvider) documentCorpus(doc document.Document) *extract.Corpus {
+	if doc == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if doc != p.corpusDoc {
+		content, err := doc.GetContent()
+		if err != nil {
+			return nil
+		}
+		p.corpusDoc = doc
+		p.corpus = extract.ParagraphCorpus([]rune(content))
+	}
+	return p.corpus
+}
+
+// concepts 概念候选，定义为原文中第一次提到它的句子
+func concepts(runes []rune, sentences []segment.Span) []Concept {
+	var result []Concept
+	for _, k := range extract.Concepts(runes, heuristicConcepts) {
+		c := Concept{Name: k.Text, Importance: float32(k.Score)}
+		for _, s := range sentences {
+			text := string(runes[s.Start:s.End])
+			if strings.Contains(strings.ToLower(text), strings.ToLower(k.Text)) {
+				c.Definition = strings.TrimSpace(text)
+				break
+			}
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// heuristicConfidence 文本越长，抽取的结果越可靠：至少五句、六十个词时达到最高置信度
+func heuristicConfidence(sentences, words int) float32 {
+	coverage := math.Min(float64(sentences)/5, 1) * math.Min(float64(words)/60, 1)
+	return float32(math.Max(0.05, heuristicMaxConfidence*coverage))
+}
+
+// countCJK CJK字数，每个字按一个词计算
+func countCJK(runes []rune) int {
+	count := 0
+	for _, r := range runes {
+		if segment.IsCJK(r) {
+			count++
+		}
+	}
+	return count
+}
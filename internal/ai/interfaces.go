@@ -1,12 +1,17 @@
 package ai
 
+import (
+	"context"
+	"time"
+)
+
 // AIProvider AI服务提供者接口
 type AIProvider interface {
 	// GetName 获取AI服务名称
 	GetName() string
 	
-	// AnalyzeText 分析文本
-	AnalyzeText(request AnalysisRequest) (*AnalysisResult, error)
+	// AnalyzeText 分析文本，ctx取消或到期时尽快返回
+	AnalyzeText(ctx context.Context, request AnalysisRequest) (*AnalysisResult, error)
 	
 	// IsAvailable 检查服务是否可用
 	IsAvailable() bool
@@ -15,8 +20,47 @@ type AIProvider interface {
 	GetSupportedAnalysisTypes() []string
 }
 
+// StreamingProvider 可以流式输出分析结果的提供者
+type StreamingProvider interface {
+	// AnalyzeTextStream 流式分析文本，通道在最后一段或出错后关闭
+	AnalyzeTextStream(ctx context.Context, request AnalysisRequest) (<-chan AnalysisChunk, error)
+}
+
+// TimedProvider 有单次分析时限的提供者。服务管理器按时限为每次分析设置截止时间，
+// 超时后改用下一个提供者
+type TimedProvider interface {
+	// Timeout 单次分析的时限，不大于0时不限制
+	Timeout() time.Duration
+}
+
+// AnalysisChunk 流式分析的一段输出。提供者只需填写Delta，最后一段填写Done和Result；
+// RequestID、Content和Tokens由服务管理器填写
+type AnalysisChunk struct {
+	RequestID string          // 所属请求的ID
+	Delta     string          // 本段新增的原始输出
+	Content   string          // 到目前为止可以显示的正文
+	Tokens    int             // 到目前为止输出的token数，为估计值
+	Done      bool            // 是否为最后一段
+	Result    *AnalysisResult // 完整结果，只在最后一段中
+	Err       error           // 中途出错时的错误，之前的输出仍然有效
+}
+
+// ModelProvider 可以报告所用模型的提供者。缓存键包含模型，换用模型后不会命中旧的结果
+type ModelProvider interface {
+	// ModelFor 分析类型使用的模型
+	ModelFor(analysisType string) string
+}
+
+// LocalProvider 可以在本机处理文本的提供者。只允许本地处理时，
+// 没有实现该接口或 IsLocal 返回false的提供者不会被使用
+type LocalProvider interface {
+	// IsLocal 文本是否只在本机处理
+	IsLocal() bool
+}
+
 // AnalysisRequest 分析请求
 type AnalysisRequest struct {
+	ID          string                 `json:"id,omitempty"`          // 请求ID，用于取消请求和识别过期的结果
 	Text        string                 `json:"text"`
 	Context     string                 `json:"context,omitempty"`     // 上下文信息
 	AnalysisType string                `json:"analysis_type"`         // 分析类型
@@ -24,9 +68,44 @@ type AnalysisRequest struct {
 	Parameters  map[string]interface{} `json:"parameters,omitempty"`  // 额外参数
 }
 
+// 分析类型
+const (
+	AnalysisTypeExplain    = "explain"    // 解释选中的文本
+	AnalysisTypeSummarize  = "summarize"  // 概括要点
+	AnalysisTypeTranslate  = "translate"  // 翻译成界面语言
+	AnalysisTypeDefine     = "define"     // 解释术语
+	AnalysisTypeBackground = "background" // 历史和文化背景
+	AnalysisTypeCritique   = "critique"   // 评析论点和论证
+	AnalysisTypeSimplify   = "simplify"   // 用浅显的语言改写
+	AnalysisTypeCompare    = "compare"    // 对照分析并排阅读的两个文档中对齐的段落
+)
+
+// 分析请求的额外参数
+const (
+	ParamAlignedText = "aligned_text" // 对照文档中对齐的文字
+	ParamDocument    = "document"     // 选中文本所在的文档，值为 document.Document
+	ParamPage        = "page"         // 选中文本所在的页码，值为int
+
+	// 选中文本在当前页内的rune偏移，值为int，用于组装上下文
+	ParamSelectionStart = "selection_start"
+	ParamSelectionEnd   = "selection_end"
+)
+
+// 分析结果的元数据键
+const (
+	MetadataProvider         = "provider"          // 给出结果的提供者名称
+	MetadataModel            = "model"             // 使用的模型
+	MetadataPromptTokens     = "prompt_tokens"     // 提示消耗的token数
+	MetadataCompletionTokens = "completion_tokens" // 回复消耗的token数
+	MetadataTotalTokens      = "total_tokens"      // 总token数
+	MetadataWholeDocument    = "whole_document"    // 是否把整个文档作为上下文发送
+	MetadataCached           = "cached"            // 结果是否来自缓存
+)
+
 // AnalysisResult 分析结果
 type AnalysisResult struct {
 	ID          string                 `json:"id"`
+	RequestID   string                 `json:"request_id,omitempty"` // 所属请求的ID
 	Type        string                 `json:"type"`
 	Content     string                 `json:"content"`
 	Summary     string                 `json:"summary,omitempty"`
@@ -68,11 +147,20 @@ type AIService interface {
 	// SetDefaultProvider 设置默认提供者
 	SetDefaultProvider(name string) error
 	
+	// SetLocalOnly 设置是否只允许文本在本机处理，开启后不使用云端提供者
+	SetLocalOnly(localOnly bool)
+	
 	// AnalyzeText 分析文本（使用默认提供者）
-	AnalyzeText(request AnalysisRequest) (*AnalysisResult, error)
+	AnalyzeText(ctx context.Context, request AnalysisRequest) (*AnalysisResult, error)
 	
 	// AnalyzeTextWithProvider 使用指定提供者分析文本
-	AnalyzeTextWithProvider(providerName string, request AnalysisRequest) (*AnalysisResult, error)
+	AnalyzeTextWithProvider(ctx context.Context, providerName string, request AnalysisRequest) (*AnalysisResult, error)
+	
+	// AnalyzeTextStream 流式分析文本（使用默认提供者）
+	AnalyzeTextStream(ctx context.Context, request AnalysisRequest) (<-chan AnalysisChunk, error)
+	
+	// SetCache 设置分析缓存，为nil时不使用缓存
+	SetCache(cache AnalysisCache)
 }
 
 // AnalysisCache 分析缓存接口
@@ -89,6 +177,9 @@ type AnalysisCache interface {
 	// Clear 清空缓存
 	Clear()
 	
-	// GetCacheKey 生成缓存键
-	GetCacheKey(request AnalysisRequest) string
+	// GetCacheKey 生成缓存键，同一请求交给不同的提供者或模型时键不同
+	GetCacheKey(request AnalysisRequest, provider, model string) string
+	
+	// Stats 缓存占用
+	Stats() CacheStats
 }
\ No newline at end of file
@@ -0,0 +1,27 @@
+package ai
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// isLocal 提供者是否只在本机处理文本，没有实现 LocalProvider 的提供者视为云端服务
+func isLocal(provider AIProvider) bool {
+	local, ok := provider.(LocalProvider)
+	return ok && local.IsLocal()
+}
+
+// isLoopback 地址是否指向本机
+func isLoopback(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
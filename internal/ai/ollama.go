@@ -0,0 +1,409 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OllamaProviderName Ollama提供者的名称
+const OllamaProviderName = "ollama"
+
+// ollamaProbeInterval 两次探测本地服务的最短间隔
+const ollamaProbeInterval = 30 * time.Second
+
+// ollamaRetryInterval 探测失败后再次探测的间隔，服务刚启动时可以很快被发现
+const ollamaRetryInterval = 5 * time.Second
+
+// ollamaProbeTimeout 探测本地服务的超时时间
+const ollamaProbeTimeout = 2 * time.Second
+
+// OllamaConfig Ollama服务的配置
+type OllamaConfig struct {
+	BaseURL     string
+	Model       string            // 默认模型
+	Models      map[string]string // 按分析类型指定的模型，未指定的类型使用默认模型
+	Temperature float64
+	Timeout     time.Duration // 单次分析的时限，由服务管理器通过context控制
+	JSONSchema  bool          // 按JSON Schema约束输出，需要0.5以上的版本，关闭时只要求输出JSON
+}
+
+// DefaultOllamaConfig 默认配置
+func DefaultOllamaConfig() OllamaConfig {
+	return OllamaConfig{
+		BaseURL:     "http://localhost:11434",
+		Model:       "qwen2.5:7b",
+		Temperature: 0.3,
+		Timeout:     120 * time.Second,
+		JSONSchema:  true,
+	}
+}
+
+// OllamaProvider 通过本机Ollama服务分析文本，文本不会离开本机
+type OllamaProvider struct {
+	config OllamaConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	probing  chan struct{} // 正在进行的探测，结束时关闭；没有探测时为nil
+	probedAt time.Time
+	models   map[string]bool // 已安装的模型，探测失败时为nil
+}
+
+// NewOllamaProvider 创建Ollama提供者
+func NewOllamaProvider(config OllamaConfig) *OllamaProvider {
+	return &OllamaProvider{
+		config: config,
+		client: &http.Client{},
+	}
+}
+
+// ollamaOptions 模型参数
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature"`
+}
+
+// ollamaChatRequest /api/chat 请求体
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []chatMessage   `json:"messages"`
+	Format   json.RawMessage `json:"format,omitempty"`
+	Stream   bool            `json:"stream"`
+	Options  ollamaOptions   `json:"options"`
+}
+
+// ollamaGenerateRequest /api/generate 请求体
+type ollamaGenerateRequest struct {
+	Model   string          `json:"model"`
+	System  string          `json:"system,omitempty"`
+	Prompt  string          `json:"prompt"`
+	Format  json.RawMessage `json:"format,omitempty"`
+	Stream  bool            `json:"stream"`
+	Options ollamaOptions   `json:"options"`
+}
+
+// ollamaResponse /api/chat 和 /api/generate 的响应体，前者回复在Message中，后者在Response中
+type ollamaResponse struct {
+	Model           string      `json:"model"`
+	Message         chatMessage `json:"message"`
+	Response        string      `json:"response"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	EvalCount       int         `json:"eval_count"`
+	Error           string      `json:"error"`
+}
+
+// ollamaTags /api/tags 响应体
+type ollamaTags struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+// GetName 获取AI服务名称
+func (p *OllamaProvider) GetName() string {
+	return OllamaProviderName
+}
+
+// IsLocal 服务地址在本机时文本不会离开本机
+func (p *OllamaProvider) IsLocal() bool {
+	return isLoopback(p.config.BaseURL)
+}
+
+// IsAvailable 本地服务在运行且安装了默认模型时可用
+func (p *OllamaProvider) IsAvailable() bool {
+	models := p.installedModels()
+	return models != nil && models[p.ModelFor("")]
+}
+
+// Timeout 单次分析的时限
+func (p *OllamaProvider) Timeout() time.Duration {
+	return p.config.Timeout
+}
+
+// GetSupportedAnalysisTypes 获取支持的分析类型，只包括所用模型已安装的类型
+func (p *OllamaProvider) GetSupportedAnalysisTypes() []string {
+	models := p.installedModels()
+	var types []string
+	for _, t := range SupportedAnalysisTypes() {
+		if models[p.ModelFor(t)] {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// AnalyzeText 分析文本
+func (p *OllamaProvider) AnalyzeText(ctx context.Context, request AnalysisRequest) (*AnalysisResult, error) {
+	start := time.Now()
+
+	reply, err := p.complete(ctx, request, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := structuredResult(ctx, reply.text(), p.correction(request))
+	if err != nil {
+		return nil, err
+	}
+	result.Type = request.AnalysisType
+	result.ProcessTime = time.Since(start).Milliseconds()
+	result.Metadata = reply.metadata()
+	return result, nil
+}
+
+// AnalyzeTextStream 流式分析文本，服务每行返回一段JSON，最后一行的done为true
+func (p *OllamaProvider) AnalyzeTextStream(ctx context.Context, request AnalysisRequest) (<-chan AnalysisChunk, error) {
+	resp, err := p.open(ctx, request, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan AnalysisChunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		var raw strings.Builder
+		var last ollamaResponse
+		var streamErr error
+		err := readLines(resp.Body, func(line string) bool {
+			if strings.TrimSpace(line) == "" {
+				return true
+			}
+			var reply ollamaResponse
+			if err := json.Unmarshal([]byte(line), &reply); err != nil {
+				streamErr = fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+				return false
+			}
+			if reply.Error != "" {
+				streamErr = fmt.Errorf("%w: %s", ErrRequestFailed, reply.Error)
+				return false
+			}
+			last = reply
+			if delta := reply.text(); delta != "" {
+				raw.WriteString(delta)
+				return emit(ctx, out, AnalysisChunk{Delta: delta})
+			}
+			return true
+		})
+		if streamErr == nil {
+			streamErr = err
+		}
+		if streamErr != nil {
+			emit(ctx, out, AnalysisChunk{Err: streamErr})
+			return
+		}
+
+		result, err := structuredResult(ctx, raw.String(), p.correction(request))
+		if err != nil {
+			emit(ctx, out, AnalysisChunk{Err: err})
+			return
+		}
+		result.Type = request.AnalysisType
+		result.Metadata = last.metadata()
+		emit(ctx, out, AnalysisChunk{Done: true, Result: result})
+	}()
+	return out, nil
+}
+
+// complete 发送非流式请求，返回完整的回复
+func (p *OllamaProvider) complete(ctx context.Context, request AnalysisRequest, turns []chatMessage) (*ollamaResponse, error) {
+	resp, err := p.open(ctx, request, turns, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var reply ollamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return &reply, nil
+}
+
+// correction 在原来的对话之后请模型更正回复
+func (p *OllamaProvider) correction(request AnalysisRequest) correctFunc {
+	return func(ctx context.Context, turns []chatMessage) (string, error) {
+		reply, err := p.complete(ctx, request, turns)
+		if err != nil {
+			return "", err
+		}
+		return reply.text(), nil
+	}
+}
+
+// format 回复的格式：开启时为JSON Schema，否则只要求JSON
+func (p *OllamaProvider) format() json.RawMessage {
+	if p.config.JSONSchema {
+		return analysisSchema
+	}
+	return json.RawMessage(`"json"`)
+}
+
+// open 发送分析请求，服务不支持 /api/chat 时改用 /api/generate。turns是追加在分析提示之后的对话
+func (p *OllamaProvider) open(ctx context.Context, request AnalysisRequest, turns []chatMessage, stream bool) (*http.Response, error) {
+	model := p.ModelFor(request.AnalysisType)
+	prompt, err := buildPrompt(request)
+	if err != nil {
+		return nil, err
+	}
+	options := ollamaOptions{Temperature: p.config.Temperature}
+
+	resp, message, err := p.post(ctx, "/api/chat", ollamaChatRequest{
+		Model: model,
+		Messages: append([]chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: prompt},
+		}, turns...),
+		Format:  p.format(),
+		Stream:  stream,
+		Options: options,
+	})
+	if resp == nil && err == nil && !strings.Contains(message, model) {
+		// 早期版本没有 /api/chat；模型不存在时同样返回404，此时不重试。
+		// /api/generate 没有对话，之后的对话接在提示后面
+		for _, turn := range turns {
+			prompt += fmt.Sprintf("\n\n%s:\n%s", turn.Role, turn.Content)
+		}
+		resp, _, err = p.post(ctx, "/api/generate", ollamaGenerateRequest{
+			Model:   model,
+			System:  systemPrompt,
+			Prompt:  prompt,
+			Format:  p.format(),
+			Stream:  stream,
+			Options: options,
+		})
+	}
+	if err == nil && resp == nil {
+		err = fmt.Errorf("%w: %d %s", ErrRequestFailed, http.StatusNotFound, message)
+	}
+	return resp, err
+}
+
+// post 发送请求，成功时返回响应。返回404时响应和错误都为nil，message为错误信息，由调用方决定是否重试
+func (p *OllamaProvider) post(ctx context.Context, path string, payload interface{}) (*http.Response, string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(p.config.BaseURL, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, "", nil
+	}
+	defer resp.Body.Close()
+
+	var reply ollamaResponse
+	data, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(data, &reply) != nil || reply.Error == "" {
+		reply.Error = http.StatusText(resp.StatusCode)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, reply.Error, nil
+	}
+	return nil, "", fmt.Errorf("%w: %d %s", ErrRequestFailed, resp.StatusCode, reply.Error)
+}
+
+// text 回复的文字，/api/chat 在Message中，/api/generate 在Response中
+func (r *ollamaResponse) text() string {
+	if r.Message.Content != "" {
+		return r.Message.Content
+	}
+	return r.Response
+}
+
+// metadata 模型和token用量，流式响应中只有最后一行带有用量
+func (r *ollamaResponse) metadata() map[string]interface{} {
+	return map[string]interface{}{
+		MetadataModel:            r.Model,
+		MetadataPromptTokens:     r.PromptEvalCount,
+		MetadataCompletionTokens: r.EvalCount,
+		MetadataTotalTokens:      r.PromptEvalCount + r.EvalCount,
+	}
+}
+
+// installedModels 通过 /api/tags 获取已安装的模型，结果缓存一段时间。服务未运行时返回nil。
+// 探测时不持有锁，同时调用时只探测一次，其余调用等待同一个结果
+func (p *OllamaProvider) installedModels() map[string]bool {
+	p.mu.Lock()
+	if p.fresh() {
+		defer p.mu.Unlock()
+		return p.models
+	}
+	if done := p.probing; done != nil {
+		p.mu.Unlock()
+		<-done
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.models
+	}
+	done := make(chan struct{})
+	p.probing = done
+	p.mu.Unlock()
+
+	models := p.probe()
+
+	p.mu.Lock()
+	p.models, p.probedAt, p.probing = models, time.Now(), nil
+	p.mu.Unlock()
+	close(done)
+	return models
+}
+
+// fresh 上次探测的结果是否还可以使用，失败的结果保留的时间较短。调用方持有锁
+func (p *OllamaProvider) fresh() bool {
+	if p.probedAt.IsZero() {
+		return false
+	}
+	interval := ollamaProbeInterval
+	if p.models == nil {
+		interval = ollamaRetryInterval
+	}
+	return time.Since(p.probedAt) < interval
+}
+
+// probe 请求 /api/tags，服务未运行或响应无法解析时返回nil
+func (p *OllamaProvider) probe() map[string]bool {
+	client := &http.Client{Timeout: ollamaProbeTimeout}
+	resp, err := client.Get(strings.TrimRight(p.config.BaseURL, "/") + "/api/tags")
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var tags ollamaTags
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&tags) != nil {
+		return nil
+	}
+	models := make(map[string]bool, len(tags.Models))
+	for _, m := range tags.Models {
+		models[m.Name] = true
+		// 未写标签的模型名默认为latest
+		if strings.HasSuffix(m.Name, ":latest") {
+			models[strings.TrimSuffix(m.Name, ":latest")] = true
+		}
+	}
+	return models
+}
+
+// ModelFor 分析类型使用的模型，没有单独指定时使用默认模型
+func (p *OllamaProvider) ModelFor(analysisType string) string {
+	if model := p.config.Models[analysisType]; model != "" {
+		return model
+	}
+	return p.config.Model
+}
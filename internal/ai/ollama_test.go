@@ -0,0 +1,199 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// writeOllamaStream 每行写出一段回复，chat为false时按 /api/generate 的格式写在response字段中
+func writeOllamaStream(w http.ResponseWriter, reply string, chat bool) {
+	encoder := json.NewEncoder(w)
+	for _, part := range splitReply(reply, 5) {
+		line := map[string]interface{}{"model": "test-model", "done": false}
+		if chat {
+			line["message"] = map[string]string{"role": "assistant", "content": part}
+		} else {
+			line["response"] = part
+		}
+		encoder.Encode(line)
+	}
+	encoder.Encode(map[string]interface{}{"model": "test-model", "done": true, "prompt_eval_count": 15, "eval_count": 35})
+}
+
+// newTestOllama 连接测试服务的提供者
+func newTestOllama(url string) *OllamaProvider {
+	config := DefaultOllamaConfig()
+	config.BaseURL = url
+	return NewOllamaProvider(config)
+}
+
+func TestOllamaStream(t *testing.T) {
+	tests := []struct {
+		name         string
+		chatStatus   int
+		chatError    string
+		wantGenerate bool
+		wantErr      bool
+	}{
+		{"chat endpoint", http.StatusOK, "", false, false},
+		{"old server without chat", http.StatusNotFound, "404 page not found", true, false},
+		{"model not installed", http.StatusNotFound, `model "qwen2.5:7b" not found, try pulling it first`, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var chat, generate recordedRequests
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/chat":
+					chat.record(t, r)
+					if tt.chatStatus != http.StatusOK {
+						w.WriteHeader(tt.chatStatus)
+						fmt.Fprintf(w, `{"error":%q}`, tt.chatError)
+						return
+					}
+					writeOllamaStream(w, testReply, true)
+				case "/api/generate":
+					generate.record(t, r)
+					writeOllamaStream(w, testReply, false)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			ch, err := newTestOllama(server.URL).AnalyzeTextStream(context.Background(), testRequest)
+			if tt.wantErr {
+				if !errors.Is(err, ErrRequestFailed) || !strings.Contains(err.Error(), "not found") {
+					t.Fatalf("error = %v, want model not found", err)
+				}
+				if len(generate.all()) != 0 {
+					t.Fatal("fell back to /api/generate for a missing model")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			deltas, last := collectStream(t, ch)
+			if deltas != testReply {
+				t.Fatalf("deltas = %q, want the whole reply", deltas)
+			}
+			if last.Result.Content != "The passage explains tides." {
+				t.Fatalf("result = %+v", last.Result)
+			}
+			if last.Result.Metadata[MetadataTotalTokens] != 50 {
+				t.Fatalf("metadata = %v", last.Result.Metadata)
+			}
+
+			requests := generate.all()
+			if got := len(requests) == 1; got != tt.wantGenerate {
+				t.Fatalf("/api/generate called %d times, want fallback %v", len(requests), tt.wantGenerate)
+			}
+			if tt.wantGenerate {
+				body := requests[0]
+				if body["system"] != systemPrompt || !strings.Contains(body["prompt"].(string), testRequest.Text) {
+					t.Fatalf("generate request = %v", body)
+				}
+				if body["stream"] != true || body["format"] == nil {
+					t.Fatalf("generate request = %v, want stream and format", body)
+				}
+			}
+		})
+	}
+}
+
+func TestOllamaStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"model":"test-model","message":{"role":"assistant","content":"{\"con"},"done":false}`)
+		fmt.Fprintln(w, `{"error":"out of memory"}`)
+	}))
+	defer server.Close()
+
+	ch, err := newTestOllama(server.URL).AnalyzeTextStream(context.Background(), testRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last AnalysisChunk
+	for chunk := range ch {
+		last = chunk
+	}
+	if !errors.Is(last.Err, ErrRequestFailed) || !strings.Contains(last.Err.Error(), "out of memory") {
+		t.Fatalf("error = %v, want the error line", last.Err)
+	}
+}
+
+func TestOllamaInstalledModels(t *testing.T) {
+	var probes atomic.Int32
+	var failing atomic.Bool
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes.Add(1)
+		<-release
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"models":[{"name":"qwen2.5:7b"},{"name":"llama3:latest"}]}`)
+	}))
+	defer server.Close()
+	provider := newTestOllama(server.URL)
+	provider.config.Models = map[string]string{AnalysisTypeTranslate: "missing:1b"}
+
+	// 同时调用只探测一次
+	var wg sync.WaitGroup
+	results := make([]map[string]bool, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = provider.installedModels()
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if got := probes.Load(); got != 1 {
+		t.Fatalf("probes = %d, want 1", got)
+	}
+	for _, models := range results {
+		if !models["qwen2.5:7b"] || !models["llama3"] || !models["llama3:latest"] {
+			t.Fatalf("models = %v", models)
+		}
+	}
+	if !provider.IsAvailable() || probes.Load() != 1 {
+		t.Fatalf("available %v after %d probes, want cached success", provider.IsAvailable(), probes.Load())
+	}
+	for _, analysisType := range provider.GetSupportedAnalysisTypes() {
+		if analysisType == AnalysisTypeTranslate {
+			t.Fatal("translate supported although its model is not installed")
+		}
+	}
+
+	// 失败的结果很快过期
+	failing.Store(true)
+	provider.mu.Lock()
+	provider.probedAt = time.Now().Add(-ollamaProbeInterval)
+	provider.mu.Unlock()
+	if provider.IsAvailable() {
+		t.Fatal("available after the server failed")
+	}
+	failing.Store(false)
+	provider.mu.Lock()
+	provider.probedAt = time.Now().Add(-ollamaRetryInterval)
+	provider.mu.Unlock()
+	if !provider.IsAvailable() {
+		t.Fatal("not available after the server recovered")
+	}
+	if got := probes.Load(); got != 3 {
+		t.Fatalf("probes = %d, want 3", got)
+	}
+}
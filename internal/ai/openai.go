@@ -0,0 +1,388 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// OpenAIProviderName OpenAI兼容提供者的名称
+const OpenAIProviderName = "openai"
+
+// openAIHost 官方API的主机名，只有官方API必须提供密钥
+const openAIHost = "api.openai.com"
+
+// OpenAIConfig OpenAI兼容接口的配置。BaseURL指向 /v1 一级，
+// 也可以指向LM Studio、vLLM或公司网关等兼容服务
+type OpenAIConfig struct {
+	BaseURL     string
+	Model       string
+	APIKey      string
+	Temperature float64
+	Timeout     time.Duration // 单次分析的时限，由服务管理器通过context控制
+	JSONSchema  bool          // 按JSON Schema约束输出，兼容服务不支持 response_format 时关闭
+	StreamUsage bool          // 流式请求要求返回token用量，兼容服务不支持 stream_options 时关闭
+}
+
+// DefaultOpenAIConfig 默认配置
+func DefaultOpenAIConfig() OpenAIConfig {
+	return OpenAIConfig{
+		BaseURL:     "https://api.openai.com/v1",
+		Model:       "gpt-4o-mini",
+		Temperature: 0.3,
+		Timeout:     60 * time.Second,
+		JSONSchema:  true,
+		StreamUsage: true,
+	}
+}
+
+// OpenAIProvider 通过 /v1/chat/completions 接口分析文本
+type OpenAIProvider struct {
+	config OpenAIConfig
+	client *http.Client
+	// plain 服务拒绝过 response_format 或 stream_options，之后的请求不再发送这些字段
+	plain atomic.Bool
+}
+
+// NewOpenAIProvider 创建OpenAI兼容提供者
+func NewOpenAIProvider(config OpenAIConfig) *OpenAIProvider {
+	return &OpenAIProvider{
+		config: config,
+		client: &http.Client{},
+	}
+}
+
+// chatMessage 对话消息
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatRequest chat completions 请求体
+type chatRequest struct {
+	Model          string              `json:"model"`
+	Messages       []chatMessage       `json:"messages"`
+	Temperature    float64             `json:"temperature"`
+	ResponseFormat *chatResponseFormat `json:"response_format,omitempty"`
+	Stream         bool                `json:"stream,omitempty"`
+	StreamOptions  *chatStreamOptions  `json:"stream_options,omitempty"`
+}
+
+// optionalFields 兼容服务可能不支持的请求字段，出现在400响应中时去掉这些字段重试
+var optionalFields = []string{"response_format", "json_schema", "stream_options"}
+
+// chatStreamOptions 流式请求的选项，要求在最后一段返回token用量
+type chatStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// chatResponseFormat 要求回复符合JSON Schema
+type chatResponseFormat struct {
+	Type       string         `json:"type"`
+	JSONSchema chatJSONSchema `json:"json_schema"`
+}
+
+// chatJSONSchema 回复的Schema
+type chatJSONSchema struct {
+	Name   string          `json:"name"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+// chatResponse chat completions 响应体
+type chatResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage chatUsage `json:"usage"`
+}
+
+// chatUsage token用量
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// metadata 模型和token用量
+func (u chatUsage) metadata(model string) map[string]interface{} {
+	return map[string]interface{}{
+		MetadataModel:            model,
+		MetadataPromptTokens:     u.PromptTokens,
+		MetadataCompletionTokens: u.CompletionTokens,
+		MetadataTotalTokens:      u.TotalTokens,
+	}
+}
+
+// chatStreamChunk 流式响应中的一段
+type chatStreamChunk struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta chatMessage `json:"delta"`
+	} `json:"choices"`
+	Usage *chatUsage `json:"usage"` // 只在最后一段中出现
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// apiError 兼容接口的错误响应体
+type apiError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// GetName 获取AI服务名称
+func (p *OpenAIProvider) GetName() string {
+	return OpenAIProviderName
+}
+
+// IsLocal 兼容服务运行在本机时文本不会离开本机
+func (p *OpenAIProvider) IsLocal() bool {
+	return isLoopback(p.config.BaseURL)
+}
+
+// IsAvailable 配置了模型且有密钥时可用，非官方地址的兼容服务可以不提供密钥
+func (p *OpenAIProvider) IsAvailable() bool {
+	if p.config.BaseURL == "" || p.config.Model == "" {
+		return false
+	}
+	if p.config.APIKey != "" {
+		return true
+	}
+	u, err := url.Parse(p.config.BaseURL)
+	return err == nil && u.Host != openAIHost
+}
+
+// Timeout 单次分析的时限
+func (p *OpenAIProvider) Timeout() time.Duration {
+	return p.config.Timeout
+}
+
+// ModelFor 分析类型使用的模型，所有类型使用同一个模型
+func (p *OpenAIProvider) ModelFor(analysisType string) string {
+	return p.config.Model
+}
+
+// GetSupportedAnalysisTypes 获取支持的分析类型
+func (p *OpenAIProvider) GetSupportedAnalysisTypes() []string {
+	return SupportedAnalysisTypes()
+}
+
+// AnalyzeText 分析文本
+func (p *OpenAIProvider) AnalyzeText(ctx context.Context, request AnalysisRequest) (*AnalysisResult, error) {
+	start := time.Now()
+
+	completion, err := p.complete(ctx, request, nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := structuredResult(ctx, completion.Choices[0].Message.Content, p.correction(request))
+	if err != nil {
+		return nil, err
+	}
+	result.ID = completion.ID
+	result.Type = request.AnalysisType
+	result.ProcessTime = time.Since(start).Milliseconds()
+	result.Metadata = completion.Usage.metadata(completion.Model)
+	return result, nil
+}
+
+// AnalyzeTextStream 流式分析文本，服务以Server-Sent Events逐段返回回复
+func (p *OpenAIProvider) AnalyzeTextStream(ctx context.Context, request AnalysisRequest) (<-chan AnalysisChunk, error) {
+	resp, err := p.post(ctx, request, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan AnalysisChunk)
+	go func() {
+		defer close(out)
+		defer resp.Body.Close()
+
+		var raw strings.Builder
+		var id, model string
+		var usage *chatUsage
+		var streamErr error
+		err := readSSE(resp.Body, func(event, data string) bool {
+			if data == "[DONE]" {
+				return false
+			}
+			var chunk chatStreamChunk
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				streamErr = fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+				return false
+			}
+			if chunk.Error != nil {
+				streamErr = fmt.Errorf("%w: %s", ErrRequestFailed, chunk.Error.Message)
+				return false
+			}
+			id, model = chunk.ID, chunk.Model
+			if chunk.Usage != nil {
+				usage = chunk.Usage
+			}
+			if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+				return true
+			}
+			raw.WriteString(chunk.Choices[0].Delta.Content)
+			return emit(ctx, out, AnalysisChunk{Delta: chunk.Choices[0].Delta.Content})
+		})
+		if streamErr == nil {
+			streamErr = err
+		}
+		if streamErr != nil {
+			emit(ctx, out, AnalysisChunk{Err: streamErr})
+			return
+		}
+
+		result, err := structuredResult(ctx, raw.String(), p.correction(request))
+		if err != nil {
+			emit(ctx, out, AnalysisChunk{Err: err})
+			return
+		}
+		result.ID = id
+		result.Type = request.AnalysisType
+		// 不支持 stream_options 的兼容服务不返回用量，这时只估计回复的token数
+		if usage != nil {
+			result.Metadata = usage.metadata(model)
+		} else {
+			result.Metadata = map[string]interface{}{
+				MetadataModel:            model,
+				MetadataCompletionTokens: estimateTokens(raw.String()),
+			}
+		}
+		emit(ctx, out, AnalysisChunk{Done: true, Result: result})
+	}()
+	return out, nil
+}
+
+// complete 发送非流式请求，返回完整的回复
+func (p *OpenAIProvider) complete(ctx context.Context, request AnalysisRequest, turns []chatMessage) (*chatResponse, error) {
+	resp, err := p.post(ctx, request, turns, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var completion chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("%w: no choices", ErrInvalidResponse)
+	}
+	return &completion, nil
+}
+
+// correction 在原来的对话之后请模型更正回复
+func (p *OpenAIProvider) correction(request AnalysisRequest) correctFunc {
+	return func(ctx context.Context, turns []chatMessage) (string, error) {
+		completion, err := p.complete(ctx, request, turns)
+		if err != nil {
+			return "", err
+		}
+		return completion.Choices[0].Message.Content, nil
+	}
+}
+
+// post 发送 chat completions 请求，非200响应转换为错误。turns是追加在分析提示之后的对话
+func (p *OpenAIProvider) post(ctx context.Context, request AnalysisRequest, turns []chatMessage, stream bool) (*http.Response, error) {
+	prompt, err := buildPrompt(request)
+	if err != nil {
+		return nil, err
+	}
+	payload := chatRequest{
+		Model: p.config.Model,
+		Messages: append([]chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: prompt},
+		}, turns...),
+		Temperature: p.config.Temperature,
+		Stream:      stream,
+	}
+	plain := p.plain.Load()
+	if stream && p.config.StreamUsage && !plain {
+		payload.StreamOptions = &chatStreamOptions{IncludeUsage: true}
+	}
+	if p.config.JSONSchema && !plain {
+		payload.ResponseFormat = &chatResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: chatJSONSchema{Name: "analysis", Schema: analysisSchema},
+		}
+	}
+
+	resp, data, err := p.send(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	// 不认识可选字段的兼容服务以400拒绝整个请求，去掉这些字段重试一次，回复格式仍由提示约束
+	if resp.StatusCode == http.StatusBadRequest && (payload.ResponseFormat != nil || payload.StreamOptions != nil) && mentionsOptionalField(data) {
+		p.plain.Store(true)
+		payload.ResponseFormat, payload.StreamOptions = nil, nil
+		if resp, data, err = p.send(ctx, payload); err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp.StatusCode, data)
+	}
+	return resp, nil
+}
+
+// send 发送请求。非200响应的响应体读出后关闭并一起返回，由调用方决定是否重试
+func (p *OpenAIProvider) send(ctx context.Context, payload chatRequest) (*http.Response, []byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(p.config.BaseURL, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp, data, nil
+	}
+	return resp, nil, nil
+}
+
+// mentionsOptionalField 错误响应是否提到了可选字段
+func mentionsOptionalField(body []byte) bool {
+	text := strings.ToLower(string(body))
+	for _, field := range optionalFields {
+		if strings.Contains(text, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// responseError 把非200响应转换为错误，优先使用响应体中的错误信息
+func responseError(status int, body []byte) error {
+	var e apiError
+	if json.Unmarshal(body, &e) == nil && e.Error.Message != "" {
+		return fmt.Errorf("%w: %d %s", ErrRequestFailed, status, e.Error.Message)
+	}
+	return fmt.Errorf("%w: %d %s", ErrRequestFailed, status, http.StatusText(status))
+}
@@ -0,0 +1,238 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testReply 模型按系统提示返回的完整回复
+const testReply = `{"content": "The passage explains tides.", "summary": "Tides", "keywords": ["moon"], "confidence": 0.8}`
+
+// testRequest 测试用的分析请求
+var testRequest = AnalysisRequest{Text: "The moon pulls the sea.", AnalysisType: AnalysisTypeExplain}
+
+// recordedRequests 测试服务收到的请求体，按到达顺序保存
+type recordedRequests struct {
+	mu     sync.Mutex
+	bodies []map[string]interface{}
+}
+
+// record 解析并保存请求体
+func (r *recordedRequests) record(t *testing.T, req *http.Request) map[string]interface{} {
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Error(err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Errorf("request body is not JSON: %v", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, body)
+	return body
+}
+
+// all 收到的所有请求体
+func (r *recordedRequests) all() []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]interface{}(nil), r.bodies...)
+}
+
+// splitReply 把回复切成若干段，模拟流式输出
+func splitReply(reply string, size int) []string {
+	var parts []string
+	runes := []rune(reply)
+	for len(runes) > size {
+		parts = append(parts, string(runes[:size]))
+		runes = runes[size:]
+	}
+	return append(parts, string(runes))
+}
+
+// writeOpenAIStream 以Server-Sent Events写出回复，usage为true时最后一段带token用量
+func writeOpenAIStream(w http.ResponseWriter, reply string, usage bool) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	for _, part := range splitReply(reply, 7) {
+		data, _ := json.Marshal(map[string]interface{}{
+			"id":      "chatcmpl-1",
+			"model":   "test-model",
+			"choices": []interface{}{map[string]interface{}{"delta": map[string]string{"content": part}}},
+		})
+		fmt.Fprintf(w, "data: %s\n\n", data)
+	}
+	if usage {
+		fmt.Fprint(w, `data: {"id":"chatcmpl-1","model":"test-model","choices":[],"usage":{"prompt_tokens":12,"completion_tokens":30,"total_tokens":42}}`+"\n\n")
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// writeOpenAICompletion 写出非流式的回复
+func writeOpenAICompletion(w http.ResponseWriter, reply string) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":      "chatcmpl-2",
+		"model":   "test-model",
+		"choices": []interface{}{map[string]interface{}{"message": map[string]string{"role": "assistant", "content": reply}}},
+		"usage":   map[string]int{"prompt_tokens": 10, "completion_tokens": 20, "total_tokens": 30},
+	})
+}
+
+// newTestOpenAI 连接测试服务的提供者
+func newTestOpenAI(url string) *OpenAIProvider {
+	config := DefaultOpenAIConfig()
+	config.BaseURL = url
+	config.APIKey = "test-key"
+	return NewOpenAIProvider(config)
+}
+
+func TestOpenAIStream(t *testing.T) {
+	var requests recordedRequests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		requests.record(t, r)
+		writeOpenAIStream(w, testReply, true)
+	}))
+	defer server.Close()
+
+	ch, err := newTestOpenAI(server.URL).AnalyzeTextStream(context.Background(), testRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deltas, last := collectStream(t, ch)
+	if deltas != testReply {
+		t.Fatalf("deltas = %q, want the whole reply", deltas)
+	}
+	if last.Result.Content != "The passage explains tides." || last.Result.Summary != "Tides" {
+		t.Fatalf("result = %+v", last.Result)
+	}
+	if last.Result.ID != "chatcmpl-1" || last.Result.Metadata[MetadataTotalTokens] != 42 {
+		t.Fatalf("id %q, metadata %v", last.Result.ID, last.Result.Metadata)
+	}
+
+	body := requests.all()[0]
+	if body["stream"] != true || body["stream_options"] == nil || body["response_format"] == nil {
+		t.Fatalf("request = %v, want stream, stream_options and response_format", body)
+	}
+}
+
+func TestOpenAIStreamWithoutUsage(t *testing.T) {
+	var requests recordedRequests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.record(t, r)
+		writeOpenAIStream(w, testReply, false)
+	}))
+	defer server.Close()
+
+	provider := newTestOpenAI(server.URL)
+	provider.config.StreamUsage = false
+	ch, err := provider.AnalyzeTextStream(context.Background(), testRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, last := collectStream(t, ch)
+	if _, ok := requests.all()[0]["stream_options"]; ok {
+		t.Fatal("stream_options sent with StreamUsage off")
+	}
+	if last.Result.Metadata[MetadataCompletionTokens] != estimateTokens(testReply) {
+		t.Fatalf("metadata = %v, want estimated completion tokens", last.Result.Metadata)
+	}
+}
+
+func TestOpenAIStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"{\\\"content\\\": \\\"par\"}}]}\n\n")
+		fmt.Fprint(w, "data: {\"error\":{\"message\":\"overloaded\"}}\n\n")
+	}))
+	defer server.Close()
+
+	ch, err := newTestOpenAI(server.URL).AnalyzeTextStream(context.Background(), testRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last AnalysisChunk
+	for chunk := range ch {
+		last = chunk
+	}
+	if !errors.Is(last.Err, ErrRequestFailed) || !strings.Contains(last.Err.Error(), "overloaded") {
+		t.Fatalf("error = %v, want the error event", last.Err)
+	}
+}
+
+func TestOpenAIRetriesWithoutOptionalFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		wantCalls int
+		wantErr   bool
+	}{
+		{"stream_options rejected", "Unrecognized request argument supplied: stream_options", 2, false},
+		{"response_format rejected", "response_format.type json_schema is not supported", 2, false},
+		{"other bad request", "messages must not be empty", 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests recordedRequests
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body := requests.record(t, r)
+				_, options := body["stream_options"]
+				_, format := body["response_format"]
+				if options || format || tt.wantErr {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprintf(w, `{"error":{"message":%q}}`, tt.message)
+					return
+				}
+				if body["stream"] != true {
+					writeOpenAICompletion(w, testReply)
+					return
+				}
+				writeOpenAIStream(w, testReply, false)
+			}))
+			defer server.Close()
+
+			provider := newTestOpenAI(server.URL)
+			ch, err := provider.AnalyzeTextStream(context.Background(), testRequest)
+			if tt.wantErr {
+				if !errors.Is(err, ErrRequestFailed) || !strings.Contains(err.Error(), tt.message) {
+					t.Fatalf("error = %v, want the server message", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				collectStream(t, ch)
+			}
+			if got := len(requests.all()); got != tt.wantCalls {
+				t.Fatalf("requests = %d, want %d", got, tt.wantCalls)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			// 之后的请求直接省略可选字段
+			if _, err := provider.AnalyzeText(context.Background(), testRequest); err != nil {
+				t.Fatal(err)
+			}
+			bodies := requests.all()
+			if len(bodies) != tt.wantCalls+1 {
+				t.Fatalf("requests = %d, want one more without retry", len(bodies))
+			}
+			if _, ok := bodies[len(bodies)-1]["response_format"]; ok {
+				t.Fatal("response_format sent after the server rejected it")
+			}
+		})
+	}
+}
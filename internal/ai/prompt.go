@@ -0,0 +1,20 @@
+package ai
+
+// systemPrompt 各提供者共用的系统提示，要求以JSON返回分析结果
+const systemPrompt = `You are a reading assistant that helps readers understand the passage they selected.
+Reply with a single JSON object and nothing else, using these fields:
+{"content": "the analysis in Markdown", "summary": "one or two sentences",
+ "keywords": ["..."], "concepts": [{"name": "...", "definition": "...", "category": "...", "importance": 0.0}],
+ "references": [{"title": "...", "url": "...", "description": "...", "type": "web|book|paper"}],
+ "confidence": 0.0}
+importance and confidence are between 0 and 1. Leave out fields you have nothing for.`
+
+// SupportedAnalysisTypes 提示模板支持的分析类型，包括用户增加的类型
+func SupportedAnalysisTypes() []string {
+	return DefaultTemplates().Types()
+}
+
+// buildPrompt 用分析类型的模板生成用户提示，未知的分析类型按解释处理
+func buildPrompt(request AnalysisRequest) (string, error) {
+	return DefaultTemplates().Render(request)
+}
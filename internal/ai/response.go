@@ -0,0 +1,330 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// maxCorrections 回复不符合要求时最多请模型更正的次数
+const maxCorrections = 1
+
+// structuredResponse 按系统提示要求返回的JSON结构
+type structuredResponse struct {
+	Content    string      `json:"content"`
+	Summary    string      `json:"summary"`
+	Keywords   []string    `json:"keywords"`
+	Concepts   []Concept   `json:"concepts"`
+	References []Reference `json:"references"`
+	Confidence float32     `json:"confidence"`
+}
+
+// analysisSchema 结构化回复的JSON Schema，发送给支持按Schema约束输出的服务
+var analysisSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "content": {"type": "string"},
+    "summary": {"type": "string"},
+    "keywords": {"type": "array", "items": {"type": "string"}},
+    "concepts": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "definition": {"type": "string"},
+          "category": {"type": "string"},
+          "importance": {"type": "number", "minimum": 0, "maximum": 1}
+        },
+        "required": ["name", "definition", "category", "importance"],
+        "additionalProperties": false
+      }
+    },
+    "references": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "title": {"type": "string"},
+          "url": {"type": "string"},
+          "description": {"type": "string"},
+          "type": {"type": "string", "enum": ["web", "book", "paper"]}
+        },
+        "required": ["title", "url", "description", "type"],
+        "additionalProperties": false
+      }
+    },
+    "confidence": {"type": "number", "minimum": 0, "maximum": 1}
+  },
+  "required": ["content", "summary", "keywords", "concepts", "references", "confidence"],
+  "additionalProperties": false
+}`)
+
+// referenceTypes 参考资料允许的类型
+var referenceTypes = map[string]bool{"web": true, "book": true, "paper": true}
+
+// correctFunc 在原来的对话之后追加消息再次请求，返回模型的回复
+type correctFunc func(ctx context.Context, turns []chatMessage) (string, error)
+
+// structuredResult 解析模型的回复。回复不是JSON或字段不符合要求时把问题告诉模型，请它更正，
+// 最多 maxCorrections 次；仍有问题或更正请求失败时使用能从回复中取出的部分。只有ctx结束时返回错误
+func structuredResult(ctx context.Context, reply string, correct correctFunc) (*AnalysisResult, error) {
+	result, problems := parseResponse(reply)
+
+	var turns []chatMessage
+	for i := 0; i < maxCorrections && len(problems) > 0; i++ {
+		turns = append(turns,
+			chatMessage{Role: "assistant", Content: reply},
+			chatMessage{Role: "user", Content: correctionPrompt(problems)},
+		)
+		corrected, err := correct(ctx, turns)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			break
+		}
+		// 更正后问题没有减少时保留原来的回复
+		candidate, remaining := parseResponse(corrected)
+		if len(remaining) > 0 && len(remaining) >= len(problems) {
+			break
+		}
+		reply, result, problems = corrected, candidate, remaining
+	}
+	return result, nil
+}
+
+// correctionPrompt 请模型更正回复的消息
+func correctionPrompt(problems []string) string {
+	var b strings.Builder
+	b.WriteString("Your reply could not be used:\n")
+	for _, p := range problems {
+		b.WriteString("- " + p + "\n")
+	}
+	b.WriteString("Reply again with only the corrected JSON object in the format described in the instructions.")
+	return b.String()
+}
+
+// parseResponse 把模型的回复解析为分析结果，同时返回回复不符合要求的地方。
+// 去掉代码块标记和JSON前后的文字，修复多余的逗号和字符串中未转义的换行；
+// 不合要求的字段被修正或丢弃。回复不是JSON时整段作为正文
+func parseResponse(text string) (*AnalysisResult, []string) {
+	text = strings.TrimSpace(text)
+	result := &AnalysisResult{Content: text}
+
+	object, ok := extractObject(stripCodeFence(text))
+	if !ok {
+		return result, []string{"the reply is not a JSON object"}
+	}
+
+	var problems []string
+	var structured structuredResponse
+	if err := json.Unmarshal([]byte(repairJSON(object)), &structured); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			// 无法解析时尽量取出正文，例如回复被截断
+			if content := partialContent(object); content != "" {
+				result.Content = content
+			}
+			return result, []string{"the reply is not valid JSON: " + err.Error()}
+		}
+		// 类型不符的字段被跳过，其余字段照常解析
+		problems = append(problems, fmt.Sprintf("%s must be a %s, not a JSON %s", typeErr.Field, typeName(typeErr.Type.Kind().String()), typeErr.Value))
+	}
+
+	problems = append(problems, validateResponse(&structured)...)
+	result.Content = structured.Content
+	if result.Content == "" {
+		result.Content = structured.Summary
+	}
+	result.Summary = structured.Summary
+	result.Keywords = structured.Keywords
+	result.Concepts = structured.Concepts
+	result.References = structured.References
+	result.Confidence = structured.Confidence
+	return result, problems
+}
+
+// typeName 字段类型在提示中的名称
+func typeName(kind string) string {
+	switch kind {
+	case "float32", "float64", "int":
+		return "number"
+	case "slice":
+		return "array"
+	case "struct":
+		return "object"
+	}
+	return kind
+}
+
+// validateResponse 检查各字段，修正超出范围的数值，丢弃缺少名称的概念和缺少标题的参考资料。
+// 返回发现的问题
+func validateResponse(r *structuredResponse) []string {
+	var problems []string
+	if strings.TrimSpace(r.Content) == "" && strings.TrimSpace(r.Summary) == "" {
+		problems = append(problems, "content is empty")
+	}
+	if r.Confidence < 0 || r.Confidence > 1 {
+		problems = append(problems, fmt.Sprintf("confidence must be between 0 and 1, got %g", r.Confidence))
+		r.Confidence = clampUnit(r.Confidence)
+	}
+
+	keywords := r.Keywords[:0]
+	seen := make(map[string]bool)
+	for _, k := range r.Keywords {
+		k = strings.TrimSpace(k)
+		if k != "" && !seen[strings.ToLower(k)] {
+			seen[strings.ToLower(k)] = true
+			keywords = append(keywords, k)
+		}
+	}
+	r.Keywords = keywords
+
+	concepts := r.Concepts[:0]
+	for i, c := range r.Concepts {
+		c.Name = strings.TrimSpace(c.Name)
+		if c.Name == "" {
+			problems = append(problems, fmt.Sprintf("concepts[%d].name is empty", i))
+			continue
+		}
+		if c.Importance < 0 || c.Importance > 1 {
+			problems = append(problems, fmt.Sprintf("concepts[%d].importance must be between 0 and 1, got %g", i, c.Importance))
+			c.Importance = clampUnit(c.Importance)
+		}
+		concepts = append(concepts, c)
+	}
+	r.Concepts = concepts
+
+	references := r.References[:0]
+	for i, ref := range r.References {
+		ref.Title = strings.TrimSpace(ref.Title)
+		if ref.Title == "" {
+			problems = append(problems, fmt.Sprintf("references[%d].title is empty", i))
+			continue
+		}
+		if ref.URL = strings.TrimSpace(ref.URL); ref.URL != "" {
+			if u, err := url.Parse(ref.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				problems = append(problems, fmt.Sprintf("references[%d].url %q is not an http or https URL", i, ref.URL))
+				ref.URL = ""
+			}
+		}
+		if ref.Type = strings.ToLower(strings.TrimSpace(ref.Type)); ref.Type != "" && !referenceTypes[ref.Type] {
+			problems = append(problems, fmt.Sprintf("references[%d].type must be web, book or paper, got %q", i, ref.Type))
+			ref.Type = ""
+		}
+		references = append(references, ref)
+	}
+	r.References = references
+	return problems
+}
+
+// stripCodeFence 去掉包裹回复的Markdown代码块标记，代码块前后有文字时取第一个代码块的内容
+func stripCodeFence(text string) string {
+	start := strings.Index(text, "```")
+	if start < 0 || strings.IndexByte(text[:start], '{') >= 0 {
+		// 代码块标记出现在JSON之后时是正文中的代码
+		return text
+	}
+	body := text[start+3:]
+	// 去掉语言标注，如 ```json
+	if i := strings.IndexByte(body, '\n'); i >= 0 {
+		body = body[i+1:]
+	} else {
+		return text
+	}
+	if end := strings.Index(body, "```"); end >= 0 {
+		body = body[:end]
+	}
+	return strings.TrimSpace(body)
+}
+
+// extractObject 取出文本中第一个JSON对象，忽略前后的文字。对象没有结束时取到文本末尾
+func extractObject(text string) (string, bool) {
+	start := strings.IndexByte(text, '{')
+	if start < 0 {
+		return "", false
+	}
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				return text[start : i+1], true
+			}
+		}
+	}
+	return text[start:], true
+}
+
+// repairJSON 修复模型常见的格式错误：对象和数组末尾多余的逗号，字符串中未转义的换行和制表符
+func repairJSON(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	inString, escaped := false, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			case c == '\n':
+				b.WriteString(`\n`)
+				continue
+			case c == '\r':
+				continue
+			case c == '\t':
+				b.WriteString(`\t`)
+				continue
+			}
+			b.WriteByte(c)
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case ',':
+			// 下一个非空白字符是结束括号时去掉逗号
+			j := i + 1
+			for j < len(text) && strings.IndexByte(" \t\r\n", text[j]) >= 0 {
+				j++
+			}
+			if j < len(text) && (text[j] == '}' || text[j] == ']') {
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// clampUnit 把数值限制在0-1之间
+func clampUnit(v float32) float32 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
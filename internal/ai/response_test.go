@@ -0,0 +1,188 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"valid", `{"a": [1, 2]}`, `{"a": [1, 2]}`},
+		{"trailing comma in object", `{"a": 1,}`, `{"a": 1}`},
+		{"trailing comma in array", "{\"a\": [1, 2,\n ]}", "{\"a\": [1, 2\n ]}"},
+		{"comma inside string", `{"a": "x,}"}`, `{"a": "x,}"}`},
+		{"raw newline in string", "{\"a\": \"line one\r\nline two\"}", `{"a": "line one\nline two"}`},
+		{"raw tab in string", "{\"a\": \"x\ty\"}", `{"a": "x\ty"}`},
+		{"escaped quote", `{"a": "say \"hi\",", "b": 1,}`, `{"a": "say \"hi\",", "b": 1}`},
+		{"newline outside string", "{\n\"a\": 1\n}", "{\n\"a\": 1\n}"},
+	}
+	for _, tt := range tests {
+		got := repairJSON(tt.in)
+		if got != tt.want {
+			t.Errorf("%s: repairJSON(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+		if !json.Valid([]byte(got)) {
+			t.Errorf("%s: repaired text %q is not valid JSON", tt.name, got)
+		}
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		reply        string
+		wantContent  string
+		wantSummary  string
+		wantKeywords []string
+		wantProblems []string // 每个问题中应出现的文字
+	}{
+		{
+			name:         "valid",
+			reply:        testReply,
+			wantContent:  "The passage explains tides.",
+			wantSummary:  "Tides",
+			wantKeywords: []string{"moon"},
+		},
+		{
+			name:         "code fence and surrounding text",
+			reply:        "Here you go:\n```json\n{\"content\": \"c\", \"keywords\": [\"a\", \"A\", \" \"],}\n```\nHope it helps",
+			wantContent:  "c",
+			wantKeywords: []string{"a"},
+		},
+		{
+			name:         "not json",
+			reply:        "  Just an explanation.  ",
+			wantContent:  "Just an explanation.",
+			wantProblems: []string{"not a JSON object"},
+		},
+		{
+			name:         "truncated",
+			reply:        `{"content": "cut off mid`,
+			wantContent:  "cut off mid",
+			wantProblems: []string{"not valid JSON"},
+		},
+		{
+			name:        "summary used when content is empty",
+			reply:       `{"summary": "only a summary"}`,
+			wantContent: "only a summary",
+			wantSummary: "only a summary",
+		},
+		{
+			name:         "empty",
+			reply:        `{"keywords": ["x"]}`,
+			wantKeywords: []string{"x"},
+			wantProblems: []string{"content is empty"},
+		},
+		{
+			name:         "wrong type",
+			reply:        `{"content": "c", "keywords": "one, two"}`,
+			wantContent:  "c",
+			wantProblems: []string{"keywords must be a array"},
+		},
+		{
+			name:        "invalid fields",
+			reply:       `{"content": "c", "confidence": 1.5, "concepts": [{"name": ""}, {"name": "Tide", "importance": -1}], "references": [{"title": "T", "url": "ftp://x", "type": "video"}, {"title": ""}]}`,
+			wantContent: "c",
+			wantProblems: []string{
+				"confidence must be between 0 and 1",
+				"concepts[0].name is empty",
+				"concepts[1].importance",
+				"references[0].url",
+				"references[0].type",
+				"references[1].title is empty",
+			},
+		},
+	}
+	for _, tt := range tests {
+		result, problems := parseResponse(tt.reply)
+		if result.Content != tt.wantContent || result.Summary != tt.wantSummary {
+			t.Errorf("%s: content %q, summary %q, want %q, %q", tt.name, result.Content, result.Summary, tt.wantContent, tt.wantSummary)
+		}
+		if strings.Join(result.Keywords, ",") != strings.Join(tt.wantKeywords, ",") {
+			t.Errorf("%s: keywords = %q, want %q", tt.name, result.Keywords, tt.wantKeywords)
+		}
+		if len(problems) != len(tt.wantProblems) {
+			t.Errorf("%s: problems = %q, want %d", tt.name, problems, len(tt.wantProblems))
+			continue
+		}
+		for i, want := range tt.wantProblems {
+			if !strings.Contains(problems[i], want) {
+				t.Errorf("%s: problem %q does not mention %q", tt.name, problems[i], want)
+			}
+		}
+	}
+}
+
+func TestParseResponseFixesFields(t *testing.T) {
+	result, _ := parseResponse(`{"content": "c", "confidence": 1.5, "concepts": [{"name": ""}, {"name": " Tide ", "importance": -1}], "references": [{"title": "T", "url": "ftp://x", "type": "Video"}, {"title": "B", "url": "https://example.com", "type": "Book"}]}`)
+	if result.Confidence != 1 {
+		t.Errorf("confidence = %v, want 1", result.Confidence)
+	}
+	if len(result.Concepts) != 1 || result.Concepts[0].Name != "Tide" || result.Concepts[0].Importance != 0 {
+		t.Errorf("concepts = %+v", result.Concepts)
+	}
+	if len(result.References) != 2 || result.References[0].URL != "" || result.References[0].Type != "" {
+		t.Errorf("references = %+v", result.References)
+	}
+	if result.References[1].Type != "book" || result.References[1].URL != "https://example.com" {
+		t.Errorf("references[1] = %+v", result.References[1])
+	}
+}
+
+func TestStructuredResultCorrection(t *testing.T) {
+	errBroken := errors.New("connection reset")
+	tests := []struct {
+		name        string
+		reply       string
+		corrected   string
+		correctErr  error
+		wantCalls   int
+		wantContent string
+	}{
+		{"valid reply", testReply, "", nil, 0, "The passage explains tides."},
+		{"corrected", "Tides are caused by the moon.", testReply, nil, 1, "The passage explains tides."},
+		{"correction no better", "Plain answer", "Still plain", nil, 1, "Plain answer"},
+		{"correction fails", "Plain answer", "", errBroken, 1, "Plain answer"},
+		{"correction with fewer problems", `{"content": "c", "confidence": 3, "keywords": 1}`, `{"content": "fixed", "confidence": 3}`, nil, 1, "fixed"},
+	}
+	for _, tt := range tests {
+		calls := 0
+		var turns []chatMessage
+		correct := func(ctx context.Context, history []chatMessage) (string, error) {
+			calls++
+			turns = history
+			return tt.corrected, tt.correctErr
+		}
+		result, err := structuredResult(context.Background(), tt.reply, correct)
+		if err != nil {
+			t.Fatalf("%s: error = %v", tt.name, err)
+		}
+		if calls != tt.wantCalls {
+			t.Errorf("%s: corrections = %d, want %d", tt.name, calls, tt.wantCalls)
+		}
+		if result.Content != tt.wantContent {
+			t.Errorf("%s: content = %q, want %q", tt.name, result.Content, tt.wantContent)
+		}
+		if calls > 0 && (len(turns) != 2 || turns[0].Role != "assistant" || turns[0].Content != tt.reply || turns[1].Role != "user") {
+			t.Errorf("%s: correction turns = %+v", tt.name, turns)
+		}
+	}
+}
+
+func TestStructuredResultCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	correct := func(ctx context.Context, turns []chatMessage) (string, error) {
+		cancel()
+		return "", ctx.Err()
+	}
+	if _, err := structuredResult(ctx, "not json", correct); !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,356 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Service AI服务管理器，按注册顺序维护提供者。
+// 分析时先使用指定或默认的提供者，不可用或失败时依次尝试其余提供者。
+// 设置了缓存时先查找每个提供者的缓存结果，命中时不再请求提供者
+type Service struct {
+	mu              sync.RWMutex
+	providers       []AIProvider
+	defaultProvider string
+	localOnly       bool // 只允许文本在本机处理，云端提供者不会被使用
+	cache           AnalysisCache
+}
+
+// NewService 创建AI服务管理器
+func NewService() *Service {
+	return &Service{}
+}
+
+// RegisterProvider 注册AI提供者，同名提供者会被替换。第一个注册的提供者成为默认提供者
+func (s *Service) RegisterProvider(provider AIProvider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := provider.GetName()
+	for i, p := range s.providers {
+		if p.GetName() == name {
+			s.providers[i] = provider
+			return
+		}
+	}
+	s.providers = append(s.providers, provider)
+	if s.defaultProvider == "" {
+		s.defaultProvider = name
+	}
+}
+
+// GetProvider 获取AI提供者，未注册时返回nil
+func (s *Service) GetProvider(name string) AIProvider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.find(name)
+}
+
+// GetAvailableProviders 获取当前可用的AI提供者，按注册顺序排列。只允许本地处理时不包括云端提供者
+func (s *Service) GetAvailableProviders() []AIProvider {
+	var available []AIProvider
+	for _, p := range s.chain("") {
+		if p.IsAvailable() {
+			available = append(available, p)
+		}
+	}
+	return available
+}
+
+// SetDefaultProvider 设置默认提供者
+func (s *Service) SetDefaultProvider(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(name) == nil {
+		return fmt.Errorf("%w: %s", ErrProviderNotFound, name)
+	}
+	s.defaultProvider = name
+	return nil
+}
+
+// SetLocalOnly 设置是否只允许文本在本机处理
+func (s *Service) SetLocalOnly(localOnly bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.localOnly = localOnly
+}
+
+// SetCache 设置分析缓存，为nil时不使用缓存
+func (s *Service) SetCache(cache AnalysisCache) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cache = cache
+}
+
+// LocalOnly 是否只允许文本在本机处理
+func (s *Service) LocalOnly() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.localOnly
+}
+
+// DefaultProvider 获取默认提供者的名称，没有注册提供者时为空
+func (s *Service) DefaultProvider() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.defaultProvider
+}
+
+// AnalyzeText 分析文本（使用默认提供者）
+func (s *Service) AnalyzeText(ctx context.Context, request AnalysisRequest) (*AnalysisResult, error) {
+	return s.AnalyzeTextWithProvider(ctx, s.DefaultProvider(), request)
+}
+
+// AnalyzeTextWithProvider 使用指定提供者分析文本，不可用、不支持分析类型、失败或超时时依次改用其余提供者。
+// 所有提供者都失败时返回各提供者的错误；ctx被取消时不再回退，直接返回ctx的错误
+func (s *Service) AnalyzeTextWithProvider(ctx context.Context, providerName string, request AnalysisRequest) (*AnalysisResult, error) {
+	s.mu.RLock()
+	known := providerName == "" || s.find(providerName) != nil
+	s.mu.RUnlock()
+	if !known {
+		return nil, fmt.Errorf("%w: %s", ErrProviderNotFound, providerName)
+	}
+
+	var errs []error
+	for _, p := range s.chain(providerName) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cached, key := s.lookup(p, request)
+		if cached != nil {
+			return cached, nil
+		}
+		if !p.IsAvailable() {
+			continue
+		}
+		if !supports(p, request.AnalysisType) {
+			errs = append(errs, fmt.Errorf("%s: %w", p.GetName(), ErrUnsupportedAnalysisType))
+			continue
+		}
+
+		start := time.Now()
+		attempt, cancel := withProviderDeadline(ctx, p)
+		result, err := p.AnalyzeText(attempt, request)
+		err = attemptError(ctx, attempt, err)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", p.GetName(), err))
+			continue
+		}
+		result = finishResult(p.GetName(), request, result, start)
+		s.store(key, result)
+		return result, nil
+	}
+	return nil, s.chainError(errs)
+}
+
+// AnalyzeTextStream 使用默认提供者流式分析文本，回退规则与 AnalyzeText 相同。
+// 不支持流式输出的提供者在完成后一次性返回结果。开始输出后出错或超时不再回退，错误随最后一段返回；
+// ctx被取消时通道直接关闭
+func (s *Service) AnalyzeTextStream(ctx context.Context, request AnalysisRequest) (<-chan AnalysisChunk, error) {
+	var errs []error
+	for _, p := range s.chain(s.DefaultProvider()) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cached, key := s.lookup(p, request)
+		if cached != nil {
+			return completed(request, cached), nil
+		}
+		if !p.IsAvailable() {
+			continue
+		}
+		if !supports(p, request.AnalysisType) {
+			errs = append(errs, fmt.Errorf("%s: %w", p.GetName(), ErrUnsupportedAnalysisType))
+			continue
+		}
+
+		start := time.Now()
+		attempt, cancel := withProviderDeadline(ctx, p)
+		streaming, ok := p.(StreamingProvider)
+		if !ok {
+			result, err := p.AnalyzeText(attempt, request)
+			err = attemptError(ctx, attempt, err)
+			cancel()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, err
+				}
+				errs = append(errs, fmt.Errorf("%s: %w", p.GetName(), err))
+				continue
+			}
+			result = finishResult(p.GetName(), request, result, start)
+			s.store(key, result)
+			return completed(request, result), nil
+		}
+
+		in, err := streaming.AnalyzeTextStream(attempt, request)
+		if err != nil {
+			err = attemptError(ctx, attempt, err)
+			cancel()
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", p.GetName(), err))
+			continue
+		}
+		store := func(result *AnalysisResult) { s.store(key, result) }
+		return relayStream(ctx, attempt, cancel, p.GetName(), request, start, in, store), nil
+	}
+	return nil, s.chainError(errs)
+}
+
+// completed 已经得到完整结果时的流式输出，只有最后一段
+func completed(request AnalysisRequest, result *AnalysisResult) <-chan AnalysisChunk {
+	out := make(chan AnalysisChunk, 1)
+	out <- AnalysisChunk{RequestID: request.ID, Content: result.Content, Done: true, Result: result}
+	close(out)
+	return out
+}
+
+// lookup 查找提供者对请求的缓存结果，命中时返回标记为缓存的结果。
+// 同时返回缓存键，用于保存新的结果；没有设置缓存时键为空
+func (s *Service) lookup(provider AIProvider, request AnalysisRequest) (*AnalysisResult, string) {
+	s.mu.RLock()
+	cache := s.cache
+	s.mu.RUnlock()
+	if cache == nil {
+		return nil, ""
+	}
+
+	var model string
+	if m, ok := provider.(ModelProvider); ok {
+		model = m.ModelFor(request.AnalysisType)
+	}
+	key := cache.GetCacheKey(request, provider.GetName(), model)
+	result, ok := cache.Get(key)
+	if !ok {
+		return nil, key
+	}
+
+	// 处理时间记为读取缓存的时间
+	result.ProcessTime = 0
+	result = finishResult(provider.GetName(), request, result, time.Now())
+	result.Metadata[MetadataCached] = true
+	return result, key
+}
+
+// store 保存提供者给出的结果
+func (s *Service) store(key string, result *AnalysisResult) {
+	s.mu.RLock()
+	cache := s.cache
+	s.mu.RUnlock()
+	if cache != nil && key != "" {
+		cache.Set(key, result)
+	}
+}
+
+// supports 提供者是否支持请求的分析类型，未指定类型时按解释处理
+func supports(provider AIProvider, analysisType string) bool {
+	if analysisType == "" {
+		analysisType = AnalysisTypeExplain
+	}
+	for _, t := range provider.GetSupportedAnalysisTypes() {
+		if t == analysisType {
+			return true
+		}
+	}
+	return false
+}
+
+// withProviderDeadline 按提供者的时限为一次分析设置截止时间
+func withProviderDeadline(ctx context.Context, provider AIProvider) (context.Context, context.CancelFunc) {
+	if timed, ok := provider.(TimedProvider); ok && timed.Timeout() > 0 {
+		return context.WithTimeout(ctx, timed.Timeout())
+	}
+	return context.WithCancel(ctx)
+}
+
+// attemptError 整理一次分析的错误：调用方取消时返回ctx的错误，
+// 超过提供者的时限时返回 ErrTimeout，其余错误原样返回
+func attemptError(ctx, attempt context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if errors.Is(attempt.Err(), context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return err
+}
+
+// chainError 所有提供者都没有给出结果时的错误
+func (s *Service) chainError(errs []error) error {
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if s.LocalOnly() {
+		return ErrNoLocalProvider
+	}
+	return ErrNoProviderAvailable
+}
+
+// finishResult 补全提供者没有填写的处理时间和类型，并记录所属的请求和给出结果的提供者
+func finishResult(provider string, request AnalysisRequest, result *AnalysisResult, start time.Time) *AnalysisResult {
+	if result.ProcessTime == 0 {
+		result.ProcessTime = time.Since(start).Milliseconds()
+	}
+	if result.Type == "" {
+		result.Type = request.AnalysisType
+	}
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.RequestID = request.ID
+	result.Metadata[MetadataProvider] = provider
+	return result
+}
+
+// chain 分析时尝试提供者的顺序：先是first，然后按注册顺序排列其余提供者。
+// 只允许本地处理时不包括云端提供者
+func (s *Service) chain(first string) []AIProvider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ordered := make([]AIProvider, 0, len(s.providers))
+	if p := s.find(first); p != nil {
+		ordered = append(ordered, p)
+	}
+	for _, p := range s.providers {
+		if p.GetName() != first {
+			ordered = append(ordered, p)
+		}
+	}
+
+	chain := ordered[:0]
+	for _, p := range ordered {
+		if !s.localOnly || isLocal(p) {
+			chain = append(chain, p)
+		}
+	}
+	return chain
+}
+
+// find 按名称查找提供者，调用方需持有锁
+func (s *Service) find(name string) AIProvider {
+	for _, p := range s.providers {
+		if p.GetName() == name {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// streamBuffer 流式通道的缓冲大小
+const streamBuffer = 16
+
+// maxStreamLine 流式响应中单行的最大长度
+const maxStreamLine = 1 << 20
+
+// relayStream 转发提供者的输出，累计可以显示的正文和token数，并补全最后的结果，完整的结果交给store保存。
+// attempt是这次分析带截止时间的context，转发结束后由cancel释放；
+// 提供者因超时停止输出时补发一段 ErrTimeout，ctx被取消时直接关闭通道
+func relayStream(ctx, attempt context.Context, cancel context.CancelFunc, provider string, request AnalysisRequest, start time.Time, in <-chan AnalysisChunk, store func(*AnalysisResult)) <-chan AnalysisChunk {
+	out := make(chan AnalysisChunk, streamBuffer)
+	go func() {
+		defer close(out)
+		defer cancel()
+
+		var raw strings.Builder
+		finished := false
+		for chunk := range in {
+			raw.WriteString(chunk.Delta)
+			chunk.RequestID = request.ID
+			chunk.Content = partialContent(raw.String())
+			chunk.Tokens = estimateTokens(raw.String())
+			if chunk.Result != nil {
+				chunk.Result = finishResult(provider, request, chunk.Result, start)
+				chunk.Content = chunk.Result.Content
+				store(chunk.Result)
+			}
+			chunk.Err = attemptError(ctx, attempt, chunk.Err)
+			finished = chunk.Done || chunk.Err != nil
+			if !emit(ctx, out, chunk) {
+				return
+			}
+		}
+		if finished || ctx.Err() != nil {
+			return
+		}
+
+		err := attemptError(ctx, attempt, attempt.Err())
+		if err == nil {
+			err = fmt.Errorf("%w: stream ended early", ErrInvalidResponse)
+		}
+		emit(ctx, out, AnalysisChunk{
+			RequestID: request.ID,
+			Content:   partialContent(raw.String()),
+			Tokens:    estimateTokens(raw.String()),
+			Err:       err,
+		})
+	}()
+	return out
+}
+
+// emit 提供者向通道发送一段输出，调用方已放弃时返回false
+func emit(ctx context.Context, out chan<- AnalysisChunk, chunk AnalysisChunk) bool {
+	select {
+	case out <- chunk:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// readLines 逐行读取流式响应，fn返回false时停止
+func readLines(r io.Reader, fn func(line string) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxStreamLine)
+	for scanner.Scan() {
+		if !fn(scanner.Text()) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// readSSE 读取Server-Sent Events，对每个事件调用fn，fn返回false时停止
+func readSSE(r io.Reader, fn func(event, data string) bool) error {
+	var event string
+	var data []string
+	return readLines(r, func(line string) bool {
+		switch {
+		case line == "":
+			if len(data) == 0 {
+				return true
+			}
+			ok := fn(event, strings.Join(data, "\n"))
+			event, data = "", nil
+			return ok
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		return true
+	})
+}
+
+// partialContent 从尚未完整的输出中取出可以显示的正文。
+// 输出是按系统提示要求的JSON时取content字段已经输出的部分，否则原样显示
+func partialContent(raw string) string {
+	text := strings.TrimSpace(raw)
+	if strings.HasPrefix(text, "```") {
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			text = strings.TrimSpace(text[i+1:])
+		} else {
+			return ""
+		}
+	}
+	if !strings.HasPrefix(text, "{") {
+		return raw
+	}
+
+	key := strings.Index(text, `"content"`)
+	if key < 0 {
+		return ""
+	}
+	rest := strings.TrimLeft(text[key+len(`"content"`):], " \t\r\n")
+	if !strings.HasPrefix(rest, ":") {
+		return ""
+	}
+	rest = strings.TrimLeft(rest[1:], " \t\r\n")
+	if !strings.HasPrefix(rest, `"`) {
+		return ""
+	}
+	return decodePartialString(rest[1:])
+}
+
+// decodePartialString 解码JSON字符串已经输出的部分，遇到结束引号或不完整的转义时停止
+func decodePartialString(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"':
+			return b.String()
+		case c != '\\':
+			r, size := utf8.DecodeRuneInString(s[i:])
+			b.WriteRune(r)
+			i += size
+			continue
+		}
+
+		if i+1 >= len(s) {
+			return b.String()
+		}
+		switch s[i+1] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+		case 'u':
+			if i+6 > len(s) {
+				return b.String()
+			}
+			code, err := strconv.ParseUint(s[i+2:i+6], 16, 32)
+			if err != nil {
+				return b.String()
+			}
+			b.WriteRune(rune(code))
+			i += 6
+			continue
+		default:
+			// \" \\ \/ 等转义为字符本身
+			b.WriteByte(s[i+1])
+		}
+		i += 2
+	}
+	return b.String()
+}
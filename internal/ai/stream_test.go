@@ -0,0 +1,154 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+// collectStream 读完提供者的输出，返回拼接的增量和最后一段
+func collectStream(t *testing.T, ch <-chan AnalysisChunk) (string, AnalysisChunk) {
+	t.Helper()
+	var deltas strings.Builder
+	var last AnalysisChunk
+	for chunk := range ch {
+		deltas.WriteString(chunk.Delta)
+		last = chunk
+	}
+	if last.Err != nil {
+		t.Fatalf("stream error: %v", last.Err)
+	}
+	if !last.Done || last.Result == nil {
+		t.Fatalf("stream ended without a result: %+v", last)
+	}
+	return deltas.String(), last
+}
+
+func TestReadSSE(t *testing.T) {
+	type sseEvent struct{ event, data string }
+	tests := []struct {
+		name  string
+		input string
+		stop  int // 读到第几个事件时停止，0表示读完
+		want  []sseEvent
+	}{
+		{
+			name:  "events and data",
+			input: "event: ping\ndata: one\n\ndata: two\n\n",
+			want:  []sseEvent{{"ping", "one"}, {"", "two"}},
+		},
+		{
+			name:  "multi-line data",
+			input: "data: first\ndata: second\n\n",
+			want:  []sseEvent{{"", "first\nsecond"}},
+		},
+		{
+			name:  "comments and empty events",
+			input: ": keep-alive\n\n\n\ndata:tight\n\n",
+			want:  []sseEvent{{"", "tight"}},
+		},
+		{
+			name:  "crlf line endings",
+			input: "event: a\r\ndata: b\r\n\r\n",
+			want:  []sseEvent{{"a", "b"}},
+		},
+		{
+			name:  "event type resets",
+			input: "event: a\ndata: 1\n\ndata: 2\n\n",
+			want:  []sseEvent{{"a", "1"}, {"", "2"}},
+		},
+		{
+			name:  "unterminated event",
+			input: "data: 1\n\ndata: 2",
+			want:  []sseEvent{{"", "1"}},
+		},
+		{
+			name:  "stop early",
+			input: "data: 1\n\ndata: 2\n\ndata: 3\n\n",
+			stop:  2,
+			want:  []sseEvent{{"", "1"}, {"", "2"}},
+		},
+	}
+	for _, tt := range tests {
+		var got []sseEvent
+		err := readSSE(strings.NewReader(tt.input), func(event, data string) bool {
+			got = append(got, sseEvent{event, data})
+			return tt.stop == 0 || len(got) < tt.stop
+		})
+		if err != nil {
+			t.Fatalf("%s: error = %v", tt.name, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: events = %q, want %q", tt.name, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: event %d = %q, want %q", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	long := strings.Repeat("x", 200*1024)
+	var got []string
+	err := readLines(strings.NewReader("a\n"+long+"\nb\nc\n"), func(line string) bool {
+		got = append(got, line)
+		return line != "b"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0] != "a" || got[1] != long || got[2] != "b" {
+		t.Fatalf("read %d lines, want a, the long line and b", len(got))
+	}
+
+	tooLong := strings.Repeat("x", maxStreamLine+1)
+	if err := readLines(strings.NewReader(tooLong), func(string) bool { return true }); err == nil {
+		t.Fatal("line over the limit: error = nil")
+	}
+}
+
+func TestPartialContent(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"plain text", "Just prose", "Just prose"},
+		{"no content yet", `{"summary": "s"`, ""},
+		{"key without value", `{"content"`, ""},
+		{"colon without string", `{"content": `, ""},
+		{"open string", `{"content": "Hel`, "Hel"},
+		{"closed string", `{"content": "Done", "summary": "more`, "Done"},
+		{"whitespace around colon", "{\n  \"content\" :\n \"x\"", "x"},
+		{"escapes", `{"content": "a\nb\tc\"d\\e\/f\r"`, "a\nb\tc\"d\\e/f"},
+		{"split escape", `{"content": "line\`, "line"},
+		{"unicode escape", `{"content": "\u4e2d\u6587"`, "中文"},
+		{"split unicode escape", `{"content": "a\u4e`, "a"},
+		{"invalid unicode escape", `{"content": "a\uzzzzb"`, "a"},
+		{"multibyte text", `{"content": "中文内容`, "中文内容"},
+		{"code fence", "```json\n{\"content\": \"fenced", "fenced"},
+		{"bare code fence", "```json", ""},
+	}
+	for _, tt := range tests {
+		if got := partialContent(tt.raw); got != tt.want {
+			t.Errorf("%s: partialContent(%q) = %q, want %q", tt.name, tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestPartialContentGrowsWithStream(t *testing.T) {
+	raw := `{"content": "café \"ok\"\nend", "summary": "s"}`
+	var previous string
+	// 增量是完整的JSON字符串解码出来的，按rune切分
+	for i := range raw + " " {
+		got := partialContent(raw[:i])
+		if !strings.HasPrefix(got, previous) {
+			t.Fatalf("after %d bytes content %q does not extend %q", i, got, previous)
+		}
+		previous = got
+	}
+	if want := "café \"ok\"\nend"; previous != want {
+		t.Fatalf("final content = %q, want %q", previous, want)
+	}
+}
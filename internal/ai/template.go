@@ -0,0 +1,311 @@
+package ai
+
+import (
+	"ai-reader/pkg/document"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// TemplateExt 用户提示模板文件的扩展名，文件名（不含扩展名）是分析类型
+const TemplateExt = ".tmpl"
+
+// sectionsTemplate 各模板共用的命名模板 "sections"：输出语言、上下文、选中的文本和对齐的文本
+const sectionsTemplate = `{{define "sections"}}
+{{- if .Language}}Write the analysis in the language with code {{printf "%q" .Language}}.
+{{else}}Write the analysis in the language of the passage.
+{{end}}
+{{- if .Context}}
+Context:
+{{.Context}}
+{{end}}
+Passage:
+{{.Selection}}
+{{if .Aligned}}
+Aligned passage:
+{{.Aligned}}
+{{end}}
+{{- end}}`
+
+// builtinTemplates 内置的提示模板，按在界面中列出的顺序排列
+var builtinTemplates = []PromptTemplate{
+	{Type: AnalysisTypeExplain, Version: 1, Text: `Explain the passage: its meaning, the important concepts it uses and the background needed to understand it.
+{{template "sections" .}}`},
+	{Type: AnalysisTypeSummarize, Version: 1, Text: `Summarize the passage: its main points and how they connect, in far fewer words than the original. List the key terms as keywords.
+{{template "sections" .}}`},
+	{Type: AnalysisTypeTranslate, Version: 1, Text: `Translate the passage {{if .Language}}into the language with code {{printf "%q" .Language}}{{else}}into English{{end}}. Put the translation in content and add notes on terms, idioms or wordplay that do not carry over directly.
+{{- if .Context}}
+
+Context:
+{{.Context}}
+{{- end}}
+
+Passage:
+{{.Selection}}
+`},
+	{Type: AnalysisTypeDefine, Version: 1, Text: `Define the terms in the passage that a general reader may not know. Give each term as a concept with a short definition that fits how it is used here{{if .Title}} in "{{.Title}}"{{end}}.
+{{template "sections" .}}`},
+	{Type: AnalysisTypeBackground, Version: 1, Text: `Give the historical and cultural background of the passage{{if .Title}} from "{{.Title}}"{{end}}: the period, people, events, works and ideas it refers to or assumes the reader knows. Suggest references for further reading.
+{{template "sections" .}}`},
+	{Type: AnalysisTypeCritique, Version: 1, Text: `Critique the passage: state its claims, assess the reasoning and evidence, point out assumptions, weaknesses and counterarguments, and note what it does well.
+{{template "sections" .}}`},
+	{Type: AnalysisTypeSimplify, Version: 1, Text: `Rewrite the passage in plain, simple language that a younger reader or language learner can follow, keeping its meaning. Put the rewrite in content and explain any idea you had to leave out.
+{{template "sections" .}}`},
+	{Type: AnalysisTypeCompare, Version: 1, Text: `The reader is reading two documents side by side, for example an original and its translation. Compare the selected passage with the aligned passage: differences in meaning, wording and emphasis.
+{{template "sections" .}}`},
+}
+
+// templateTypePattern 用户模板文件名允许的分析类型
+var templateTypePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// PromptData 提示模板可用的变量
+type PromptData struct {
+	Type      string // 分析类型
+	Selection string // 选中的文本
+	Context   string // 选中文本的上下文
+	Language  string // 输出语言的代码，为空时使用原文的语言
+	Title     string // 文档标题
+	Aligned   string // 并排阅读时另一文档中对齐的文本
+}
+
+// PromptTemplate 一种分析类型的提示模板。修改内置模板时应增加版本号，使旧的缓存结果失效
+type PromptTemplate struct {
+	Type    string
+	Name    string // 显示名称，为空时由界面按分析类型翻译
+	Version int
+	Text    string
+	Builtin bool
+	Path    string // 用户模板的文件路径
+
+	parsed *template.Template
+}
+
+// TemplateSet 各分析类型的提示模板，内置模板可以被同名的用户模板替换
+type TemplateSet struct {
+	mu        sync.RWMutex
+	templates map[string]*PromptTemplate
+	types     []string
+}
+
+var (
+	defaultTemplates     *TemplateSet
+	defaultTemplatesOnce sync.Once
+)
+
+// DefaultTemplates 返回共享的模板集合，各提供者用它生成提示
+func DefaultTemplates() *TemplateSet {
+	defaultTemplatesOnce.Do(func() {
+		defaultTemplates = NewTemplateSet()
+	})
+	return defaultTemplates
+}
+
+// NewTemplateSet 创建只包含内置模板的集合
+func NewTemplateSet() *TemplateSet {
+	ts := &TemplateSet{templates: make(map[string]*PromptTemplate)}
+	for _, builtin := range builtinTemplates {
+		t := builtin
+		t.Builtin = true
+		if err := t.parse(); err != nil {
+			panic(fmt.Sprintf("builtin prompt template %s: %v", t.Type, err))
+		}
+		ts.templates[t.Type] = &t
+		ts.types = append(ts.types, t.Type)
+	}
+	return ts
+}
+
+// parse 解析模板文本，模板中可以引用共用的 "sections"
+func (t *PromptTemplate) parse() error {
+	parsed, err := template.New(t.Type).Parse(sectionsTemplate)
+	if err != nil {
+		return err
+	}
+	if _, err := parsed.Parse(t.Text); err != nil {
+		return err
+	}
+	t.parsed = parsed
+	return nil
+}
+
+// LoadDir 加载目录中的用户模板，每个 <类型>.tmpl 文件替换同名的模板或增加新的分析类型。
+// 文件开头可以有 “# name: 显示名称” 和 “# version: 版本号” 两行。
+// 目录不存在时不做任何事；有问题的文件被跳过，返回这些文件的错误
+func (ts *TemplateSet) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var loaded []*PromptTemplate
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != TemplateExt {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		t, err := readTemplateFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		loaded = append(loaded, t)
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	// 新增的类型按文件名顺序排在内置类型之后
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].Type < loaded[j].Type })
+	for _, t := range loaded {
+		if _, ok := ts.templates[t.Type]; !ok {
+			ts.types = append(ts.types, t.Type)
+		}
+		ts.templates[t.Type] = t
+	}
+	return errors.Join(errs...)
+}
+
+// readTemplateFile 读取并解析用户模板文件
+func readTemplateFile(path string) (*PromptTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	analysisType := strings.TrimSuffix(filepath.Base(path), TemplateExt)
+	if !templateTypePattern.MatchString(analysisType) {
+		return nil, fmt.Errorf("invalid analysis type %q, use lowercase letters, digits, - and _", analysisType)
+	}
+
+	t := &PromptTemplate{Type: analysisType, Version: 1, Path: path}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for len(lines) > 0 {
+		key, value, ok := templateHeader(lines[0])
+		if !ok {
+			break
+		}
+		switch key {
+		case "name":
+			t.Name = value
+		case "version":
+			version, err := strconv.Atoi(value)
+			if err != nil || version < 1 {
+				return nil, fmt.Errorf("invalid version %q", value)
+			}
+			t.Version = version
+		}
+		lines = lines[1:]
+	}
+	t.Text = strings.Join(lines, "\n")
+	if strings.TrimSpace(t.Text) == "" {
+		return nil, errors.New("empty template")
+	}
+	if t.Name == "" {
+		if builtin := builtinTemplate(analysisType); builtin == nil {
+			t.Name = analysisType
+		}
+	}
+	if err := t.parse(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// templateHeader 解析 “# key: value” 形式的文件头
+func templateHeader(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "#") {
+		return "", "", false
+	}
+	key, value, ok = strings.Cut(strings.TrimSpace(line[1:]), ":")
+	key = strings.ToLower(strings.TrimSpace(key))
+	if !ok || (key != "name" && key != "version") {
+		return "", "", false
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+// builtinTemplate 按分析类型查找内置模板
+func builtinTemplate(analysisType string) *PromptTemplate {
+	for i := range builtinTemplates {
+		if builtinTemplates[i].Type == analysisType {
+			return &builtinTemplates[i]
+		}
+	}
+	return nil
+}
+
+// Types 所有分析类型，内置类型在前
+func (ts *TemplateSet) Types() []string {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return append([]string(nil), ts.types...)
+}
+
+// Get 获取分析类型的模板
+func (ts *TemplateSet) Get(analysisType string) (PromptTemplate, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	t, ok := ts.templates[analysisType]
+	if !ok {
+		return PromptTemplate{}, false
+	}
+	return *t, true
+}
+
+// Fingerprint 分析类型所用模板的版本和内容摘要，模板变化时缓存键随之改变。
+// 未知的类型使用解释模板
+func (ts *TemplateSet) Fingerprint(analysisType string) string {
+	t := ts.lookup(analysisType)
+	sum := sha256.Sum256([]byte(t.Text))
+	return fmt.Sprintf("v%d:%x", t.Version, sum[:6])
+}
+
+// Render 用请求填充分析类型的模板，未知的类型按解释处理
+func (ts *TemplateSet) Render(request AnalysisRequest) (string, error) {
+	t := ts.lookup(request.AnalysisType)
+
+	data := PromptData{
+		Type:      request.AnalysisType,
+		Selection: request.Text,
+		Context:   request.Context,
+		Language:  request.Language,
+	}
+	if doc, ok := request.Parameters[ParamDocument].(document.Document); ok {
+		data.Title = doc.GetMetadata().Title
+		if data.Title == "" {
+			data.Title = doc.GetTitle()
+		}
+	}
+	data.Aligned, _ = request.Parameters[ParamAlignedText].(string)
+
+	var b strings.Builder
+	if err := t.parsed.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("prompt template %s: %w", t.Type, err)
+	}
+	return b.String(), nil
+}
+
+// lookup 分析类型的模板，未知的类型返回解释模板
+func (ts *TemplateSet) lookup(analysisType string) *PromptTemplate {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	if t, ok := ts.templates[analysisType]; ok {
+		return t
+	}
+	return ts.templates[AnalysisTypeExplain]
+}
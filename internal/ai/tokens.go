@@ -0,0 +1,16 @@
+package ai
+
+import "unicode/utf8"
+
+// estimateTokens 粗略估计文本的token数：ASCII字符约4个一个token，其余字符各算一个
+func estimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
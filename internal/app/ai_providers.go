@@ -0,0 +1,238 @@
+package app
+
+import (
+	"ai-reader/internal/ai"
+	"ai-reader/pkg/document"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 分析缓存的默认限制，配置中没有时使用
+const (
+	defaultCacheEntries  = 200
+	defaultCacheMemoryMB = 16
+	defaultCacheTTLDays  = 30
+)
+
+// promptTemplatesDir 配置目录中存放用户提示模板的子目录
+const promptTemplatesDir = "prompts"
+
+// 配置中没有密钥时读取的环境变量
+const (
+	openAIKeyEnv    = "OPENAI_API_KEY"
+	anthropicKeyEnv = "ANTHROPIC_API_KEY"
+)
+
+// setupAIProviders 按配置注册AI提供者并设置默认提供者。
+// 注册顺序即回退顺序，配置的默认提供者未注册时使用第一个
+func (a *App) setupAIProviders() {
+	a.aiService.RegisterProvider(ai.NewOpenAIProvider(a.openAIConfig()))
+	a.aiService.RegisterProvider(ai.NewOllamaProvider(a.ollamaConfig()))
+	a.aiService.RegisterProvider(ai.NewAnthropicProvider(a.anthropicConfig()))
+	// 内置离线提供者总是可用，放在最后作为兜底
+	a.aiService.RegisterProvider(ai.NewHeuristicProvider())
+
+	a.aiService.SetDefaultProvider(a.config.GetString("ai_provider"))
+	// 文本不得离开本机时只使用本地提供者
+	a.aiService.SetLocalOnly(a.config.GetBool("ai_local_only"))
+
+	a.setupAICache()
+	a.setupAIContext()
+}
+
+// loadPromptTemplates 加载配置目录中的用户提示模板，替换同名的内置模板或增加分析类型
+func (a *App) loadPromptTemplates() error {
+	return ai.DefaultTemplates().LoadDir(filepath.Join(a.getConfigDir(), promptTemplatesDir))
+}
+
+// setupAIContext 按配置创建上下文组装器，配置关闭时分析请求不附带上下文
+func (a *App) setupAIContext() {
+	section, _ := a.config.Get("ai_context").(map[string]interface{})
+	if enabled, ok := section["enabled"].(bool); ok && !enabled {
+		return
+	}
+
+	a.contextBuilder = ai.NewContextBuilder()
+	if v, ok := number(section, "token_budget"); ok && v >= 0 {
+		a.contextBuilder.Tokens = int(v)
+	}
+	if v, ok := number(section, "paragraphs"); ok && v >= 0 {
+		a.contextBuilder.Paragraphs = int(v)
+	}
+	if v, ok := number(section, "highlights"); ok && v >= 0 {
+		a.contextBuilder.Highlights = int(v)
+	}
+}
+
+// analysisContext 为分析请求组装上下文：选中文本所在文档的元数据、章节、前后段落和之前的高亮
+func (a *App) analysisContext(request ai.AnalysisRequest, doc document.Document, page int) string {
+	if a.contextBuilder == nil {
+		return ""
+	}
+	start, end := -1, -1
+	if v, ok := request.Parameters[ai.ParamSelectionStart].(int); ok {
+		start = v
+	}
+	if v, ok := request.Parameters[ai.ParamSelectionEnd].(int); ok {
+		end = v
+	}
+	return a.contextBuilder.Build(ai.ContextSource{
+		Document:   doc,
+		Page:       page,
+		Start:      start,
+		End:        end,
+		Text:       request.Text,
+		Highlights: a.highlightStore.GetHighlights(a.documentFile(doc)),
+	})
+}
+
+// documentFile 打开的文档的文件路径，文档不在任何标签页中时为空
+func (a *App) documentFile(doc document.Document) string {
+	for _, t := range a.readerController.GetTabs() {
+		if t.Document == doc {
+			return t.Filename
+		}
+	}
+	return ""
+}
+
+// setupAICache 按配置创建内存和磁盘两级分析缓存，磁盘缓存在配置目录下，启动时在后台清理过期的结果
+func (a *App) setupAICache() {
+	section, _ := a.config.Get("ai_cache").(map[string]interface{})
+	if enabled, ok := section["enabled"].(bool); ok && !enabled {
+		return
+	}
+
+	entries := defaultCacheEntries
+	if v, ok := number(section, "memory_entries"); ok && v > 0 {
+		entries = int(v)
+	}
+	memoryMB := float64(defaultCacheMemoryMB)
+	if v, ok := number(section, "memory_mb"); ok && v > 0 {
+		memoryMB = v
+	}
+	ttlDays := float64(defaultCacheTTLDays)
+	if v, ok := number(section, "ttl_days"); ok && v >= 0 {
+		ttlDays = v
+	}
+	ttl := time.Duration(ttlDays * float64(24*time.Hour))
+
+	a.aiCache = ai.NewTieredCache(
+		ai.NewMemoryCache(entries, int64(memoryMB*(1<<20)), ttl),
+		ai.NewDiskCache(filepath.Join(a.getConfigDir(), "cache", "analysis"), ttl),
+	)
+	a.aiService.SetCache(a.aiCache)
+	go a.aiCache.Prune()
+}
+
+// openAIConfig 读取OpenAI兼容接口的配置，未配置的项使用默认值
+func (a *App) openAIConfig() ai.OpenAIConfig {
+	config := ai.DefaultOpenAIConfig()
+	section := a.providerSection(ai.OpenAIProviderName)
+	if v, ok := section["base_url"].(string); ok && v != "" {
+		config.BaseURL = v
+	}
+	if v, ok := section["model"].(string); ok && v != "" {
+		config.Model = v
+	}
+	if v, ok := section["api_key"].(string); ok {
+		config.APIKey = v
+	}
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv(openAIKeyEnv)
+	}
+	if v, ok := number(section, "temperature"); ok {
+		config.Temperature = v
+	}
+	if v, ok := number(section, "timeout"); ok && v > 0 {
+		config.Timeout = time.Duration(v * float64(time.Second))
+	}
+	if v, ok := section["json_schema"].(bool); ok {
+		config.JSONSchema = v
+	}
+	if v, ok := section["stream_usage"].(bool); ok {
+		config.StreamUsage = v
+	}
+	return config
+}
+
+// ollamaConfig 读取Ollama服务的配置，未配置的项使用默认值
+func (a *App) ollamaConfig() ai.OllamaConfig {
+	config := ai.DefaultOllamaConfig()
+	section := a.providerSection(ai.OllamaProviderName)
+	if v, ok := section["base_url"].(string); ok && v != "" {
+		config.BaseURL = v
+	}
+	if v, ok := section["model"].(string); ok && v != "" {
+		config.Model = v
+	}
+	if models, ok := section["models"].(map[string]interface{}); ok {
+		config.Models = make(map[string]string, len(models))
+		for analysisType, model := range models {
+			if name, ok := model.(string); ok && name != "" {
+				config.Models[analysisType] = name
+			}
+		}
+	}
+	if v, ok := number(section, "temperature"); ok {
+		config.Temperature = v
+	}
+	if v, ok := number(section, "timeout"); ok && v > 0 {
+		config.Timeout = time.Duration(v * float64(time.Second))
+	}
+	if v, ok := section["json_schema"].(bool); ok {
+		config.JSONSchema = v
+	}
+	return config
+}
+
+// anthropicConfig 读取Anthropic Messages API的配置，未配置的项使用默认值
+func (a *App) anthropicConfig() ai.AnthropicConfig {
+	config := ai.DefaultAnthropicConfig()
+	section := a.providerSection(ai.AnthropicProviderName)
+	if v, ok := section["base_url"].(string); ok && v != "" {
+		config.BaseURL = v
+	}
+	if v, ok := section["model"].(string); ok && v != "" {
+		config.Model = v
+	}
+	if v, ok := section["api_key"].(string); ok {
+		config.APIKey = v
+	}
+	if config.APIKey == "" {
+		config.APIKey = os.Getenv(anthropicKeyEnv)
+	}
+	if v, ok := number(section, "max_tokens"); ok && v > 0 {
+		config.MaxTokens = int(v)
+	}
+	if v, ok := number(section, "timeout"); ok && v > 0 {
+		config.Timeout = time.Duration(v * float64(time.Second))
+	}
+	if v, ok := section["whole_document"].(bool); ok {
+		config.WholeDocument = v
+	}
+	if v, ok := number(section, "context_tokens"); ok && v > 0 {
+		config.ContextTokens = int(v)
+	}
+	return config
+}
+
+// number 读取配置项中的数值。默认配置中的数值是int，从配置文件读取的是float64
+func number(section map[string]interface{}, key string) (float64, bool) {
+	switch v := section[key].(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// providerSection 获取提供者的配置项，配置键与提供者名称相同
+func (a *App) providerSection(name string) map[string]interface{} {
+	section, _ := a.config.Get(name).(map[string]interface{})
+	return section
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProviderConfigFromDefaultsAndFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	defaults := NewConfig(path)
+	if err := defaults.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := defaults.Save(); err != nil {
+		t.Fatal(err)
+	}
+	// 从文件读取时数值都是float64
+	saved := NewConfig(path)
+	if err := saved.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	fromDefaults, fromFile := &App{config: defaults}, &App{config: saved}
+	if got, want := fromDefaults.openAIConfig(), fromFile.openAIConfig(); !reflect.DeepEqual(got, want) {
+		t.Errorf("openai config from defaults %+v, from file %+v", got, want)
+	}
+	if got, want := fromDefaults.ollamaConfig(), fromFile.ollamaConfig(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ollama config from defaults %+v, from file %+v", got, want)
+	}
+	if got, want := fromDefaults.anthropicConfig(), fromFile.anthropicConfig(); !reflect.DeepEqual(got, want) {
+		t.Errorf("anthropic config from defaults %+v, from file %+v", got, want)
+	}
+
+	defaults.Set("openai", map[string]interface{}{"timeout": 5, "temperature": float32(0.5)})
+	config := fromDefaults.openAIConfig()
+	if config.Timeout != 5*time.Second || config.Temperature != 0.5 {
+		t.Errorf("timeout %v, temperature %v, want 5s and 0.5", config.Timeout, config.Temperature)
+	}
+}
+
+func TestNumber(t *testing.T) {
+	section := map[string]interface{}{"int": 3, "float32": float32(1.5), "float64": 2.5, "string": "4"}
+	tests := []struct {
+		key    string
+		want   float64
+		wantOK bool
+	}{
+		{"int", 3, true},
+		{"float32", 1.5, true},
+		{"float64", 2.5, true},
+		{"string", 0, false},
+		{"missing", 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := number(section, tt.key); got != tt.want || ok != tt.wantOK {
+			t.Errorf("number(%q) = %v, %v, want %v, %v", tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
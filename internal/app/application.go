@@ -3,12 +3,19 @@ package app
 import (
 	"ai-reader/internal/ai"
 	"ai-reader/internal/events"
+	"ai-reader/internal/i18n"
 	"ai-reader/internal/reader"
 	"ai-reader/internal/ui"
+	"ai-reader/pkg/annotation"
 	"ai-reader/pkg/document"
+	"ai-reader/pkg/segment"
 	"ai-reader/pkg/theme"
+	"context"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 )
 
 // App 应用程序实现
@@ -18,17 +25,35 @@ type App struct {
 	themeManager     theme.ThemeManager
 	readerController reader.ReaderController
 	aiService        ai.AIService
+	aiCache          *ai.TieredCache    // 分析缓存，配置关闭缓存时为nil
+	contextBuilder   *ai.ContextBuilder // 分析上下文组装器，配置关闭时为nil
+	annotations      *annotation.Manager
+	highlightStore   annotation.HighlightStore
 	mainWindow       *ui.MainWindow
 	serviceContainer ServiceContainer
 	config           Configuration
+	
+	// 正在进行的分析，按请求ID记录取消函数；取消先于请求到达时记下请求ID，请求开始时直接取消
+	analysesMu sync.Mutex
+	analyses   map[string]context.CancelFunc
+	cancelled  []string
+	
+	// 应用程序的生命周期，关闭时取消，耗时的后台任务据此停止
+	ctx  context.Context
+	stop context.CancelFunc
 }
 
+// maxEarlyCancels 记录的先于请求到达的取消数，超过时丢弃最早的
+const maxEarlyCancels = 32
+
 // NewApp 创建新应用程序
 func NewApp() *App {
 	app := &App{
 		eventBus:         events.NewBus(),
 		serviceContainer: NewServiceContainer(),
+		analyses:         make(map[string]context.CancelFunc),
 	}
+	app.ctx, app.stop = context.WithCancel(context.Background())
 	
 	app.initializeServices()
 	app.setupEventHandlers()
@@ -50,17 +75,31 @@ func (a *App) initializeServices() {
 	// 初始化主题管理器
 	a.themeManager = theme.NewManager(filepath.Join(configDir, "theme.json"))
 	
-	// TODO: 初始化AI服务
-	// a.aiService = ai.NewService()
+	// 加载用户分词词典（可选）
+	if dict, err := os.Open(filepath.Join(configDir, "user_dict.txt")); err == nil {
+		segment.Default().LoadDictionary(dict)
+		dict.Close()
+	}
+	
+	// 初始化标注导入和高亮存储
+	a.annotations = annotation.NewManager()
+	a.highlightStore = annotation.NewStore(filepath.Join(configDir, "highlights.json"))
+	a.highlightStore.Load()
 	
-	// TODO: 初始化阅读器控制器
-	// a.readerController = reader.NewController(a.eventBus)
+	// 初始化AI服务，提供者在加载配置后注册
+	a.aiService = ai.NewService()
+	
+	// 初始化阅读器控制器
+	a.readerController = reader.NewController(a.eventBus, a.documentManager, a.themeManager, a.config)
 	
 	// 注册服务到容器
 	a.serviceContainer.Register("eventBus", a.eventBus)
 	a.serviceContainer.Register("documentManager", a.documentManager)
 	a.serviceContainer.Register("themeManager", a.themeManager)
+	a.serviceContainer.Register("readerController", a.readerController)
 	a.serviceContainer.Register("config", a.config)
+	a.serviceContainer.Register("aiService", a.aiService)
+	a.serviceContainer.Register("highlightStore", a.highlightStore)
 }
 
 // setupEventHandlers 设置全局事件处理器
@@ -76,22 +115,212 @@ func (a *App) setupEventHandlers() {
 	
 	// 监听AI分析请求
 	a.eventBus.Subscribe(events.AIAnalysisRequest, func(event events.Event) {
-		// TODO: 处理AI分析请求
-		selectedText := event.Payload.(string)
+		request := event.Payload.(ai.AnalysisRequest)
 		
-		// 模拟AI分析结果
-		result := "这是对文本 \"" + selectedText + "\" 的分析结果。\n\n" +
-				 "这段文本包含了重要的概念和背景信息。AI分析功能正在开发中，" +
-				 "将来会提供更深入的语义分析、概念解释和相关背景知识。"
+		// 先登记请求，组装上下文期间到达的取消同样生效
+		ctx, done := a.startAnalysis(request.ID)
+		defer done()
 		
-		// 发布分析结果
+		// 附上选中文本所在的文档，支持整篇文档分析的提供者可以把全文作为上下文；
+		// 其余提供者使用选中文本周围的段落、所在章节和之前的高亮作为上下文。
+		// AI面板没有附上文档时使用活动标签页的文档
+		if request.Parameters == nil {
+			request.Parameters = make(map[string]interface{})
+		}
+		doc, _ := request.Parameters[ai.ParamDocument].(document.Document)
+		page, _ := request.Parameters[ai.ParamPage].(int)
+		if doc == nil {
+			doc, page = a.readerController.GetCurrentDocument(), a.readerController.GetCurrentPage()
+		}
+		if doc != nil {
+			request.Parameters[ai.ParamDocument] = doc
+			request.Parameters[ai.ParamPage] = page
+			if request.Context == "" {
+				request.Context = a.analysisContext(request, doc, page)
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		
+		chunks, err := a.aiService.AnalyzeTextStream(ctx, request)
+		if err != nil {
+			// 已取消的请求不再发布任何事件，取消由AI面板处理
+			if ctx.Err() == nil {
+				a.eventBus.Publish(events.Event{
+					Type:    events.AIAnalysisFailed,
+					Payload: &ai.RequestError{RequestID: request.ID, Err: err},
+				})
+			}
+			return
+		}
+		
+		// 逐段发布输出，最后发布完整结果；中途出错时已输出的部分由AI面板保留。
+		// 请求被取消后通道随即关闭
+		for chunk := range chunks {
+			if ctx.Err() != nil {
+				return
+			}
+			switch {
+			case chunk.Err != nil:
+				a.eventBus.Publish(events.Event{
+					Type:    events.AIAnalysisFailed,
+					Payload: &ai.RequestError{RequestID: request.ID, Err: chunk.Err},
+				})
+			case chunk.Done:
+				a.eventBus.Publish(events.Event{
+					Type:    events.AIAnalysisResult,
+					Payload: chunk.Result,
+				})
+			default:
+				a.eventBus.Publish(events.Event{
+					Type:    events.AIAnalysisChunk,
+					Payload: chunk,
+				})
+			}
+		}
+	})
+	
+	// 监听AI分析取消事件
+	a.eventBus.Subscribe(events.AIAnalysisCancelled, func(event events.Event) {
+		a.cancelAnalysis(event.Payload.(string))
+	})
+	
+	// 监听清除分析缓存请求，回报清除前的占用
+	a.eventBus.Subscribe(events.AICacheClearRequest, func(event events.Event) {
+		var stats ai.CacheStats
+		if a.aiCache != nil {
+			stats = a.aiCache.Stats()
+			a.aiCache.Clear()
+		}
 		a.eventBus.Publish(events.Event{
-			Type:    events.AIAnalysisResult,
-			Payload: result,
+			Type:    events.AICacheCleared,
+			Payload: stats,
+		})
+	})
+	
+	// 监听标注导入请求
+	a.eventBus.Subscribe(events.HighlightsImportRequest, func(event events.Event) {
+		filename := event.Payload.(string)
+		
+		library := a.loadLibrary(a.ctx)
+		defer func() {
+			for _, entry := range library {
+				entry.Document.Close()
+			}
+		}()
+		if a.ctx.Err() != nil {
+			return
+		}
+		
+		var payload interface{}
+		report, err := a.annotations.Import(filename, library, a.highlightStore)
+		if err != nil {
+			payload = err
+		} else {
+			payload = report
+		}
+		
+		a.eventBus.Publish(events.Event{
+			Type:    events.HighlightsImported,
+			Payload: payload,
 		})
 	})
 }
 
+// startAnalysis 登记一个正在进行的分析，返回可以取消的context和分析结束时调用的函数
+func (a *App) startAnalysis(id string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	
+	a.analysesMu.Lock()
+	a.analyses[id] = cancel
+	for i, cancelledID := range a.cancelled {
+		if cancelledID == id {
+			a.cancelled = append(a.cancelled[:i], a.cancelled[i+1:]...)
+			cancel()
+			break
+		}
+	}
+	a.analysesMu.Unlock()
+	
+	return ctx, func() {
+		a.analysesMu.Lock()
+		delete(a.analyses, id)
+		a.analysesMu.Unlock()
+		cancel()
+	}
+}
+
+// cancelAnalysis 取消分析。事件处理是并发的，取消可能先于请求到达，这时记下请求ID，
+// 由 startAnalysis 在请求开始时取消
+func (a *App) cancelAnalysis(id string) {
+	a.analysesMu.Lock()
+	cancel, ok := a.analyses[id]
+	if !ok {
+		if len(a.cancelled) >= maxEarlyCancels {
+			a.cancelled = a.cancelled[1:]
+		}
+		a.cancelled = append(a.cancelled, id)
+	}
+	a.analysesMu.Unlock()
+	
+	if ok {
+		cancel()
+	}
+}
+
+// loadLibrary 加载用于匹配摘录的文档：打开的标签页、最近打开的文档和书库目录下所有可识别的文档。
+// 没有配置书库目录时不扫描磁盘；ctx取消时停止遍历，返回已经加载的文档
+func (a *App) loadLibrary(ctx context.Context) []annotation.LibraryEntry {
+	var library []annotation.LibraryEntry
+	seen := make(map[string]bool)
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		
+		doc, err := a.documentManager.LoadDocument(path)
+		if err != nil {
+			return
+		}
+		library = append(library, annotation.LibraryEntry{Path: path, Document: doc})
+	}
+	
+	for _, tab := range a.readerController.GetTabs() {
+		add(tab.Filename)
+	}
+	for _, filename := range a.readerController.GetRecentDocuments() {
+		add(filename)
+	}
+	
+	libraryDir := a.config.GetString("library_dir")
+	if libraryDir == "" {
+		return library
+	}
+	filepath.WalkDir(libraryDir, func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return nil
+		}
+		// 跳过隐藏目录
+		if d.IsDir() {
+			if path != libraryDir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		
+		add(path)
+		return nil
+	})
+	
+	return library
+}
+
 // getConfigDir 获取配置目录
 func (a *App) getConfigDir() string {
 	homeDir, err := os.UserHomeDir()
@@ -108,8 +337,34 @@ func (a *App) Initialize() error {
 		// 配置加载失败不是致命错误，使用默认配置
 	}
 	
+	// 设置界面语言，配置的语言不受支持时跟随系统
+	if err := i18n.Init(a.config.GetString("language")); err != nil {
+		i18n.Init(i18n.LocaleAuto)
+	}
+	
+	// 按配置注册AI提供者
+	a.setupAIProviders()
+	
+	// 加载用户的提示模板，有问题的模板文件在主窗口创建后提示
+	templateErr := a.loadPromptTemplates()
+	
+	// 初始化阅读器控制器
+	if err := a.readerController.Initialize(); err != nil {
+		return err
+	}
+	
 	// 创建主窗口
-	a.mainWindow = ui.NewMainWindow(a.eventBus)
+	a.mainWindow = ui.NewMainWindow(a.eventBus, a.readerController, a.config)
+	
+	if templateErr != nil {
+		a.eventBus.Publish(events.Event{
+			Type:    events.ErrorOccurred,
+			Payload: templateErr,
+		})
+	}
+	
+	// 恢复上次退出时打开的标签页
+	a.readerController.RestoreSession()
 	
 	return nil
 }
@@ -128,9 +383,22 @@ func (a *App) Run() error {
 
 // Shutdown 关闭应用程序
 func (a *App) Shutdown() error {
+	// 停止后台任务
+	a.stop()
+	
+	// 保存会话：窗口布局和打开的标签页
+	if a.mainWindow != nil {
+		a.mainWindow.SaveState()
+	}
+	a.readerController.SaveSession()
+	
+	// 关闭所有文档
+	a.readerController.Cleanup()
+	
 	// 保存配置
 	a.config.Save()
 	a.themeManager.SaveThemeConfig()
+	a.highlightStore.Save()
 	
 	return nil
 }
@@ -88,8 +88,11 @@ func (c *Config) GetString(key string) string {
 
 func (c *Config) GetInt(key string) int {
 	if value := c.Get(key); value != nil {
-		if num, ok := value.(float64); ok {
+		switch num := value.(type) {
+		case float64:
 			return int(num)
+		case int:
+			return num
 		}
 	}
 	return 0
@@ -106,8 +109,13 @@ func (c *Config) GetBool(key string) bool {
 
 func (c *Config) GetFloat(key string) float64 {
 	if value := c.Get(key); value != nil {
-		if f, ok := value.(float64); ok {
+		switch f := value.(type) {
+		case float64:
 			return f
+		case float32:
+			return float64(f)
+		case int:
+			return float64(f)
 		}
 	}
 	return 0.0
@@ -120,10 +128,65 @@ func (c *Config) setDefaults() {
 		"window_height":     800,
 		"window_maximized":  false,
 		"default_theme":     "classic",
-		"font_size":         14,
+		"language":          "",
+		"font_size":         0,
 		"auto_save":         true,
 		"ai_provider":       "openai",
-		"page_turn_animation": "flip",
+		"openai": map[string]interface{}{
+			"base_url":     "https://api.openai.com/v1",
+			"model":        "gpt-4o-mini",
+			"api_key":      "",
+			"temperature":  0.3,
+			"timeout":      60,
+			"json_schema":  true,
+			"stream_usage": true,
+		},
+		"ollama": map[string]interface{}{
+			"base_url":    "http://localhost:11434",
+			"model":       "qwen2.5:7b",
+			"models":      map[string]interface{}{},
+			"temperature": 0.3,
+			"timeout":     120,
+			"json_schema": true,
+		},
+		"anthropic": map[string]interface{}{
+			"base_url":       "https://api.anthropic.com",
+			"model":          "claude-sonnet-4-0",
+			"api_key":        "",
+			"max_tokens":     2048,
+			"timeout":        120,
+			"whole_document": false,
+			"context_tokens": 150000,
+		},
+		"ai_local_only":     false,
+		"ai_cache": map[string]interface{}{
+			"enabled":        true,
+			"memory_entries": 200,
+			"memory_mb":      16,
+			"ttl_days":       30,
+		},
+		"ai_context": map[string]interface{}{
+			"enabled":      true,
+			"token_budget": 1500,
+			"paragraphs":   3,
+			"highlights":   5,
+		},
+		"page_turn_animation": "theme",
+		"reduced_motion":    false,
+		"focus_mode":        false,
+		"focus_chars_per_line": 66,
+		"focus_dim_paragraphs": true,
+		"focus_typewriter_scroll": false,
+		"parallel_align":    "paragraph",
+		"parallel_sync":     true,
+		"keymap_preset":     "default",
+		"keymap":            map[string]interface{}{},
+		"recent_documents":  []interface{}{},
+		"bookmarks":         map[string]interface{}{},
+		"reading_speed":     map[string]interface{}{},
+		"session":           map[string]interface{}{},
+		"panel_splits":      map[string]interface{}{},
 		"enable_sounds":     true,
+		"library_dir":       "",
 	}
 }
\ No newline at end of file
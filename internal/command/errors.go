@@ -0,0 +1,14 @@
+package command
+
+import "errors"
+
+var (
+	// ErrUnknownCommand 命令未注册
+	ErrUnknownCommand = errors.New("unknown command")
+
+	// ErrInvalidChord 无法解析的按键组合
+	ErrInvalidChord = errors.New("invalid key chord")
+
+	// ErrUnknownPreset 不存在的键位预设
+	ErrUnknownPreset = errors.New("unknown keymap preset")
+)
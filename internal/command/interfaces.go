@@ -0,0 +1,22 @@
+package command
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// Command 可以通过快捷键、菜单或命令面板执行的命名操作
+type Command struct {
+	ID       string // 唯一标识，如 "page.next"
+	Title    string // 显示名称
+	Category string // 分类，用于速查表分组
+	Run      func()
+}
+
+// Chord 按键组合
+type Chord struct {
+	Modifier fyne.KeyModifier
+	Key      fyne.KeyName
+}
+
+// Keymap 命令到按键组合的绑定，一个命令可以绑定多个按键组合
+type Keymap map[string][]Chord
@@ -0,0 +1,275 @@
+package command
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	"sort"
+	"strings"
+)
+
+// 修饰键名称。Ctrl 在macOS上对应Command键
+var modifierNames = []struct {
+	name     string
+	modifier fyne.KeyModifier
+}{
+	{"Ctrl", fyne.KeyModifierShortcutDefault},
+	{"Alt", fyne.KeyModifierAlt},
+	{"Shift", fyne.KeyModifierShift},
+}
+
+// keyAliases 便于书写的按键别名
+var keyAliases = map[string]fyne.KeyName{
+	"PAGEUP":   fyne.KeyPageUp,
+	"PAGEDOWN": fyne.KeyPageDown,
+	"ESC":      fyne.KeyEscape,
+	"ENTER":    fyne.KeyReturn,
+	"PLUS":     fyne.KeyPlus,
+	"MINUS":    fyne.KeyMinus,
+}
+
+// keyDisplayNames 按键的显示名称，没有列出的按键直接使用 fyne.KeyName
+var keyDisplayNames = map[fyne.KeyName]string{
+	fyne.KeyPageUp:   "PageUp",
+	fyne.KeyPageDown: "PageDown",
+	fyne.KeyReturn:   "Enter",
+	fyne.KeyEscape:   "Esc",
+}
+
+// ParseChord 解析按键组合，如 "Ctrl+Shift+F"、"Space"、"PageDown"
+func ParseChord(s string) (Chord, error) {
+	var chord Chord
+
+	parts := strings.Split(strings.TrimSpace(s), "+")
+	// 以 "+" 结尾表示按键本身是加号，如 "Ctrl++"
+	if len(parts) > 1 && parts[len(parts)-1] == "" {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return Chord{}, fmt.Errorf("%w: %q", ErrInvalidChord, s)
+		}
+
+		if i < len(parts)-1 {
+			modifier, ok := parseModifier(part)
+			if !ok {
+				return Chord{}, fmt.Errorf("%w: unknown modifier %q in %q", ErrInvalidChord, part, s)
+			}
+			chord.Modifier |= modifier
+			continue
+		}
+
+		chord.Key = parseKey(part)
+	}
+
+	return chord, nil
+}
+
+func parseModifier(name string) (fyne.KeyModifier, bool) {
+	switch strings.ToLower(name) {
+	case "ctrl", "control", "cmd", "command", "mod":
+		return fyne.KeyModifierShortcutDefault, true
+	case "alt", "option":
+		return fyne.KeyModifierAlt, true
+	case "shift":
+		return fyne.KeyModifierShift, true
+	case "super", "win", "meta":
+		return fyne.KeyModifierSuper, true
+	}
+	return 0, false
+}
+
+func parseKey(name string) fyne.KeyName {
+	upper := strings.ToUpper(name)
+	if key, ok := keyAliases[upper]; ok {
+		return key
+	}
+	// 单个字母统一为大写，与 fyne.KeyA 等一致
+	if len(name) == 1 {
+		return fyne.KeyName(upper)
+	}
+	// 其余按键名首字母大写，如 "space" -> "Space"
+	return fyne.KeyName(strings.ToUpper(name[:1]) + name[1:])
+}
+
+// String 按键组合的文本形式，可以被 ParseChord 解析
+func (c Chord) String() string {
+	var parts []string
+	for _, m := range modifierNames {
+		if c.Modifier&m.modifier != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	if c.Modifier&fyne.KeyModifierSuper != 0 && fyne.KeyModifierShortcutDefault != fyne.KeyModifierSuper {
+		parts = append(parts, "Super")
+	}
+
+	key := string(c.Key)
+	if name, ok := keyDisplayNames[c.Key]; ok {
+		key = name
+	}
+	return strings.Join(append(parts, key), "+")
+}
+
+// IsShortcut 是否带有Ctrl、Alt等修饰键。只有这类组合能注册为Fyne快捷键，
+// 其余按键需要在按键事件中分发
+func (c Chord) IsShortcut() bool {
+	return c.Modifier&^fyne.KeyModifierShift != 0
+}
+
+// Clone 复制键位
+func (km Keymap) Clone() Keymap {
+	clone := make(Keymap, len(km))
+	for id, chords := range km {
+		clone[id] = append([]Chord(nil), chords...)
+	}
+	return clone
+}
+
+// Merge 用另一个键位中的绑定覆盖同名命令的绑定
+func (km Keymap) Merge(other Keymap) Keymap {
+	merged := km.Clone()
+	for id, chords := range other {
+		merged[id] = append([]Chord(nil), chords...)
+	}
+	return merged
+}
+
+// ToConfig 转为可以保存到配置的形式：命令ID -> 按键组合字符串列表
+func (km Keymap) ToConfig() map[string]interface{} {
+	data := make(map[string]interface{}, len(km))
+	ids := make([]string, 0, len(km))
+	for id := range km {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		chords := make([]interface{}, 0, len(km[id]))
+		for _, chord := range km[id] {
+			chords = append(chords, chord.String())
+		}
+		data[id] = chords
+	}
+	return data
+}
+
+// ParseKeymap 从配置中解析键位，值可以是字符串或字符串列表
+func ParseKeymap(data map[string]interface{}) (Keymap, error) {
+	km := make(Keymap, len(data))
+	for id, value := range data {
+		var specs []string
+		switch v := value.(type) {
+		case string:
+			specs = []string{v}
+		case []string:
+			specs = v
+		case []interface{}:
+			for _, item := range v {
+				spec, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("%w: %v for %s", ErrInvalidChord, item, id)
+				}
+				specs = append(specs, spec)
+			}
+		default:
+			return nil, fmt.Errorf("%w: %v for %s", ErrInvalidChord, value, id)
+		}
+
+		chords := make([]Chord, 0, len(specs))
+		for _, spec := range specs {
+			chord, err := ParseChord(spec)
+			if err != nil {
+				return nil, err
+			}
+			chords = append(chords, chord)
+		}
+		km[id] = chords
+	}
+	return km, nil
+}
+
+// mustKeymap 解析内置键位，格式错误属于编程错误
+func mustKeymap(data map[string][]string) Keymap {
+	km := make(Keymap, len(data))
+	for id, specs := range data {
+		for _, spec := range specs {
+			chord, err := ParseChord(spec)
+			if err != nil {
+				panic(err)
+			}
+			km[id] = append(km[id], chord)
+		}
+	}
+	return km
+}
+
+// 键位预设名称
+const (
+	PresetDefault = "default"
+	PresetVim     = "vim"
+)
+
+// DefaultKeymap 默认键位
+func DefaultKeymap() Keymap {
+	return mustKeymap(map[string][]string{
+		"page.next":            {"Right", "PageDown", "Space"},
+		"page.previous":        {"Left", "PageUp", "Shift+Space"},
+		"page.first":           {"Ctrl+Home"},
+		"page.last":            {"Ctrl+End"},
+		"page.goto":            {"Ctrl+G"},
+		"document.open":        {"Ctrl+O"},
+		"document.close":       {"Ctrl+W"},
+		"bookmark.toggle":      {"Ctrl+D"},
+		"document.properties":  {"Ctrl+I"},
+		"tab.next":             {"Ctrl+Tab", "Ctrl+PageDown"},
+		"tab.previous":         {"Ctrl+Shift+Tab", "Ctrl+PageUp"},
+		"search.find":          {"Ctrl+F"},
+		"selection.analyze":    {"Ctrl+Enter"},
+		"view.focus_mode":      {"Ctrl+Shift+F"},
+		"view.parallel":        {"Ctrl+\\"},
+		"view.fullscreen":      {"F11"},
+		"view.file_tree":       {"Ctrl+B"},
+		"view.ai_panel":        {"Ctrl+Shift+A"},
+		"view.zoom_in":         {"Ctrl+=", "Ctrl+Plus"},
+		"view.zoom_out":        {"Ctrl+-"},
+		"view.zoom_reset":      {"Ctrl+0"},
+		"view.command_palette": {"Ctrl+Shift+P"},
+		"theme.next":           {"Ctrl+Shift+T"},
+		"theme.classic":        {"Ctrl+1"},
+		"theme.dark":           {"Ctrl+2"},
+		"theme.green":          {"Ctrl+3"},
+		"theme.minimal":        {"Ctrl+4"},
+		"help.shortcuts":       {"F1"},
+		"app.quit":             {"Ctrl+Q"},
+	})
+}
+
+// VimKeymap 类vim键位，在默认键位基础上增加单键导航
+func VimKeymap() Keymap {
+	return DefaultKeymap().Merge(mustKeymap(map[string][]string{
+		"page.next":       {"J", "L", "Right", "Space"},
+		"page.previous":   {"K", "H", "Left", "Shift+Space"},
+		"page.first":      {"G"},
+		"page.last":       {"Shift+G"},
+		"search.find":     {"/", "Ctrl+F"},
+		"view.focus_mode": {"Z", "Ctrl+Shift+F"},
+		"help.shortcuts":  {"Shift+/", "F1"},
+	}))
+}
+
+// Preset 获取键位预设
+func Preset(name string) (Keymap, error) {
+	switch name {
+	case "", PresetDefault:
+		return DefaultKeymap(), nil
+	case PresetVim:
+		return VimKeymap(), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownPreset, name)
+}
+
+// Presets 所有键位预设名称
+func Presets() []string {
+	return []string{PresetDefault, PresetVim}
+}
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Registry 命令注册表，维护命令及其按键绑定
+type Registry struct {
+	commands map[string]*Command
+	order    []string
+	keymap   Keymap
+	chords   map[Chord]string
+	mu       sync.RWMutex
+}
+
+// NewRegistry 创建命令注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		commands: make(map[string]*Command),
+		keymap:   make(Keymap),
+		chords:   make(map[Chord]string),
+	}
+}
+
+// Register 注册命令，同ID的命令会被替换
+func (r *Registry) Register(cmd Command) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.commands[cmd.ID]; !exists {
+		r.order = append(r.order, cmd.ID)
+	}
+	r.commands[cmd.ID] = &cmd
+}
+
+// Get 获取命令
+func (r *Registry) Get(id string) (Command, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cmd, ok := r.commands[id]
+	if !ok {
+		return Command{}, fmt.Errorf("%w: %s", ErrUnknownCommand, id)
+	}
+	return *cmd, nil
+}
+
+// All 按注册顺序返回所有命令
+func (r *Registry) All() []Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	commands := make([]Command, 0, len(r.order))
+	for _, id := range r.order {
+		commands = append(commands, *r.commands[id])
+	}
+	return commands
+}
+
+// Execute 执行命令
+func (r *Registry) Execute(id string) error {
+	cmd, err := r.Get(id)
+	if err != nil {
+		return err
+	}
+	if cmd.Run != nil {
+		cmd.Run()
+	}
+	return nil
+}
+
+// SetKeymap 替换全部按键绑定。同一按键组合绑定到多个命令时，按命令ID排序后靠前的生效
+func (r *Registry) SetKeymap(km Keymap) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keymap = km.Clone()
+	r.chords = make(map[Chord]string)
+	for id, chords := range r.keymap {
+		for _, chord := range chords {
+			if existing, ok := r.chords[chord]; ok && existing < id {
+				continue
+			}
+			r.chords[chord] = id
+		}
+	}
+}
+
+// GetKeymap 获取当前按键绑定
+func (r *Registry) GetKeymap() Keymap {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.keymap.Clone()
+}
+
+// Bindings 获取命令绑定的按键组合
+func (r *Registry) Bindings(id string) []Chord {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Chord(nil), r.keymap[id]...)
+}
+
+// Lookup 查找按键组合绑定的命令
+func (r *Registry) Lookup(chord Chord) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.chords[chord]
+	return id, ok
+}
+
+// Chords 所有已绑定的按键组合
+func (r *Registry) Chords() []Chord {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chords := make([]Chord, 0, len(r.chords))
+	for chord := range r.chords {
+		chords = append(chords, chord)
+	}
+	return chords
+}
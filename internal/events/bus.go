@@ -15,6 +15,20 @@ const (
 	ThemeChanged      EventType = "theme_changed"
 	AIAnalysisRequest EventType = "ai_analysis_request"
 	AIAnalysisResult  EventType = "ai_analysis_result"
+	AIAnalysisChunk   EventType = "ai_analysis_chunk"
+	AIAnalysisFailed  EventType = "ai_analysis_failed"
+
+	AIAnalysisCancelled EventType = "ai_analysis_cancelled"
+	AICacheClearRequest EventType = "ai_cache_clear_request"
+	AICacheCleared      EventType = "ai_cache_cleared"
+
+	HighlightsImportRequest EventType = "highlights_import_request"
+	HighlightsImported      EventType = "highlights_imported"
+
+	DocumentOpenRequest EventType = "document_open_request"
+	ErrorOccurred       EventType = "error_occurred"
+	ZoomChanged         EventType = "zoom_changed"
+	TabsChanged         EventType = "tabs_changed"
 )
 
 // Event 事件数据结构
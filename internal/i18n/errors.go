@@ -0,0 +1,7 @@
+package i18n
+
+import "errors"
+
+var (
+	ErrUnsupportedLocale = errors.New("unsupported locale")
+)
@@ -0,0 +1,39 @@
+package i18n
+
+import (
+	"time"
+)
+
+// FormatNumber 按当前语言格式化数字，如千位分隔符
+func FormatNumber(n interface{}) string {
+	current.mu.RLock()
+	printer := current.printer
+	current.mu.RUnlock()
+
+	switch v := n.(type) {
+	case float32:
+		return printer.Sprintf("%.1f", v)
+	case float64:
+		return printer.Sprintf("%.1f", v)
+	}
+	return printer.Sprintf("%d", n)
+}
+
+// FormatPercent 格式化百分比，ratio 为比例，1表示100%
+func FormatPercent(ratio float64) string {
+	current.mu.RLock()
+	printer := current.printer
+	current.mu.RUnlock()
+
+	return printer.Sprintf("%.0f%%", ratio*100)
+}
+
+// FormatDate 按当前语言格式化日期
+func FormatDate(t time.Time) string {
+	return t.Format(T("format.date"))
+}
+
+// FormatDateTime 按当前语言格式化日期和时间
+func FormatDateTime(t time.Time) string {
+	return t.Format(T("format.datetime"))
+}
@@ -0,0 +1,145 @@
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"sync"
+)
+
+// 支持的语言
+const (
+	LocaleChinese = "zh"
+	LocaleEnglish = "en"
+)
+
+// LocaleAuto 跟随系统语言的配置值
+const LocaleAuto = ""
+
+//go:embed locales/*.json
+var catalogs embed.FS
+
+// supported 支持的语言，顺序即 Locales 的返回顺序
+var supported = []language.Tag{language.Chinese, language.English}
+
+// Args 消息模板参数，整数和浮点数会按当前语言格式化
+type Args map[string]interface{}
+
+// catalog 当前语言的消息目录
+type catalog struct {
+	bundle    *goi18n.Bundle
+	localizer *goi18n.Localizer
+	tag       language.Tag
+	printer   *message.Printer
+	mu        sync.RWMutex
+}
+
+var current = newCatalog()
+
+// newCatalog 加载内置的消息目录，默认使用英文
+func newCatalog() *catalog {
+	bundle := goi18n.NewBundle(language.English)
+	for _, tag := range supported {
+		if _, err := bundle.LoadMessageFileFS(catalogs, "locales/"+tag.String()+".json"); err != nil {
+			panic(err)
+		}
+	}
+
+	c := &catalog{bundle: bundle}
+	c.use(language.English)
+	return c
+}
+
+// use 切换到指定语言
+func (c *catalog) use(tag language.Tag) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tag = tag
+	c.localizer = goi18n.NewLocalizer(c.bundle, tag.String())
+	c.printer = message.NewPrinter(tag)
+}
+
+// Init 设置界面语言。locale 为 LocaleAuto 时按系统语言选择，系统语言不受支持时使用英文
+func Init(locale string) error {
+	if locale == LocaleAuto {
+		current.use(detectLocale())
+		return nil
+	}
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedLocale, locale)
+	}
+	matched, _, confidence := language.NewMatcher(supported).Match(tag)
+	if confidence == language.No {
+		return fmt.Errorf("%w: %s", ErrUnsupportedLocale, locale)
+	}
+	current.use(base(matched))
+	return nil
+}
+
+// Locale 当前界面语言
+func Locale() string {
+	current.mu.RLock()
+	defer current.mu.RUnlock()
+
+	return current.tag.String()
+}
+
+// Locales 支持的界面语言
+func Locales() []string {
+	locales := make([]string, len(supported))
+	for i, tag := range supported {
+		locales[i] = tag.String()
+	}
+	return locales
+}
+
+// T 翻译消息，找不到时返回消息ID
+func T(id string, args ...Args) string {
+	return localize(&goi18n.LocalizeConfig{MessageID: id, TemplateData: templateData(args)})
+}
+
+// N 翻译带数量的消息，按 count 选择单复数形式，模板中可以用 {{.Count}} 引用数量
+func N(id string, count int, args ...Args) string {
+	data := templateData(args)
+	data["Count"] = FormatNumber(count)
+	return localize(&goi18n.LocalizeConfig{MessageID: id, PluralCount: count, TemplateData: data})
+}
+
+func localize(config *goi18n.LocalizeConfig) string {
+	current.mu.RLock()
+	localizer := current.localizer
+	current.mu.RUnlock()
+
+	text, err := localizer.Localize(config)
+	if err != nil {
+		return config.MessageID
+	}
+	return text
+}
+
+// templateData 合并模板参数，并格式化其中的数字
+func templateData(args []Args) map[string]interface{} {
+	data := make(map[string]interface{})
+	for _, a := range args {
+		for k, v := range a {
+			switch v.(type) {
+			case int, int32, int64, uint, uint32, uint64, float32, float64:
+				data[k] = FormatNumber(v)
+			default:
+				data[k] = v
+			}
+		}
+	}
+	return data
+}
+
+// base 去掉地区等信息，只保留语言
+func base(tag language.Tag) language.Tag {
+	b, _ := tag.Base()
+	return language.Make(b.String())
+}
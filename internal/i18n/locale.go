@@ -0,0 +1,27 @@
+package i18n
+
+import (
+	"github.com/jeandeaual/go-locale"
+	"golang.org/x/text/language"
+)
+
+// detectLocale 按系统语言偏好选择界面语言
+func detectLocale() language.Tag {
+	locales, err := locale.GetLocales()
+	if err != nil || len(locales) == 0 {
+		return language.English
+	}
+
+	var preferred []language.Tag
+	for _, l := range locales {
+		if tag, err := language.Parse(l); err == nil {
+			preferred = append(preferred, tag)
+		}
+	}
+
+	matched, _, confidence := language.NewMatcher(supported).Match(preferred...)
+	if confidence == language.No {
+		return language.English
+	}
+	return base(matched)
+}
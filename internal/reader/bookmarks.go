@@ -0,0 +1,128 @@
+package reader
+
+import (
+	"ai-reader/internal/i18n"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// configBookmarks 书签配置键：文件路径 -> 书签列表
+const configBookmarks = "bookmarks"
+
+// bookmarkTitleLength 书签标题取页面开头的字符数
+const bookmarkTitleLength = 30
+
+// Bookmark 书签
+type Bookmark struct {
+	Page      int
+	Title     string
+	CreatedAt time.Time
+}
+
+// ToggleBookmark 为当前页添加或删除书签，返回操作后当前页是否有书签
+func (c *Controller) ToggleBookmark() (bool, error) {
+	c.mu.RLock()
+	doc := c.currentDoc
+	filename := c.currentFile
+	page := c.currentPage
+	c.mu.RUnlock()
+
+	if doc == nil {
+		return false, nil
+	}
+
+	bookmarks := c.GetBookmarks(filename)
+	for i, b := range bookmarks {
+		if b.Page == page {
+			c.saveBookmarks(filename, append(bookmarks[:i], bookmarks[i+1:]...))
+			return false, nil
+		}
+	}
+
+	content, err := doc.GetPage(page)
+	if err != nil {
+		return false, err
+	}
+	bookmarks = append(bookmarks, Bookmark{
+		Page:      page,
+		Title:     bookmarkTitle(content, page),
+		CreatedAt: time.Now(),
+	})
+	sort.Slice(bookmarks, func(i, j int) bool { return bookmarks[i].Page < bookmarks[j].Page })
+	c.saveBookmarks(filename, bookmarks)
+	return true, nil
+}
+
+// GetBookmarks 获取文档的书签，按页码排序
+func (c *Controller) GetBookmarks(filename string) []Bookmark {
+	all, ok := c.settings.Get(configBookmarks).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	items, ok := all[filename].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	bookmarks := make([]Bookmark, 0, len(items))
+	for _, item := range items {
+		data, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		page, ok := data["page"].(float64)
+		if !ok {
+			continue
+		}
+		b := Bookmark{Page: int(page)}
+		b.Title, _ = data["title"].(string)
+		if created, ok := data["created"].(string); ok {
+			b.CreatedAt, _ = time.Parse(time.RFC3339, created)
+		}
+		bookmarks = append(bookmarks, b)
+	}
+	sort.Slice(bookmarks, func(i, j int) bool { return bookmarks[i].Page < bookmarks[j].Page })
+	return bookmarks
+}
+
+// saveBookmarks 保存文档的书签，复制后整体写回配置
+func (c *Controller) saveBookmarks(filename string, bookmarks []Bookmark) {
+	all := make(map[string]interface{})
+	if existing, ok := c.settings.Get(configBookmarks).(map[string]interface{}); ok {
+		for k, v := range existing {
+			all[k] = v
+		}
+	}
+
+	if len(bookmarks) == 0 {
+		delete(all, filename)
+	} else {
+		items := make([]interface{}, 0, len(bookmarks))
+		for _, b := range bookmarks {
+			items = append(items, map[string]interface{}{
+				"page":    float64(b.Page),
+				"title":   b.Title,
+				"created": b.CreatedAt.Format(time.RFC3339),
+			})
+		}
+		all[filename] = items
+	}
+	c.settings.Set(configBookmarks, all)
+}
+
+// bookmarkTitle 用页面第一行非空文本作为书签标题
+func bookmarkTitle(content string, page int) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if utf8.RuneCountInString(line) > bookmarkTitleLength {
+			line = string([]rune(line)[:bookmarkTitleLength]) + "…"
+		}
+		return line
+	}
+	return i18n.T("page.number", i18n.Args{"Page": page})
+}
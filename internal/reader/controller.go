@@ -0,0 +1,644 @@
+package reader
+
+import (
+	"ai-reader/internal/events"
+	"ai-reader/pkg/animation"
+	"ai-reader/pkg/document"
+	"ai-reader/pkg/textstats"
+	"ai-reader/pkg/theme"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// 配置键
+const (
+	configPageTurnAnimation = "page_turn_animation"
+	configReducedMotion     = "reduced_motion"
+	configFontSize          = "font_size"     // 正文字号，0表示跟随主题
+	configDocumentZoom      = "document_zoom" // 各文档的缩放级别，按文件路径保存
+	configRecentDocuments   = "recent_documents"
+	configReadingSpeed      = "reading_speed" // 校准后的阅读速度
+)
+
+// maxRecentDocuments 最近文档列表的长度
+const maxRecentDocuments = 10
+
+// 缩放范围和步长
+const (
+	MinZoom  float32 = 0.5
+	MaxZoom  float32 = 3.0
+	ZoomStep float32 = 0.1
+)
+
+// TransitionFollowTheme 翻页动画跟随当前主题的配置
+const TransitionFollowTheme = "theme"
+
+// 用户输入类型，供 HandleUserInput 使用
+const (
+	InputOpenDocument   = "open_document"   // data: string 文件路径
+	InputCloseDocument  = "close_document"  // data: nil
+	InputNextPage       = "next_page"       // data: nil
+	InputPreviousPage   = "previous_page"   // data: nil
+	InputGoToPage       = "go_to_page"      // data: int 页码
+	InputSetZoom        = "set_zoom"        // data: float32 缩放级别
+	InputZoomIn         = "zoom_in"         // data: nil
+	InputZoomOut        = "zoom_out"        // data: nil
+	InputZoomReset      = "zoom_reset"      // data: nil
+	InputClearSelection = "clear_selection" // data: nil
+	InputSetTransition  = "set_transition"  // data: string 动画类型
+	InputReducedMotion  = "reduced_motion"  // data: bool
+	InputToggleBookmark = "toggle_bookmark" // data: nil
+	InputSwitchTab      = "switch_tab"      // data: int 标签页ID
+	InputCloseTab       = "close_tab"       // data: int 标签页ID
+	InputNextTab        = "next_tab"        // data: nil
+	InputPreviousTab    = "previous_tab"    // data: nil
+)
+
+// DocumentInfo DocumentOpened / DocumentClosed 事件的负载
+type DocumentInfo struct {
+	Filename string
+	Document document.Document
+}
+
+// Controller 阅读器控制器实现，以标签页管理打开的文档并负责其生命周期
+type Controller struct {
+	eventBus        *events.Bus
+	documentManager document.DocumentManager
+	themeManager    theme.ThemeManager
+	settings        Settings
+	view            ReaderView
+	selector        TextSelector
+
+	// 标签页
+	tabs      []*tab
+	activeTab *tab
+	nextTabID int
+
+	// 活动标签页的状态
+	currentDoc    document.Document
+	currentFile   string
+	currentPage   int
+	zoom          float32
+	transition    string
+	reducedMotion bool
+	pageOpenedAt  time.Time // 当前页开始显示的时间，用于校准阅读速度
+	calibrator    *textstats.Calibrator
+	mu            sync.RWMutex
+}
+
+// NewController 创建阅读器控制器
+func NewController(eventBus *events.Bus, documentManager document.DocumentManager, themeManager theme.ThemeManager, settings Settings) *Controller {
+	return &Controller{
+		eventBus:        eventBus,
+		documentManager: documentManager,
+		themeManager:    themeManager,
+		settings:        settings,
+		currentPage:     1,
+		zoom:            1.0,
+		transition:      TransitionFollowTheme,
+		calibrator:      textstats.NewCalibrator(textstats.DefaultReadingSpeed()),
+	}
+}
+
+func (c *Controller) Initialize() error {
+	// 读取翻页动画配置
+	c.mu.Lock()
+	if transition := c.settings.GetString(configPageTurnAnimation); transition != "" {
+		c.transition = transition
+	}
+	c.reducedMotion = c.settings.GetBool(configReducedMotion)
+	c.calibrator = textstats.NewCalibrator(c.savedReadingSpeed())
+	c.mu.Unlock()
+	
+	// 文件树等组件通过事件请求打开文档
+	c.eventBus.Subscribe(events.DocumentOpenRequest, func(event events.Event) {
+		if filename, ok := event.Payload.(string); ok {
+			c.HandleUserInput(InputOpenDocument, filename)
+		}
+	})
+	
+	// 主题变化时更新视图的配色和字号
+	c.eventBus.Subscribe(events.ThemeChanged, func(event events.Event) {
+		themeName, ok := event.Payload.(string)
+		if !ok {
+			return
+		}
+		t := c.themeManager.GetTheme(themeName)
+		if view := c.GetView(); view != nil && t != nil {
+			view.ApplyTheme(t)
+			view.SetFontSize(c.baseFontSize(t))
+		}
+	})
+	return nil
+}
+
+// AttachView 绑定视图和文本选择器
+func (c *Controller) AttachView(view ReaderView, selector TextSelector) {
+	c.mu.Lock()
+	c.view = view
+	c.selector = selector
+	doc := c.currentDoc
+	page := c.currentPage
+	zoom := c.zoom
+	c.mu.Unlock()
+
+	if view == nil {
+		return
+	}
+	view.SetFontSize(c.baseFontSize(c.themeManager.GetCurrentTheme()))
+	view.SetZoom(zoom)
+	if doc != nil {
+		view.DisplayDocument(doc)
+		view.SetPage(page)
+	}
+}
+
+// LoadDocument 在新标签页中打开文档，文档已经打开时切换到其标签页
+func (c *Controller) LoadDocument(filename string) error {
+	c.mu.RLock()
+	existing := c.findTab(func(t *tab) bool { return t.filename == filename })
+	c.mu.RUnlock()
+	if existing != nil {
+		return c.SwitchTab(existing.id)
+	}
+
+	t, created, err := c.newTab(filename)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return c.SwitchTab(t.id)
+	}
+
+	c.saveActiveTab()
+	c.activate(t)
+	c.addRecentDocument(filename)
+
+	c.eventBus.Publish(events.Event{
+		Type:    events.DocumentOpened,
+		Payload: DocumentInfo{Filename: filename, Document: t.doc},
+	})
+
+	return nil
+}
+
+// CloseDocument 关闭活动标签页
+func (c *Controller) CloseDocument() error {
+	id := c.GetActiveTab()
+	if id == 0 {
+		return nil
+	}
+	return c.CloseTab(id)
+}
+
+func (c *Controller) GetView() ReaderView {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.view
+}
+
+func (c *Controller) GetSelector() TextSelector {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.selector
+}
+
+func (c *Controller) GetPageTurner() PageTurner {
+	return c
+}
+
+// GetCurrentDocument 获取当前文档
+func (c *Controller) GetCurrentDocument() document.Document {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.currentDoc
+}
+
+// GetCurrentFile 获取当前文档的文件路径
+func (c *Controller) GetCurrentFile() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.currentFile
+}
+
+// GetCurrentPage 获取当前页码
+func (c *Controller) GetCurrentPage() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.currentPage
+}
+
+// GetTotalPages 获取总页数，没有文档时为1
+func (c *Controller) GetTotalPages() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.currentDoc == nil {
+		return 1
+	}
+	return c.currentDoc.GetPages()
+}
+
+// GetRecentDocuments 获取最近打开的文档，最近的在前
+func (c *Controller) GetRecentDocuments() []string {
+	items, ok := c.settings.Get(configRecentDocuments).([]interface{})
+	if !ok {
+		return nil
+	}
+	recent := make([]string, 0, len(items))
+	for _, item := range items {
+		if filename, ok := item.(string); ok {
+			recent = append(recent, filename)
+		}
+	}
+	return recent
+}
+
+// addRecentDocument 把文档移到最近文档列表的最前面
+func (c *Controller) addRecentDocument(filename string) {
+	recent := []interface{}{filename}
+	for _, existing := range c.GetRecentDocuments() {
+		if existing != filename && len(recent) < maxRecentDocuments {
+			recent = append(recent, existing)
+		}
+	}
+	c.settings.Set(configRecentDocuments, recent)
+}
+
+func (c *Controller) HandleUserInput(inputType string, data interface{}) {
+	var err error
+
+	switch inputType {
+	case InputOpenDocument:
+		filename, ok := data.(string)
+		if !ok {
+			err = fmt.Errorf("invalid input data for %s: %v", inputType, data)
+			break
+		}
+		err = c.LoadDocument(filename)
+	case InputCloseDocument:
+		err = c.CloseDocument()
+	case InputNextPage:
+		err = c.NextPage()
+	case InputPreviousPage:
+		err = c.PreviousPage()
+	case InputGoToPage:
+		pageNum, ok := data.(int)
+		if !ok {
+			err = fmt.Errorf("invalid input data for %s: %v", inputType, data)
+			break
+		}
+		err = c.GoToPage(pageNum)
+	case InputSetZoom:
+		level, ok := data.(float32)
+		if !ok {
+			err = fmt.Errorf("invalid input data for %s: %v", inputType, data)
+			break
+		}
+		c.SetZoom(level)
+	case InputZoomIn:
+		c.SetZoom(c.GetZoom() + ZoomStep)
+	case InputZoomOut:
+		c.SetZoom(c.GetZoom() - ZoomStep)
+	case InputZoomReset:
+		c.SetZoom(1.0)
+	case InputClearSelection:
+		if view := c.GetView(); view != nil {
+			view.ClearSelection()
+		}
+	case InputSetTransition:
+		transitionType, ok := data.(string)
+		if !ok {
+			err = fmt.Errorf("invalid input data for %s: %v", inputType, data)
+			break
+		}
+		c.SetTransition(transitionType)
+	case InputReducedMotion:
+		enabled, ok := data.(bool)
+		if !ok {
+			err = fmt.Errorf("invalid input data for %s: %v", inputType, data)
+			break
+		}
+		c.SetReducedMotion(enabled)
+	case InputToggleBookmark:
+		_, err = c.ToggleBookmark()
+	case InputSwitchTab:
+		id, ok := data.(int)
+		if !ok {
+			err = fmt.Errorf("invalid input data for %s: %v", inputType, data)
+			break
+		}
+		err = c.SwitchTab(id)
+	case InputCloseTab:
+		id, ok := data.(int)
+		if !ok {
+			err = fmt.Errorf("invalid input data for %s: %v", inputType, data)
+			break
+		}
+		err = c.CloseTab(id)
+	case InputNextTab:
+		err = c.cycleTab(1)
+	case InputPreviousTab:
+		err = c.cycleTab(-1)
+	default:
+		err = fmt.Errorf("unknown input type: %s", inputType)
+	}
+
+	if err != nil {
+		c.eventBus.Publish(events.Event{
+			Type:    events.ErrorOccurred,
+			Payload: err,
+		})
+	}
+}
+
+// Cleanup 关闭所有标签页的文档
+func (c *Controller) Cleanup() {
+	c.mu.Lock()
+	tabs := c.tabs
+	c.tabs = nil
+	c.activeTab = nil
+	c.currentDoc = nil
+	c.currentFile = ""
+	c.currentPage = 1
+	c.mu.Unlock()
+
+	for _, t := range tabs {
+		t.doc.Close()
+		c.eventBus.Publish(events.Event{
+			Type:    events.DocumentClosed,
+			Payload: DocumentInfo{Filename: t.filename, Document: t.doc},
+		})
+	}
+}
+
+// NextPage 下一页
+func (c *Controller) NextPage() error {
+	page := c.GetCurrentPage()
+	if page >= c.GetTotalPages() {
+		return nil
+	}
+	return c.GoToPage(page + 1)
+}
+
+// PreviousPage 上一页
+func (c *Controller) PreviousPage() error {
+	page := c.GetCurrentPage()
+	if page <= 1 {
+		return nil
+	}
+	return c.GoToPage(page - 1)
+}
+
+// GoToPage 跳转到指定页
+func (c *Controller) GoToPage(pageNum int) error {
+	c.mu.Lock()
+	if c.currentDoc == nil {
+		c.mu.Unlock()
+		return nil
+	}
+	if pageNum < 1 || pageNum > c.currentDoc.GetPages() {
+		c.mu.Unlock()
+		return document.ErrInvalidPage
+	}
+	if pageNum == c.currentPage {
+		c.mu.Unlock()
+		return nil
+	}
+	forward := pageNum > c.currentPage
+	// 只有顺序读到下一页时，上一页的停留时间才是阅读用时
+	sequential := pageNum == c.currentPage+1
+	doc, previous, openedAt := c.currentDoc, c.currentPage, c.pageOpenedAt
+	c.currentPage = pageNum
+	c.pageOpenedAt = time.Now()
+	view := c.view
+	c.mu.Unlock()
+
+	if sequential {
+		c.recordReading(doc, previous, time.Since(openedAt))
+	}
+
+	if view != nil {
+		if err := view.TurnPage(pageNum, c.pageTransition(forward)); err != nil {
+			return err
+		}
+	}
+
+	c.publishPageChanged()
+	return nil
+}
+
+// GetReadingSpeed 获取按用户阅读节奏校准的阅读速度
+func (c *Controller) GetReadingSpeed() textstats.ReadingSpeed {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.calibrator.Speed()
+}
+
+// recordReading 用一页的阅读用时校准阅读速度
+func (c *Controller) recordReading(doc document.Document, page int, elapsed time.Duration) {
+	content, err := doc.GetPage(page)
+	if err != nil {
+		return
+	}
+	stats := textstats.Compute(content)
+
+	c.mu.Lock()
+	accepted := c.calibrator.Record(stats, elapsed)
+	speed := c.calibrator.Speed()
+	c.mu.Unlock()
+
+	if !accepted {
+		return
+	}
+	c.settings.Set(configReadingSpeed, map[string]interface{}{
+		"cjk_chars_per_minute":   speed.CJKCharsPerMinute,
+		"latin_words_per_minute": speed.LatinWordsPerMinute,
+	})
+}
+
+// savedReadingSpeed 读取保存的阅读速度，没有时使用默认速度
+func (c *Controller) savedReadingSpeed() textstats.ReadingSpeed {
+	speed := textstats.DefaultReadingSpeed()
+	if saved, ok := c.settings.Get(configReadingSpeed).(map[string]interface{}); ok {
+		if v, ok := saved["cjk_chars_per_minute"].(float64); ok {
+			speed.CJKCharsPerMinute = v
+		}
+		if v, ok := saved["latin_words_per_minute"].(float64); ok {
+			speed.LatinWordsPerMinute = v
+		}
+	}
+	return speed
+}
+
+// SetZoom 设置缩放级别并为当前文档保存
+func (c *Controller) SetZoom(level float32) {
+	level = clampZoom(level)
+	c.applyZoom(level)
+
+	filename := c.GetCurrentFile()
+	if filename == "" {
+		return
+	}
+
+	// 复制后整体写回，避免修改配置中共享的map
+	saved := make(map[string]interface{})
+	if existing, ok := c.settings.Get(configDocumentZoom).(map[string]interface{}); ok {
+		for k, v := range existing {
+			saved[k] = v
+		}
+	}
+	if level == 1.0 {
+		delete(saved, filename)
+	} else {
+		saved[filename] = float64(level)
+	}
+	c.settings.Set(configDocumentZoom, saved)
+}
+
+// GetZoom 获取当前缩放级别
+func (c *Controller) GetZoom() float32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.zoom
+}
+
+// applyZoom 更新缩放级别并通知视图
+func (c *Controller) applyZoom(level float32) {
+	c.mu.Lock()
+	changed := level != c.zoom
+	c.zoom = level
+	view := c.view
+	c.mu.Unlock()
+
+	if view != nil {
+		view.SetZoom(level)
+	}
+	if changed {
+		c.eventBus.Publish(events.Event{
+			Type:    events.ZoomChanged,
+			Payload: level,
+		})
+	}
+}
+
+// savedZoom 读取文档保存的缩放级别，没有时为1
+func (c *Controller) savedZoom(filename string) float32 {
+	if saved, ok := c.settings.Get(configDocumentZoom).(map[string]interface{}); ok {
+		if level, ok := saved[filename].(float64); ok {
+			return clampZoom(float32(level))
+		}
+	}
+	return 1.0
+}
+
+// baseFontSize 计算基准字号：优先使用 font_size 配置，否则使用主题的正文字号
+func (c *Controller) baseFontSize(t theme.Theme) float32 {
+	if size := c.settings.GetFloat(configFontSize); size > 0 {
+		return float32(size)
+	}
+	if t != nil {
+		return t.GetFonts().Primary.Size
+	}
+	return 0
+}
+
+// clampZoom 把缩放级别限制在范围内并舍入到百分之一，避免步进累积误差
+func clampZoom(level float32) float32 {
+	if level < MinZoom {
+		level = MinZoom
+	}
+	if level > MaxZoom {
+		level = MaxZoom
+	}
+	return float32(math.Round(float64(level)*100) / 100)
+}
+
+// SetTransition 设置翻页动画，TransitionFollowTheme 表示使用主题配置
+func (c *Controller) SetTransition(transitionType string) {
+	c.mu.Lock()
+	c.transition = transitionType
+	c.mu.Unlock()
+
+	c.settings.Set(configPageTurnAnimation, transitionType)
+}
+
+// GetTransition 获取当前设置的翻页动画
+func (c *Controller) GetTransition() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.transition
+}
+
+// GetTransitionTypes 获取支持的翻页动画类型
+func (c *Controller) GetTransitionTypes() []string {
+	return animation.Names()
+}
+
+// SetReducedMotion 设置减少动态效果，开启后翻页不播放动画
+func (c *Controller) SetReducedMotion(enabled bool) {
+	c.mu.Lock()
+	c.reducedMotion = enabled
+	c.mu.Unlock()
+
+	c.settings.Set(configReducedMotion, enabled)
+}
+
+// IsReducedMotion 是否开启了减少动态效果
+func (c *Controller) IsReducedMotion() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.reducedMotion
+}
+
+// pageTransition 根据配置和当前主题计算翻页动画参数
+func (c *Controller) pageTransition(forward bool) PageTransition {
+	c.mu.RLock()
+	transitionType := c.transition
+	reducedMotion := c.reducedMotion
+	c.mu.RUnlock()
+
+	result := PageTransition{Type: "none", Forward: forward}
+	if reducedMotion {
+		return result
+	}
+
+	config := theme.AnimationConfig{PageTurnType: "none", Easing: "linear"}
+	if current := c.themeManager.GetCurrentTheme(); current != nil {
+		config = current.GetAnimation()
+	}
+
+	if transitionType == "" || transitionType == TransitionFollowTheme {
+		transitionType = config.PageTurnType
+	}
+	if animation.Get(transitionType) == nil {
+		transitionType = "none"
+	}
+
+	result.Type = transitionType
+	result.Duration = time.Duration(config.PageTurnDuration) * time.Millisecond
+	result.Easing = config.Easing
+	return result
+}
+
+// publishPageChanged 发布页面变化事件
+func (c *Controller) publishPageChanged() {
+	c.eventBus.Publish(events.Event{
+		Type: events.PageChanged,
+		Payload: map[string]interface{}{
+			"current": c.GetCurrentPage(),
+			"total":   c.GetTotalPages(),
+		},
+	})
+}
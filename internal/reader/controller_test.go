@@ -0,0 +1,461 @@
+package reader
+
+import (
+	"ai-reader/internal/events"
+	"ai-reader/pkg/document"
+	"ai-reader/pkg/theme"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// fakeDocument 内存中的分页文档
+type fakeDocument struct {
+	name   string
+	pages  []string
+	closed bool
+}
+
+func (d *fakeDocument) GetContent() (string, error) { return fmt.Sprint(d.pages), nil }
+func (d *fakeDocument) GetTitle() string            { return d.name }
+func (d *fakeDocument) GetPages() int               { return len(d.pages) }
+func (d *fakeDocument) GetMetadata() document.Metadata {
+	return document.Metadata{Title: d.name, PageCount: len(d.pages)}
+}
+func (d *fakeDocument) Search(string) ([]document.SearchResult, error) { return nil, nil }
+func (d *fakeDocument) Close() error                                   { d.closed = true; return nil }
+
+func (d *fakeDocument) GetPage(pageNum int) (string, error) {
+	if pageNum < 1 || pageNum > len(d.pages) {
+		return "", document.ErrInvalidPage
+	}
+	return d.pages[pageNum-1], nil
+}
+
+// fakeManager 按文件名返回预先登记页数的文档，每次加载都创建新的实例
+type fakeManager struct {
+	mu     sync.Mutex
+	pages  map[string]int
+	loaded []*fakeDocument
+}
+
+func (m *fakeManager) RegisterLoader(document.DocumentLoader) {}
+func (m *fakeManager) GetSupportedFormats() []string          { return nil }
+
+func (m *fakeManager) LoadDocument(filename string) (document.Document, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count, ok := m.pages[filename]
+	if !ok {
+		return nil, document.ErrUnsupportedFormat
+	}
+	doc := &fakeDocument{name: filename}
+	for i := 1; i <= count; i++ {
+		doc.pages = append(doc.pages, fmt.Sprintf("%s page %d", filename, i))
+	}
+	m.loaded = append(m.loaded, doc)
+	return doc, nil
+}
+
+// fakeSettings 内存中的配置
+type fakeSettings struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+}
+
+func newFakeSettings() *fakeSettings {
+	return &fakeSettings{values: make(map[string]interface{})}
+}
+
+func (s *fakeSettings) Get(key string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key]
+}
+
+func (s *fakeSettings) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+func (s *fakeSettings) GetString(key string) string { v, _ := s.Get(key).(string); return v }
+func (s *fakeSettings) GetInt(key string) int       { v, _ := s.Get(key).(float64); return int(v) }
+func (s *fakeSettings) GetBool(key string) bool     { v, _ := s.Get(key).(bool); return v }
+func (s *fakeSettings) GetFloat(key string) float64 { v, _ := s.Get(key).(float64); return v }
+
+// fakeView 记录控制器对视图的调用，state 是切换标签页时保存的视图状态
+type fakeView struct {
+	doc       document.Document
+	page      int
+	zoom      float32
+	scroll    float32
+	selection Selection
+	state     ViewState
+}
+
+func (v *fakeView) DisplayDocument(doc document.Document) error {
+	v.doc, v.page = doc, 1
+	return nil
+}
+func (v *fakeView) SetPage(pageNum int) error                    { v.page = pageNum; return nil }
+func (v *fakeView) TurnPage(pageNum int, _ PageTransition) error { v.page = pageNum; return nil }
+func (v *fakeView) GetCurrentPage() int                          { return v.page }
+func (v *fakeView) SetZoom(level float32)                        { v.zoom = level }
+func (v *fakeView) GetZoom() float32                             { return v.zoom }
+func (v *fakeView) SetFontSize(float32)                          {}
+func (v *fakeView) ApplyTheme(theme.Theme)                       {}
+func (v *fakeView) GetSelectedText() string                      { return v.selection.Text }
+func (v *fakeView) ClearSelection()                              { v.selection = Selection{} }
+func (v *fakeView) ScrollToPosition(position float32)            { v.scroll = position }
+func (v *fakeView) GetScrollPosition() float32                   { return v.scroll }
+func (v *fakeView) Snapshot() ViewState                          { return v.state }
+func (v *fakeView) Refresh()                                     {}
+func (v *fakeView) SelectRange(start, end int) {
+	v.selection = Selection{Start: start, End: end, Text: "selected"}
+}
+
+// fakeSelector 不做任何事的文本选择器
+type fakeSelector struct{}
+
+func (fakeSelector) StartSelection(x, y float32)         {}
+func (fakeSelector) UpdateSelection(x, y float32)        {}
+func (fakeSelector) EndSelection() string                { return "" }
+func (fakeSelector) GetSelectionBounds() (int, int)      { return 0, 0 }
+func (fakeSelector) SelectWord(x, y float32) string      { return "" }
+func (fakeSelector) SelectSentence(x, y float32) string  { return "" }
+func (fakeSelector) SelectParagraph(x, y float32) string { return "" }
+
+// newTestController 创建绑定了假视图的控制器，a.txt 有3页，b.txt 有5页
+func newTestController(t *testing.T, settings *fakeSettings) (*Controller, *fakeView, *fakeManager) {
+	t.Helper()
+	manager := &fakeManager{pages: map[string]int{"a.txt": 3, "b.txt": 5}}
+	themes := theme.NewManager(filepath.Join(t.TempDir(), "theme.json"))
+	c := NewController(events.NewBus(), manager, themes, settings)
+	if err := c.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	view := &fakeView{}
+	c.AttachView(view, fakeSelector{})
+	return c, view, manager
+}
+
+func TestPageNavigation(t *testing.T) {
+	c, view, _ := newTestController(t, newFakeSettings())
+	if err := c.LoadDocument("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		action   func() error
+		wantErr  error
+		wantPage int
+	}{
+		{"next", c.NextPage, nil, 2},
+		{"next to last", c.NextPage, nil, 3},
+		{"next past last", c.NextPage, nil, 3},
+		{"go to first", func() error { return c.GoToPage(1) }, nil, 1},
+		{"previous before first", c.PreviousPage, nil, 1},
+		{"go to zero", func() error { return c.GoToPage(0) }, document.ErrInvalidPage, 1},
+		{"go past end", func() error { return c.GoToPage(4) }, document.ErrInvalidPage, 1},
+		{"go to last", func() error { return c.GoToPage(3) }, nil, 3},
+		{"previous", c.PreviousPage, nil, 2},
+	}
+	for _, tt := range tests {
+		if err := tt.action(); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if got := c.GetCurrentPage(); got != tt.wantPage {
+			t.Fatalf("%s: page = %d, want %d", tt.name, got, tt.wantPage)
+		}
+		if view.page != tt.wantPage {
+			t.Fatalf("%s: view page = %d, want %d", tt.name, view.page, tt.wantPage)
+		}
+	}
+}
+
+func TestPageNavigationWithoutDocument(t *testing.T) {
+	c, _, _ := newTestController(t, newFakeSettings())
+	for _, action := range []func() error{c.NextPage, c.PreviousPage, func() error { return c.GoToPage(2) }} {
+		if err := action(); err != nil {
+			t.Fatalf("error = %v, want nil", err)
+		}
+	}
+	if c.GetCurrentPage() != 1 || c.GetTotalPages() != 1 {
+		t.Fatalf("page %d of %d, want 1 of 1", c.GetCurrentPage(), c.GetTotalPages())
+	}
+}
+
+func TestClampZoom(t *testing.T) {
+	tests := []struct {
+		level float32
+		want  float32
+	}{
+		{0, MinZoom},
+		{0.49, MinZoom},
+		{0.5, 0.5},
+		{1.234, 1.23},
+		{1.2999999, 1.3},
+		{3.0, MaxZoom},
+		{10, MaxZoom},
+	}
+	for _, tt := range tests {
+		if got := clampZoom(tt.level); got != tt.want {
+			t.Errorf("clampZoom(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestZoomPersistence(t *testing.T) {
+	settings := newFakeSettings()
+	c, view, _ := newTestController(t, settings)
+	if err := c.LoadDocument("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	savedZoom := func() (float64, bool) {
+		saved, _ := settings.Get(configDocumentZoom).(map[string]interface{})
+		level, ok := saved["a.txt"].(float64)
+		return level, ok
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		data      interface{}
+		wantZoom  float32
+		wantSaved bool
+	}{
+		{"set", InputSetZoom, float32(1.5), 1.5, true},
+		{"zoom in", InputZoomIn, nil, 1.6, true},
+		{"set above max", InputSetZoom, float32(5), MaxZoom, true},
+		{"zoom in at max", InputZoomIn, nil, MaxZoom, true},
+		{"reset removes saved level", InputZoomReset, nil, 1, false},
+		{"set below min", InputSetZoom, float32(0.1), MinZoom, true},
+		{"zoom out at min", InputZoomOut, nil, MinZoom, true},
+		{"set", InputSetZoom, float32(1.2), 1.2, true},
+	}
+	for _, tt := range tests {
+		c.HandleUserInput(tt.input, tt.data)
+		if got := c.GetZoom(); got != tt.wantZoom {
+			t.Fatalf("%s: zoom = %v, want %v", tt.name, got, tt.wantZoom)
+		}
+		if view.zoom != tt.wantZoom {
+			t.Fatalf("%s: view zoom = %v, want %v", tt.name, view.zoom, tt.wantZoom)
+		}
+		level, ok := savedZoom()
+		if ok != tt.wantSaved || (ok && float32(level) != tt.wantZoom) {
+			t.Fatalf("%s: saved zoom = %v, %v", tt.name, level, ok)
+		}
+	}
+
+	// 重新打开文档时恢复保存的缩放级别，其他文档不受影响
+	if err := c.LoadDocument("b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetZoom(); got != 1 {
+		t.Fatalf("zoom of b.txt = %v, want 1", got)
+	}
+	if err := c.CloseTab(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadDocument("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetZoom(); got != 1.2 {
+		t.Fatalf("zoom of reopened a.txt = %v, want 1.2", got)
+	}
+}
+
+func TestTabs(t *testing.T) {
+	c, view, manager := newTestController(t, newFakeSettings())
+
+	files := func() string {
+		var names []string
+		for _, info := range c.GetTabs() {
+			names = append(names, info.Filename)
+		}
+		return fmt.Sprint(names)
+	}
+
+	if err := c.LoadDocument("missing.txt"); !errors.Is(err, document.ErrUnsupportedFormat) {
+		t.Fatalf("LoadDocument(missing.txt) error = %v", err)
+	}
+	if len(c.GetTabs()) != 0 || c.GetActiveTab() != 0 {
+		t.Fatalf("tabs after failed load = %v, active %d", files(), c.GetActiveTab())
+	}
+
+	// 新标签页插入在活动标签页之后并成为活动标签页
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := c.LoadDocument(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := files(); got != "[a.txt b.txt]" {
+		t.Fatalf("tabs = %s", got)
+	}
+	if c.GetCurrentFile() != "b.txt" || view.doc != c.GetCurrentDocument() {
+		t.Fatalf("active file = %s", c.GetCurrentFile())
+	}
+
+	// 离开标签页时保存页码和视图状态，切换回来时恢复
+	if err := c.GoToPage(4); err != nil {
+		t.Fatal(err)
+	}
+	view.state = ViewState{Scroll: 0.25, Selection: Selection{Text: "b", Start: 3, End: 4}}
+	if err := c.SwitchTab(1); err != nil {
+		t.Fatal(err)
+	}
+	if c.GetCurrentFile() != "a.txt" || c.GetCurrentPage() != 1 || view.page != 1 {
+		t.Fatalf("after switch: %s page %d, view page %d", c.GetCurrentFile(), c.GetCurrentPage(), view.page)
+	}
+	view.state = ViewState{}
+	c.HandleUserInput(InputNextTab, nil)
+	if c.GetCurrentFile() != "b.txt" || c.GetCurrentPage() != 4 || view.page != 4 {
+		t.Fatalf("after switching back: %s page %d, view page %d", c.GetCurrentFile(), c.GetCurrentPage(), view.page)
+	}
+	if view.scroll != 0.25 || view.selection.Start != 3 || view.selection.End != 4 {
+		t.Fatalf("restored scroll %v selection %+v", view.scroll, view.selection)
+	}
+
+	// 打开已经打开的文档时切换到其标签页
+	if err := c.LoadDocument("a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := files(); got != "[a.txt b.txt]" || c.GetCurrentFile() != "a.txt" {
+		t.Fatalf("tabs = %s, active %s", got, c.GetCurrentFile())
+	}
+	if len(manager.loaded) != 2 {
+		t.Fatalf("loaded %d documents, want 2", len(manager.loaded))
+	}
+
+	tests := []struct {
+		name       string
+		close      int
+		wantErr    error
+		wantTabs   string
+		wantActive string
+	}{
+		{"unknown tab", 99, ErrTabNotFound, "[a.txt b.txt]", "a.txt"},
+		{"inactive tab", 2, nil, "[a.txt]", "a.txt"},
+		{"last tab", 1, nil, "[]", ""},
+	}
+	for _, tt := range tests {
+		if err := c.CloseTab(tt.close); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if got := files(); got != tt.wantTabs || c.GetCurrentFile() != tt.wantActive {
+			t.Fatalf("%s: tabs = %s, active %q", tt.name, got, c.GetCurrentFile())
+		}
+	}
+	for _, doc := range manager.loaded {
+		if !doc.closed {
+			t.Errorf("%s was not closed", doc.name)
+		}
+	}
+	if view.doc != nil {
+		t.Fatalf("view shows %v after closing all tabs, want welcome page", view.doc)
+	}
+}
+
+func TestCloseActiveTabActivatesNeighbour(t *testing.T) {
+	c, _, _ := newTestController(t, newFakeSettings())
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := c.LoadDocument(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.HandleUserInput(InputCloseDocument, nil)
+	if c.GetCurrentFile() != "a.txt" || len(c.GetTabs()) != 1 {
+		t.Fatalf("active %q with %d tabs", c.GetCurrentFile(), len(c.GetTabs()))
+	}
+}
+
+func TestConcurrentLoadOpensOneTab(t *testing.T) {
+	c, _, manager := newTestController(t, newFakeSettings())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.LoadDocument("a.txt")
+		}()
+	}
+	wg.Wait()
+
+	if tabs := c.GetTabs(); len(tabs) != 1 {
+		t.Fatalf("%d tabs, want 1", len(tabs))
+	}
+	open := 0
+	for _, doc := range manager.loaded {
+		if !doc.closed {
+			open++
+		}
+	}
+	if open != 1 {
+		t.Fatalf("%d documents left open, want 1", open)
+	}
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	settings := newFakeSettings()
+	c, view, _ := newTestController(t, settings)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := c.LoadDocument(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.GoToPage(3); err != nil {
+		t.Fatal(err)
+	}
+	view.state = ViewState{Scroll: 0.5}
+	if err := c.SwitchTab(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.GoToPage(2); err != nil {
+		t.Fatal(err)
+	}
+	view.state = ViewState{Scroll: 0.75}
+	c.SaveSession()
+	c.Cleanup()
+
+	restored, restoredView, _ := newTestController(t, settings)
+	restored.RestoreSession()
+
+	tabs := restored.GetTabs()
+	if len(tabs) != 2 {
+		t.Fatalf("restored %d tabs, want 2", len(tabs))
+	}
+	want := []struct {
+		file   string
+		page   int
+		scroll float32
+	}{
+		{"a.txt", 2, 0.75},
+		{"b.txt", 3, 0.5},
+	}
+	for i, w := range want {
+		if tabs[i].Filename != w.file || tabs[i].Page != w.page {
+			t.Errorf("tab %d = %s page %d, want %s page %d", i, tabs[i].Filename, tabs[i].Page, w.file, w.page)
+		}
+	}
+	if restored.GetCurrentFile() != "a.txt" || restored.GetCurrentPage() != 2 {
+		t.Fatalf("active %s page %d, want a.txt page 2", restored.GetCurrentFile(), restored.GetCurrentPage())
+	}
+	if restoredView.page != 2 || restoredView.scroll != 0.75 {
+		t.Fatalf("view page %d scroll %v, want page 2 scroll 0.75", restoredView.page, restoredView.scroll)
+	}
+
+	// 切换到另一个标签页时恢复其滚动位置
+	if err := restored.SwitchTab(tabs[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	if restoredView.page != want[1].page || restoredView.scroll != want[1].scroll {
+		t.Fatalf("view page %d scroll %v after switch", restoredView.page, restoredView.scroll)
+	}
+}
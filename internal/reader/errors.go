@@ -0,0 +1,8 @@
+package reader
+
+import "errors"
+
+var (
+	// ErrTabNotFound 标签页不存在
+	ErrTabNotFound = errors.New("tab not found")
+)
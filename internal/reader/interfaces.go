@@ -2,7 +2,9 @@ package reader
 
 import (
 	"ai-reader/pkg/document"
+	"ai-reader/pkg/textstats"
 	"ai-reader/pkg/theme"
+	"time"
 )
 
 // ReaderView 阅读器视图接口
@@ -13,6 +15,9 @@ type ReaderView interface {
 	// SetPage 设置当前页
 	SetPage(pageNum int) error
 	
+	// TurnPage 播放翻页动画并切换到指定页
+	TurnPage(pageNum int, transition PageTransition) error
+	
 	// GetCurrentPage 获取当前页
 	GetCurrentPage() int
 	
@@ -22,6 +27,9 @@ type ReaderView interface {
 	// GetZoom 获取当前缩放级别
 	GetZoom() float32
 	
+	// SetFontSize 设置基准字号，实际字号为基准字号乘以缩放级别，0表示使用默认字号
+	SetFontSize(size float32)
+	
 	// ApplyTheme 应用主题
 	ApplyTheme(theme theme.Theme)
 	
@@ -34,10 +42,50 @@ type ReaderView interface {
 	// ScrollToPosition 滚动到指定位置
 	ScrollToPosition(position float32)
 	
+	// GetScrollPosition 获取滚动位置（0-1之间的比例）
+	GetScrollPosition() float32
+	
+	// Snapshot 获取滚动位置和选区，可以在任意goroutine中调用
+	Snapshot() ViewState
+	
+	// SelectRange 选中当前页中的区间并滚动到可见位置
+	SelectRange(start, end int)
+	
 	// Refresh 刷新显示
 	Refresh()
 }
 
+// PageTransition 翻页动画参数
+type PageTransition struct {
+	Type     string // 动画类型，"none" 表示不播放动画
+	Duration time.Duration
+	Easing   string
+	Forward  bool // 是否向后翻页
+}
+
+// Settings 阅读器使用的配置读写接口
+type Settings interface {
+	Get(key string) interface{}
+	Set(key string, value interface{})
+	GetString(key string) string
+	GetInt(key string) int
+	GetBool(key string) bool
+	GetFloat(key string) float64
+}
+
+// Selection 选中的文本及其rune偏移范围 [Start,End)
+type Selection struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// ViewState 视图的滚动位置和选区，切换标签页时保存到标签页
+type ViewState struct {
+	Scroll    float32 // 0-1之间的比例
+	Selection Selection
+}
+
 // TextSelector 文本选择器接口
 type TextSelector interface {
 	// StartSelection 开始选择
@@ -76,8 +124,17 @@ type PageTurner interface {
 	// SetTransition 设置翻页动画
 	SetTransition(transitionType string)
 	
+	// GetTransition 获取当前设置的翻页动画
+	GetTransition() string
+	
 	// GetTransitionTypes 获取支持的翻页动画类型
 	GetTransitionTypes() []string
+	
+	// SetReducedMotion 设置减少动态效果
+	SetReducedMotion(enabled bool)
+	
+	// IsReducedMotion 是否开启了减少动态效果
+	IsReducedMotion() bool
 }
 
 // ReaderController 阅读器控制器接口
@@ -85,12 +142,33 @@ type ReaderController interface {
 	// Initialize 初始化
 	Initialize() error
 	
-	// LoadDocument 加载文档
+	// LoadDocument 在新标签页中加载文档
 	LoadDocument(filename string) error
 	
-	// CloseDocument 关闭文档
+	// CloseDocument 关闭活动标签页的文档
 	CloseDocument() error
 	
+	// GetTabs 获取所有标签页，按显示顺序排列
+	GetTabs() []TabInfo
+	
+	// GetActiveTab 获取活动标签页的ID，没有打开文档时为0
+	GetActiveTab() int
+	
+	// SwitchTab 切换到指定标签页
+	SwitchTab(id int) error
+	
+	// CloseTab 关闭指定标签页
+	CloseTab(id int) error
+	
+	// SaveSession 保存打开的标签页及阅读位置
+	SaveSession()
+	
+	// RestoreSession 恢复上次保存的标签页
+	RestoreSession()
+	
+	// AttachView 绑定视图和文本选择器
+	AttachView(view ReaderView, selector TextSelector)
+	
 	// GetView 获取视图
 	GetView() ReaderView
 	
@@ -100,6 +178,27 @@ type ReaderController interface {
 	// GetPageTurner 获取翻页器
 	GetPageTurner() PageTurner
 	
+	// GetCurrentDocument 获取当前文档，没有打开文档时为nil
+	GetCurrentDocument() document.Document
+	
+	// GetCurrentFile 获取当前文档的文件路径
+	GetCurrentFile() string
+	
+	// GetCurrentPage 获取当前页码
+	GetCurrentPage() int
+	
+	// GetTotalPages 获取总页数
+	GetTotalPages() int
+	
+	// GetRecentDocuments 获取最近打开的文档，最近的在前
+	GetRecentDocuments() []string
+	
+	// GetBookmarks 获取文档的书签，按页码排序
+	GetBookmarks(filename string) []Bookmark
+	
+	// GetReadingSpeed 获取按用户阅读节奏校准的阅读速度
+	GetReadingSpeed() textstats.ReadingSpeed
+	
 	// HandleUserInput 处理用户输入
 	HandleUserInput(inputType string, data interface{})
 	
@@ -0,0 +1,81 @@
+package reader
+
+import (
+	"ai-reader/internal/events"
+)
+
+// configSession 会话配置键：打开的标签页和活动标签页
+const configSession = "session"
+
+// SaveSession 保存打开的标签页及其阅读位置，下次启动时由 RestoreSession 恢复
+func (c *Controller) SaveSession() {
+	tabs := c.GetTabs()
+	active := c.GetActiveTab()
+
+	saved := make([]interface{}, 0, len(tabs))
+	activeIndex := 0
+	for i, t := range tabs {
+		if t.ID == active {
+			activeIndex = i
+		}
+		saved = append(saved, map[string]interface{}{
+			"file":   t.Filename,
+			"page":   float64(t.Page),
+			"scroll": float64(t.Scroll),
+		})
+	}
+
+	c.settings.Set(configSession, map[string]interface{}{
+		"tabs":   saved,
+		"active": float64(activeIndex),
+	})
+}
+
+// RestoreSession 重新打开上次保存的标签页，无法打开的文档会被跳过
+func (c *Controller) RestoreSession() {
+	session, ok := c.settings.Get(configSession).(map[string]interface{})
+	if !ok {
+		return
+	}
+	saved, _ := session["tabs"].([]interface{})
+	activeIndex, _ := session["active"].(float64)
+
+	var active *tab
+	for i, item := range saved {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		filename, _ := entry["file"].(string)
+		if filename == "" {
+			continue
+		}
+		t, created, err := c.newTab(filename)
+		if err != nil || !created {
+			continue
+		}
+
+		c.mu.Lock()
+		if page, ok := entry["page"].(float64); ok && int(page) >= 1 && int(page) <= t.doc.GetPages() {
+			t.page = int(page)
+		}
+		if scroll, ok := entry["scroll"].(float64); ok {
+			t.scroll = float32(scroll)
+		}
+		c.mu.Unlock()
+
+		if active == nil || i == int(activeIndex) {
+			active = t
+		}
+
+		c.eventBus.Publish(events.Event{
+			Type:    events.DocumentOpened,
+			Payload: DocumentInfo{Filename: filename, Document: t.doc},
+		})
+	}
+
+	if active == nil {
+		return
+	}
+	c.activate(active)
+}
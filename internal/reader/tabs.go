@@ -0,0 +1,277 @@
+package reader
+
+import (
+	"ai-reader/internal/events"
+	"ai-reader/pkg/document"
+	"time"
+)
+
+// tab 一个打开的文档及其阅读状态。活动标签页的页码和缩放以控制器的字段为准，
+// 切换离开时才写回标签页
+type tab struct {
+	id        int
+	doc       document.Document
+	filename  string
+	page      int
+	zoom      float32
+	scroll    float32 // 页内滚动位置，0-1之间的比例
+	selection Selection
+}
+
+// TabInfo 标签页信息
+type TabInfo struct {
+	ID       int
+	Filename string
+	Document document.Document
+	Page     int
+	Zoom     float32
+	Scroll   float32 // 页内滚动位置，0-1之间的比例
+}
+
+// GetTabs 获取所有标签页，按显示顺序排列
+func (c *Controller) GetTabs() []TabInfo {
+	c.mu.RLock()
+	tabs := make([]TabInfo, 0, len(c.tabs))
+	for _, t := range c.tabs {
+		info := TabInfo{
+			ID:       t.id,
+			Filename: t.filename,
+			Document: t.doc,
+			Page:     t.page,
+			Zoom:     t.zoom,
+			Scroll:   t.scroll,
+		}
+		if t == c.activeTab {
+			info.Page = c.currentPage
+			info.Zoom = c.zoom
+		}
+		tabs = append(tabs, info)
+	}
+	active := c.activeTab
+	view := c.view
+	c.mu.RUnlock()
+
+	// 活动标签页的滚动位置直接从视图读取
+	if active != nil && view != nil {
+		for i := range tabs {
+			if tabs[i].ID == active.id {
+				tabs[i].Scroll = view.Snapshot().Scroll
+			}
+		}
+	}
+	return tabs
+}
+
+// GetActiveTab 获取活动标签页的ID，没有打开文档时为0
+func (c *Controller) GetActiveTab() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.activeTab == nil {
+		return 0
+	}
+	return c.activeTab.id
+}
+
+// SwitchTab 切换到指定标签页，恢复其页码、缩放、滚动位置和选中的文本
+func (c *Controller) SwitchTab(id int) error {
+	c.mu.RLock()
+	t := c.findTab(func(t *tab) bool { return t.id == id })
+	active := c.activeTab
+	c.mu.RUnlock()
+
+	if t == nil {
+		return ErrTabNotFound
+	}
+	if t == active {
+		return nil
+	}
+
+	c.saveActiveTab()
+	c.activate(t)
+	return nil
+}
+
+// CloseTab 关闭指定标签页，关闭的是活动标签页时切换到相邻的标签页
+func (c *Controller) CloseTab(id int) error {
+	c.mu.Lock()
+	index := -1
+	for i, t := range c.tabs {
+		if t.id == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		c.mu.Unlock()
+		return ErrTabNotFound
+	}
+	closed := c.tabs[index]
+	c.tabs = append(c.tabs[:index], c.tabs[index+1:]...)
+	wasActive := closed == c.activeTab
+	var next *tab
+	if wasActive && len(c.tabs) > 0 {
+		if index >= len(c.tabs) {
+			index = len(c.tabs) - 1
+		}
+		next = c.tabs[index]
+	}
+	c.mu.Unlock()
+
+	if wasActive {
+		c.activate(next)
+	} else {
+		c.publishTabsChanged()
+	}
+
+	err := closed.doc.Close()
+
+	c.eventBus.Publish(events.Event{
+		Type:    events.DocumentClosed,
+		Payload: DocumentInfo{Filename: closed.filename, Document: closed.doc},
+	})
+
+	return err
+}
+
+// cycleTab 按顺序切换到前一个或后一个标签页，首尾相接
+func (c *Controller) cycleTab(delta int) error {
+	c.mu.RLock()
+	count := len(c.tabs)
+	index := 0
+	for i, t := range c.tabs {
+		if t == c.activeTab {
+			index = i
+		}
+	}
+	c.mu.RUnlock()
+
+	if count < 2 {
+		return nil
+	}
+	index = ((index+delta)%count + count) % count
+
+	c.mu.RLock()
+	id := c.tabs[index].id
+	c.mu.RUnlock()
+	return c.SwitchTab(id)
+}
+
+// newTab 加载文档并在活动标签页之后插入新标签页，不切换到新标签页。
+// 加载期间同一文档已在其他标签页中打开时关闭刚加载的文档，返回已有的标签页，created为false
+func (c *Controller) newTab(filename string) (t *tab, created bool, err error) {
+	doc, err := c.documentManager.LoadDocument(filename)
+	if err != nil {
+		return nil, false, err
+	}
+	zoom := c.savedZoom(filename)
+
+	c.mu.Lock()
+	existing := c.findTab(func(t *tab) bool { return t.filename == filename })
+	if existing != nil {
+		c.mu.Unlock()
+		doc.Close()
+		return existing, false, nil
+	}
+	defer c.mu.Unlock()
+
+	c.nextTabID++
+	t = &tab{id: c.nextTabID, doc: doc, filename: filename, page: 1, zoom: zoom}
+
+	index := len(c.tabs)
+	for i, existing := range c.tabs {
+		if existing == c.activeTab {
+			index = i + 1
+		}
+	}
+	c.tabs = append(c.tabs, nil)
+	copy(c.tabs[index+1:], c.tabs[index:])
+	c.tabs[index] = t
+	return t, true, nil
+}
+
+// findTab 查找满足条件的标签页，调用方需持有锁
+func (c *Controller) findTab(match func(t *tab) bool) *tab {
+	for _, t := range c.tabs {
+		if match(t) {
+			return t
+		}
+	}
+	return nil
+}
+
+// saveActiveTab 把活动标签页的阅读状态写回标签页
+func (c *Controller) saveActiveTab() {
+	c.mu.RLock()
+	active := c.activeTab
+	page, zoom := c.currentPage, c.zoom
+	view := c.view
+	c.mu.RUnlock()
+
+	if active == nil {
+		return
+	}
+
+	var state ViewState
+	if view != nil {
+		state = view.Snapshot()
+	}
+
+	c.mu.Lock()
+	active.page = page
+	active.zoom = zoom
+	active.scroll = state.Scroll
+	active.selection = state.Selection
+	c.mu.Unlock()
+}
+
+// activate 把标签页设为活动标签页并在视图中恢复其状态，t为nil时显示欢迎页。
+// 标签页变化事件在恢复选区之前发布，订阅者先切换到新标签页再收到选区事件
+func (c *Controller) activate(t *tab) {
+	c.mu.Lock()
+	c.activeTab = t
+	c.currentDoc = nil
+	c.currentFile = ""
+	c.currentPage = 1
+	zoom := float32(1.0)
+	if t != nil {
+		c.currentDoc = t.doc
+		c.currentFile = t.filename
+		c.currentPage = t.page
+		zoom = t.zoom
+	}
+	c.pageOpenedAt = time.Now()
+	view := c.view
+	c.mu.Unlock()
+
+	c.publishTabsChanged()
+
+	if view != nil {
+		view.ClearSelection()
+		if t == nil {
+			view.DisplayDocument(nil)
+		} else {
+			view.DisplayDocument(t.doc)
+			if t.page > 1 {
+				view.SetPage(t.page)
+			}
+		}
+	}
+	c.applyZoom(zoom)
+
+	if view != nil && t != nil {
+		if t.selection.Text != "" {
+			view.SelectRange(t.selection.Start, t.selection.End)
+		}
+		view.ScrollToPosition(t.scroll)
+	}
+	c.publishPageChanged()
+}
+
+// publishTabsChanged 发布标签页变化事件
+func (c *Controller) publishTabsChanged() {
+	c.eventBus.Publish(events.Event{
+		Type:    events.TabsChanged,
+		Payload: c.GetActiveTab(),
+	})
+}
@@ -1,12 +1,25 @@
 package ui
 
 import (
+	"ai-reader/internal/ai"
 	"ai-reader/internal/events"
+	"ai-reader/internal/i18n"
+	"ai-reader/internal/reader"
+	"ai-reader/pkg/document"
+	"errors"
+	"fmt"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
+// streamRenderInterval 流式输出的最短渲染间隔
+const streamRenderInterval = 100 * time.Millisecond
+
 // AIPanel AI分析面板
 type AIPanel struct {
 	eventBus    *events.Bus
@@ -14,22 +27,63 @@ type AIPanel struct {
 	
 	// UI组件
 	analysisText  *widget.RichText
+	details       *fyne.Container // 概念卡片和参考资料列表
 	statusLabel   *widget.Label
+	typeSelect    *widget.Select
 	analyzeBtn    *widget.Button
+	cancelBtn     *widget.Button
 	clearBtn      *widget.Button
 	historyList   *widget.List
+	split         *container.Split
 	
 	// 状态
 	isAnalyzing   bool
-	analysisHistory []string
+	analysisHistory []*ai.AnalysisResult
 	selectedText  string
+	analysisTypes []string // 类型选择框中各项对应的分析类型
+	selectionStart int // 选中文本在页内的rune偏移，未知时为-1
+	selectionEnd  int
+	selectionSeq  atomic.Int64 // 收到的选择事件数，对齐完成时丢弃过时的选区
+	alignedText   string // 并排阅读时对照文档中对齐的文字，与选中的文本一起分析
+	sourceDoc     document.Document // 选中文本所在的文档，为nil时使用活动标签页的文档
+	sourcePage    int
+	result        *ai.AnalysisResult // 当前显示的分析结果，为nil时显示占位提示
+	requestID     string // 正在进行的分析的请求ID，其他请求的输出和结果被忽略
+	requestSeq    int    // 已发出的请求数，用于生成请求ID
+	
+	// 流式输出
+	streamed      string    // 到目前为止输出的正文
+	streamTokens  int       // 到目前为止输出的token数
+	firstChunkAt  time.Time // 收到第一段输出的时间，用于计算输出速度
+	lastRenderAt  time.Time // 上次渲染的时间，限制渲染频率
+	
+	// 每个标签页独立的分析状态
+	tabs       map[int]*aiTabState
+	activeTab  int
+	pendingTab int // 正在分析的请求所属的标签页
+	
+	// AlignSelection 并排阅读时返回选中文本在对照文档中对齐的文字，不在并排阅读时返回空。
+	// 在处理选择事件的goroutine中调用，可以等待较慢的工作
+	AlignSelection func(selection reader.Selection) string
+	// SelectionSource 返回主阅读区选中文本所在的文档和页码，在收到选择事件时调用
+	SelectionSource func() (document.Document, int)
+}
+
+// aiTabState 一个标签页的分析状态
+type aiTabState struct {
+	history      []*ai.AnalysisResult
+	selectedText string
+	result       *ai.AnalysisResult
 }
 
 // NewAIPanel 创建AI分析面板
 func NewAIPanel(eventBus *events.Bus) *AIPanel {
 	ap := &AIPanel{
 		eventBus:        eventBus,
-		analysisHistory: make([]string, 0),
+		analysisHistory: make([]*ai.AnalysisResult, 0),
+		selectionStart:  -1,
+		selectionEnd:    -1,
+		tabs:            make(map[int]*aiTabState),
 	}
 	
 	ap.initializeComponents()
@@ -44,17 +98,26 @@ func (ap *AIPanel) initializeComponents() {
 	// 分析结果显示区域
 	ap.analysisText = widget.NewRichText()
 	ap.analysisText.Wrapping = fyne.TextWrapWord
-	ap.analysisText.ParseMarkdown("*选择文本进行AI分析*")
+	ap.analysisText.ParseMarkdown(i18n.T("ai.placeholder"))
+	ap.details = container.NewVBox()
 	
 	// 状态标签
-	ap.statusLabel = widget.NewLabel("就绪")
+	ap.statusLabel = widget.NewLabel(i18n.T("status.ready"))
+	
+	// 分析类型，包括用户模板增加的类型
+	ap.typeSelect = widget.NewSelect(nil, nil)
+	ap.loadAnalysisTypes()
 	
 	// 分析按钮
-	ap.analyzeBtn = widget.NewButton("📝 分析选中文本", ap.handleAnalyze)
+	ap.analyzeBtn = widget.NewButton(i18n.T("ai.analyze"), ap.handleAnalyze)
 	ap.analyzeBtn.Disable() // 初始禁用
 	
+	// 取消按钮，分析进行中可用
+	ap.cancelBtn = widget.NewButton(i18n.T("ai.cancel"), ap.CancelAnalysis)
+	ap.cancelBtn.Disable()
+	
 	// 清除按钮
-	ap.clearBtn = widget.NewButton("🗑️ 清除", ap.handleClear)
+	ap.clearBtn = widget.NewButton(i18n.T("ai.clear"), ap.handleClear)
 	
 	// 历史记录列表
 	ap.historyList = widget.NewList(
@@ -66,7 +129,7 @@ func (ap *AIPanel) initializeComponents() {
 		},
 		func(id int, obj fyne.CanvasObject) {
 			if id < len(ap.analysisHistory) {
-				obj.(*widget.Label).SetText(ap.analysisHistory[id])
+				obj.(*widget.Label).SetText(historyTitle(ap.analysisHistory[id]))
 			}
 		},
 	)
@@ -74,49 +137,81 @@ func (ap *AIPanel) initializeComponents() {
 	ap.historyList.OnSelected = func(id int) {
 		if id < len(ap.analysisHistory) {
 			// 显示历史分析结果
-			ap.analysisText.ParseMarkdown(ap.analysisHistory[id])
+			ap.renderResult(ap.analysisHistory[id])
 		}
 	}
 }
 
+// loadAnalysisTypes 按提示模板列出分析类型。对照分析不单独列出，解释时有对齐的文字即进行对照分析
+func (ap *AIPanel) loadAnalysisTypes() {
+	ap.analysisTypes = nil
+	var names []string
+	for _, t := range ai.SupportedAnalysisTypes() {
+		if t == ai.AnalysisTypeCompare {
+			continue
+		}
+		ap.analysisTypes = append(ap.analysisTypes, t)
+		names = append(names, analysisTypeName(t))
+	}
+	ap.typeSelect.SetOptions(names)
+	ap.typeSelect.SetSelectedIndex(0)
+}
+
+// analysisTypeName 分析类型的显示名称，模板没有指定名称时按类型翻译
+func analysisTypeName(analysisType string) string {
+	if t, ok := ai.DefaultTemplates().Get(analysisType); ok && t.Name != "" {
+		return t.Name
+	}
+	return i18n.T("analysis." + analysisType)
+}
+
+// selectedAnalysisType 选择的分析类型，没有选择时为解释
+func (ap *AIPanel) selectedAnalysisType() string {
+	if i := ap.typeSelect.SelectedIndex(); i >= 0 && i < len(ap.analysisTypes) {
+		return ap.analysisTypes[i]
+	}
+	return ai.AnalysisTypeExplain
+}
+
 // setupLayout 设置布局
 func (ap *AIPanel) setupLayout() {
 	// 按钮栏
 	buttonBar := container.NewHBox(
 		ap.analyzeBtn,
+		ap.cancelBtn,
 		ap.clearBtn,
 	)
 	
 	// 分析结果区域（可滚动）
-	analysisScroll := container.NewScroll(ap.analysisText)
+	analysisScroll := container.NewScroll(container.NewVBox(ap.analysisText, ap.details))
 	analysisScroll.SetMinSize(fyne.NewSize(250, 200))
 	
 	// 历史记录区域
 	historyContainer := container.NewBorder(
-		widget.NewCard("", "分析历史", nil),
+		widget.NewCard("", i18n.T("ai.history"), nil),
 		nil, nil, nil,
 		container.NewScroll(ap.historyList),
 	)
 	historyContainer.Resize(fyne.NewSize(250, 150))
 	
 	// 主容器 - 垂直分割
-	mainContent := container.NewVSplit(
+	ap.split = container.NewVSplit(
 		container.NewBorder(
-			widget.NewCard("", "分析结果", nil),
-			buttonBar,
+			widget.NewCard("", i18n.T("ai.result"), nil),
+			container.NewVBox(ap.typeSelect, buttonBar),
 			nil, nil,
 			analysisScroll,
 		),
 		historyContainer,
 	)
-	mainContent.SetOffset(0.7) // 分析结果占70%
+	ap.split.SetOffset(0.7) // 分析结果占70%
 	
 	// 底部状态
 	ap.container = container.NewBorder(
 		nil,             // 顶部
 		ap.statusLabel,  // 底部状态
 		nil, nil,        // 左右
-		mainContent,     // 中心
+		ap.split,        // 中心
 	)
 }
 
@@ -124,76 +219,420 @@ func (ap *AIPanel) setupLayout() {
 func (ap *AIPanel) setupEventHandlers() {
 	// 监听文本选择事件
 	ap.eventBus.Subscribe(events.TextSelected, func(event events.Event) {
-		ap.selectedText = event.Payload.(string)
-		ap.analyzeBtn.Enable()
-		ap.statusLabel.SetText("已选择文本，可进行分析")
+		selection := event.Payload.(reader.Selection)
+		text := selection.Text
+		seq := ap.selectionSeq.Add(1)
+		var doc document.Document
+		var page int
+		if ap.SelectionSource != nil {
+			doc, page = ap.SelectionSource()
+		}
+		// 并排阅读时附上对照文档中对齐的段落，可能要等待建立索引
+		aligned := ""
+		if text != "" && ap.AlignSelection != nil {
+			aligned = ap.AlignSelection(selection)
+		}
+		fyne.Do(func() {
+			// 等待对齐期间又选中了其他文本
+			if seq != ap.selectionSeq.Load() {
+				return
+			}
+			if text != ap.selectedText {
+				// 为原来的选区进行的分析已经过时
+				ap.CancelAnalysis()
+			}
+			ap.selectionStart, ap.selectionEnd = selection.Start, selection.End
+			ap.selectedText = text
+			ap.alignedText = aligned
+			ap.sourceDoc, ap.sourcePage = doc, page
+			ap.analyzeBtn.Enable()
+			ap.statusLabel.SetText(ap.selectionStatus())
+		})
+	})
+	
+	// 监听AI分析输出事件，逐段显示
+	ap.eventBus.Subscribe(events.AIAnalysisChunk, func(event events.Event) {
+		chunk := event.Payload.(ai.AnalysisChunk)
+		fyne.Do(func() {
+			ap.handleChunk(chunk)
+		})
 	})
 	
 	// 监听AI分析结果事件
 	ap.eventBus.Subscribe(events.AIAnalysisResult, func(event events.Event) {
-		result := event.Payload.(string)
-		ap.displayAnalysisResult(result)
-		ap.addToHistory(result)
-		ap.isAnalyzing = false
-		ap.updateUIState()
+		result := event.Payload.(*ai.AnalysisResult)
+		fyne.Do(func() {
+			if !ap.isAnalyzing || result.RequestID != ap.requestID {
+				return
+			}
+			ap.finishAnalysis(result, i18n.T("ai.done"))
+		})
+	})
+	
+	// 监听AI分析失败事件，已经输出的部分作为结果保留
+	ap.eventBus.Subscribe(events.AIAnalysisFailed, func(event events.Event) {
+		err := event.Payload.(error)
+		fyne.Do(func() {
+			var requestErr *ai.RequestError
+			if !ap.isAnalyzing || errors.As(err, &requestErr) && requestErr.RequestID != ap.requestID {
+				return
+			}
+			if ap.streamed != "" {
+				ap.finishAnalysis(&ai.AnalysisResult{Content: ap.streamed}, i18n.T("ai.partial", i18n.Args{"Error": err}))
+				return
+			}
+			ap.isAnalyzing = false
+			ap.requestID = ""
+			ap.updateUIState()
+			if ap.pendingTab == ap.activeTab {
+				ap.statusLabel.SetText(i18n.T("ai.failed", i18n.Args{"Error": err}))
+			}
+		})
 	})
 }
 
+// CancelAnalysis 取消正在进行的分析，已经输出的部分作为结果保留
+func (ap *AIPanel) CancelAnalysis() {
+	if !ap.isAnalyzing {
+		return
+	}
+	ap.eventBus.Publish(events.Event{
+		Type:    events.AIAnalysisCancelled,
+		Payload: ap.requestID,
+	})
+	
+	if ap.streamed != "" {
+		ap.finishAnalysis(&ai.AnalysisResult{Content: ap.streamed}, i18n.T("ai.cancelled"))
+		return
+	}
+	ap.isAnalyzing = false
+	ap.requestID = ""
+	ap.updateUIState()
+	if ap.pendingTab == ap.activeTab {
+		ap.statusLabel.SetText(i18n.T("ai.cancelled"))
+	}
+}
+
+// handleChunk 显示流式输出。事件异步到达，其他请求的输出和早于已显示内容的输出被忽略
+func (ap *AIPanel) handleChunk(chunk ai.AnalysisChunk) {
+	if !ap.isAnalyzing || chunk.RequestID != ap.requestID || chunk.Tokens < ap.streamTokens {
+		return
+	}
+	if ap.firstChunkAt.IsZero() {
+		ap.firstChunkAt = time.Now()
+	}
+	ap.streamed = chunk.Content
+	ap.streamTokens = chunk.Tokens
+	
+	if ap.pendingTab != ap.activeTab || time.Since(ap.lastRenderAt) < streamRenderInterval {
+		return
+	}
+	ap.lastRenderAt = time.Now()
+	ap.analysisText.ParseMarkdown("## " + i18n.T("ai.result") + "\n\n" + ap.streamed)
+	ap.details.RemoveAll()
+	
+	rate := 0.0
+	if elapsed := time.Since(ap.firstChunkAt).Seconds(); elapsed > 0 {
+		rate = float64(ap.streamTokens) / elapsed
+	}
+	ap.statusLabel.SetText(i18n.T("ai.streaming", i18n.Args{"Rate": fmt.Sprintf("%.1f", rate)}))
+}
+
+// finishAnalysis 分析结束，显示结果并加入历史记录。分析期间切换了标签页时结果保存到发起请求的标签页
+func (ap *AIPanel) finishAnalysis(result *ai.AnalysisResult, status string) {
+	ap.isAnalyzing = false
+	ap.requestID = ""
+	ap.streamed = ""
+	if ap.pendingTab != ap.activeTab {
+		state := ap.tabState(ap.pendingTab)
+		state.result = result
+		state.history = appendHistory(state.history, result)
+		ap.updateUIState()
+		return
+	}
+	ap.displayAnalysisResult(result)
+	ap.statusLabel.SetText(status)
+	ap.addToHistory(result)
+	ap.updateUIState()
+}
+
+// resultText 分析结果的正文，没有正文时显示摘要，并列出关键词。概念和参考资料另外显示
+func resultText(result *ai.AnalysisResult) string {
+	var b strings.Builder
+	if result.Content != "" {
+		b.WriteString(result.Content)
+	} else {
+		b.WriteString(result.Summary)
+	}
+	if len(result.Keywords) > 0 {
+		b.WriteString("\n\n**" + i18n.T("ai.keywords") + "**: " + strings.Join(result.Keywords, ", "))
+	}
+	return b.String()
+}
+
+// historyTitle 历史记录中显示的文字，有摘要时显示摘要
+func historyTitle(result *ai.AnalysisResult) string {
+	if result.Summary != "" {
+		return result.Summary
+	}
+	return result.Content
+}
+
+// conceptCards 每个概念一张卡片，显示类别、定义和重要性
+func conceptCards(concepts []ai.Concept) []fyne.CanvasObject {
+	cards := make([]fyne.CanvasObject, 0, len(concepts))
+	for _, c := range concepts {
+		definition := widget.NewLabel(c.Definition)
+		definition.Wrapping = fyne.TextWrapWord
+		importance := widget.NewProgressBar()
+		importance.SetValue(float64(c.Importance))
+		importance.TextFormatter = func() string {
+			return i18n.T("ai.importance", i18n.Args{"Percent": fmt.Sprintf("%.0f", importance.Value*100)})
+		}
+		cards = append(cards, widget.NewCard(c.Name, c.Category, container.NewVBox(definition, importance)))
+	}
+	return cards
+}
+
+// referenceList 参考资料列表，有网址的标题可以点击打开
+func referenceList(references []ai.Reference) []fyne.CanvasObject {
+	items := make([]fyne.CanvasObject, 0, len(references))
+	for _, ref := range references {
+		title := "• " + ref.Title
+		if ref.Type != "" {
+			title += " (" + i18n.T("ai.reference_"+ref.Type) + ")"
+		}
+		var heading fyne.CanvasObject
+		if u, err := url.Parse(ref.URL); ref.URL != "" && err == nil {
+			link := widget.NewHyperlink(title, u)
+			link.Wrapping = fyne.TextWrapWord
+			heading = link
+		} else {
+			label := widget.NewLabel(title)
+			label.Wrapping = fyne.TextWrapWord
+			heading = label
+		}
+		items = append(items, heading)
+		if ref.Description != "" {
+			description := widget.NewLabel(ref.Description)
+			description.Wrapping = fyne.TextWrapWord
+			items = append(items, description)
+		}
+	}
+	return items
+}
+
 // handleAnalyze 处理分析请求
 func (ap *AIPanel) handleAnalyze() {
 	if ap.selectedText == "" {
 		return
 	}
 	
+	ap.requestSeq++
+	ap.requestID = fmt.Sprintf("analysis-%d", ap.requestSeq)
 	ap.isAnalyzing = true
+	ap.pendingTab = ap.activeTab
+	ap.streamed = ""
+	ap.streamTokens = 0
+	ap.firstChunkAt = time.Time{}
+	ap.lastRenderAt = time.Time{}
 	ap.updateUIState()
 	
-	// 发布AI分析请求事件
+	// 发布AI分析请求事件，解释时有对齐的文字则作为对照分析请求
+	request := ai.AnalysisRequest{
+		ID:           ap.requestID,
+		Text:         ap.selectedText,
+		AnalysisType: ap.selectedAnalysisType(),
+	}
+	request.Parameters = map[string]interface{}{
+		ai.ParamSelectionStart: ap.selectionStart,
+		ai.ParamSelectionEnd:   ap.selectionEnd,
+	}
+	if ap.sourceDoc != nil {
+		request.Parameters[ai.ParamDocument] = ap.sourceDoc
+		request.Parameters[ai.ParamPage] = ap.sourcePage
+	}
+	if request.AnalysisType == ai.AnalysisTypeTranslate {
+		// 译成界面语言
+		request.Language = i18n.Locale()
+	}
+	if ap.alignedText != "" {
+		if request.AnalysisType == ai.AnalysisTypeExplain {
+			request.AnalysisType = ai.AnalysisTypeCompare
+		}
+		request.Parameters[ai.ParamAlignedText] = ap.alignedText
+	}
 	ap.eventBus.Publish(events.Event{
 		Type:    events.AIAnalysisRequest,
-		Payload: ap.selectedText,
+		Payload: request,
 	})
 }
 
+// SetSelection 设置选中的文本、所在的文档和页码以及另一文档中对齐的文字，用于并排阅读
+func (ap *AIPanel) SetSelection(selection reader.Selection, aligned string, doc document.Document, page int) {
+	text := selection.Text
+	if text != ap.selectedText {
+		ap.CancelAnalysis()
+	}
+	ap.selectionStart, ap.selectionEnd = selection.Start, selection.End
+	ap.selectedText = text
+	ap.alignedText = aligned
+	ap.sourceDoc, ap.sourcePage = doc, page
+	if text != "" {
+		ap.analyzeBtn.Enable()
+	}
+	ap.statusLabel.SetText(ap.selectionStatus())
+	ap.updateUIState()
+}
+
+// SetAlignedText 设置对照文档中对齐的文字，为空时只分析选中的文本
+func (ap *AIPanel) SetAlignedText(aligned string) {
+	ap.alignedText = aligned
+	if ap.selectedText != "" && !ap.isAnalyzing {
+		ap.statusLabel.SetText(ap.selectionStatus())
+	}
+}
+
+// selectionStatus 选中文本后的状态提示
+func (ap *AIPanel) selectionStatus() string {
+	if ap.alignedText != "" {
+		return i18n.T("ai.selected_aligned")
+	}
+	return i18n.T("ai.selected")
+}
+
 // handleClear 处理清除操作
 func (ap *AIPanel) handleClear() {
-	ap.analysisText.ParseMarkdown("*选择文本进行AI分析*")
+	ap.analysisText.ParseMarkdown(i18n.T("ai.placeholder"))
 	ap.selectedText = ""
+	ap.selectionStart, ap.selectionEnd = -1, -1
+	ap.alignedText = ""
+	ap.sourceDoc = nil
+	ap.result = nil
+	ap.details.RemoveAll()
 	ap.analyzeBtn.Disable()
-	ap.statusLabel.SetText("就绪")
+	ap.statusLabel.SetText(i18n.T("status.ready"))
 }
 
 // displayAnalysisResult 显示分析结果
-func (ap *AIPanel) displayAnalysisResult(result string) {
-	ap.analysisText.ParseMarkdown("## 分析结果\n\n" + result)
-	ap.statusLabel.SetText("分析完成")
+func (ap *AIPanel) displayAnalysisResult(result *ai.AnalysisResult) {
+	ap.result = result
+	ap.renderResult(result)
+	ap.statusLabel.SetText(i18n.T("ai.done"))
+}
+
+// renderResult 显示分析结果的正文、概念卡片和参考资料
+func (ap *AIPanel) renderResult(result *ai.AnalysisResult) {
+	ap.analysisText.ParseMarkdown("## " + i18n.T("ai.result") + "\n\n" + resultText(result))
+	
+	var details []fyne.CanvasObject
+	if len(result.Concepts) > 0 {
+		details = append(details, widget.NewLabelWithStyle(i18n.T("ai.concepts"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		details = append(details, conceptCards(result.Concepts)...)
+	}
+	if len(result.References) > 0 {
+		details = append(details, widget.NewLabelWithStyle(i18n.T("ai.references"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		details = append(details, referenceList(result.References)...)
+	}
+	ap.details.Objects = details
+	ap.details.Refresh()
 }
 
 // addToHistory 添加到历史记录
-func (ap *AIPanel) addToHistory(result string) {
-	// 限制历史记录数量
-	if len(ap.analysisHistory) >= 10 {
-		ap.analysisHistory = ap.analysisHistory[1:]
+func (ap *AIPanel) addToHistory(result *ai.AnalysisResult) {
+	ap.analysisHistory = appendHistory(ap.analysisHistory, result)
+	ap.historyList.Refresh()
+}
+
+// appendHistory 添加历史记录，最多保留10条
+func appendHistory(history []*ai.AnalysisResult, result *ai.AnalysisResult) []*ai.AnalysisResult {
+	if len(history) >= 10 {
+		history = history[1:]
 	}
-	
-	// 添加新记录（只保存前50个字符作为标题）
-	title := result
-	if len(title) > 50 {
-		title = title[:50] + "..."
+	return append(history, result)
+}
+
+// SwitchTab 保存当前标签页的分析状态并显示另一个标签页的状态
+func (ap *AIPanel) SwitchTab(id int) {
+	if id == ap.activeTab {
+		return
 	}
 	
-	ap.analysisHistory = append(ap.analysisHistory, result)
+	current := ap.tabState(ap.activeTab)
+	current.history = ap.analysisHistory
+	current.selectedText = ap.selectedText
+	current.result = ap.result
+	
+	next := ap.tabState(id)
+	ap.activeTab = id
+	ap.analysisHistory = next.history
+	ap.selectedText = next.selectedText
+	ap.selectionStart, ap.selectionEnd = -1, -1
+	ap.alignedText = ""
+	ap.sourceDoc = nil
+	ap.result = next.result
+	
+	if ap.result == nil {
+		ap.analysisText.ParseMarkdown(i18n.T("ai.placeholder"))
+		ap.details.RemoveAll()
+	} else {
+		ap.renderResult(ap.result)
+	}
+	ap.historyList.UnselectAll()
 	ap.historyList.Refresh()
+	if ap.selectedText == "" {
+		ap.analyzeBtn.Disable()
+	}
+	ap.statusLabel.SetText(i18n.T("status.ready"))
+	ap.updateUIState()
+}
+
+// RetainTabs 丢弃已关闭标签页的分析状态，取消为已关闭文档进行的分析
+func (ap *AIPanel) RetainTabs(ids []int) {
+	open := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		open[id] = true
+	}
+	if ap.isAnalyzing && !open[ap.pendingTab] {
+		ap.CancelAnalysis()
+	}
+	for id := range ap.tabs {
+		if !open[id] {
+			delete(ap.tabs, id)
+		}
+	}
+}
+
+// tabState 获取标签页的分析状态，没有时创建
+func (ap *AIPanel) tabState(id int) *aiTabState {
+	state, ok := ap.tabs[id]
+	if !ok {
+		state = &aiTabState{}
+		ap.tabs[id] = state
+	}
+	return state
+}
+
+// SplitOffset 获取分析结果和历史记录的分割比例
+func (ap *AIPanel) SplitOffset() float64 {
+	return ap.split.Offset
+}
+
+// SetSplitOffset 设置分析结果和历史记录的分割比例
+func (ap *AIPanel) SetSplitOffset(offset float64) {
+	ap.split.SetOffset(offset)
 }
 
 // updateUIState 更新UI状态
 func (ap *AIPanel) updateUIState() {
 	if ap.isAnalyzing {
-		ap.analyzeBtn.SetText("🔄 分析中...")
+		ap.analyzeBtn.SetText(i18n.T("ai.analyzing_button"))
 		ap.analyzeBtn.Disable()
-		ap.statusLabel.SetText("正在分析...")
+		ap.cancelBtn.Enable()
+		ap.statusLabel.SetText(i18n.T("ai.analyzing"))
 	} else {
-		ap.analyzeBtn.SetText("📝 分析选中文本")
+		ap.analyzeBtn.SetText(i18n.T("ai.analyze"))
+		ap.cancelBtn.Disable()
 		if ap.selectedText != "" {
 			ap.analyzeBtn.Enable()
 		}
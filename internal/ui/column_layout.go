@@ -0,0 +1,28 @@
+package ui
+
+import "fyne.io/fyne/v2"
+
+// columnLayout 把内容限制在最大宽度内水平居中，并可在上下留出空白
+type columnLayout struct {
+	maxWidth   float32 // 0表示不限制
+	overscroll float32 // 上下留白，使首末行也能滚动到视口中央
+}
+
+func (l *columnLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	width := size.Width
+	if l.maxWidth > 0 && l.maxWidth < width {
+		width = l.maxWidth
+	}
+	for _, o := range objects {
+		o.Move(fyne.NewPos((size.Width-width)/2, l.overscroll))
+		o.Resize(fyne.NewSize(width, size.Height-l.overscroll*2))
+	}
+}
+
+func (l *columnLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	min := fyne.NewSize(0, 0)
+	for _, o := range objects {
+		min = min.Max(o.MinSize())
+	}
+	return min.AddWidthHeight(0, l.overscroll*2)
+}
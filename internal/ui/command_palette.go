@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"ai-reader/internal/i18n"
+	"ai-reader/pkg/fuzzy"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	fynetheme "fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"sort"
+)
+
+// 命令面板尺寸和结果数量上限
+const (
+	paletteWidth      = 600
+	paletteHeight     = 400
+	paletteMaxResults = 200
+)
+
+// kindPenalty 只在“类别 标题”中匹配到时扣的分，让直接匹配标题的条目靠前
+const kindPenalty = 32
+
+// paletteItem 命令面板中的一项
+type paletteItem struct {
+	kind   string // 类别，如命令分类、“最近文档”、“目录”
+	title  string
+	detail string // 右侧的补充信息，如快捷键、页码
+	run    func()
+}
+
+// paletteResult 匹配结果，positions 为标题中匹配字符的rune偏移
+type paletteResult struct {
+	item      paletteItem
+	score     int
+	positions []int
+}
+
+// paletteEntry 命令面板的输入框，上下键移动选中项，Esc关闭
+type paletteEntry struct {
+	widget.Entry
+	onMove   func(delta int)
+	onCancel func()
+}
+
+func newPaletteEntry() *paletteEntry {
+	entry := &paletteEntry{}
+	entry.ExtendBaseWidget(entry)
+	return entry
+}
+
+// TypedKey 拦截导航按键，其余交给输入框处理
+func (e *paletteEntry) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyUp:
+		e.onMove(-1)
+	case fyne.KeyDown:
+		e.onMove(1)
+	case fyne.KeyPageUp:
+		e.onMove(-10)
+	case fyne.KeyPageDown:
+		e.onMove(10)
+	case fyne.KeyEscape:
+		e.onCancel()
+	default:
+		e.Entry.TypedKey(key)
+	}
+}
+
+// commandPalette 命令面板：对命令、最近文档、目录、书签等条目做模糊匹配
+type commandPalette struct {
+	window fyne.Window
+	source func() []paletteItem
+
+	items    []paletteItem
+	results  []paletteResult
+	selected int
+	moving   bool // 键盘移动选中项时为true，避免触发列表的点击执行
+
+	entry *paletteEntry
+	list  *widget.List
+	popup *widget.PopUp
+}
+
+// newCommandPalette 创建命令面板，source 在每次打开时提供候选条目
+func newCommandPalette(window fyne.Window, source func() []paletteItem) *commandPalette {
+	cp := &commandPalette{
+		window: window,
+		source: source,
+	}
+
+	cp.entry = newPaletteEntry()
+	cp.entry.SetPlaceHolder(i18n.T("palette.placeholder"))
+	cp.entry.OnChanged = func(string) { cp.filter() }
+	cp.entry.OnSubmitted = func(string) { cp.execute(cp.selected) }
+	cp.entry.onMove = cp.move
+	cp.entry.onCancel = cp.Hide
+
+	cp.list = widget.NewList(
+		func() int {
+			return len(cp.results)
+		},
+		func() fyne.CanvasObject {
+			kind := widget.NewLabel("")
+			kind.Importance = widget.LowImportance
+			detail := widget.NewLabel("")
+			detail.Importance = widget.LowImportance
+			title := widget.NewRichText()
+			title.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, kind, detail, title)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			row := obj.(*fyne.Container)
+			title := row.Objects[0].(*widget.RichText)
+			kind := row.Objects[1].(*widget.Label)
+			detail := row.Objects[2].(*widget.Label)
+
+			result := cp.results[id]
+			kind.SetText(result.item.kind)
+			detail.SetText(result.item.detail)
+			title.Segments = highlightSegments(result.item.title, result.positions)
+			title.Refresh()
+		},
+	)
+	cp.list.OnSelected = func(id widget.ListItemID) {
+		cp.selected = id
+		if !cp.moving {
+			cp.execute(id)
+		}
+	}
+
+	content := container.NewBorder(cp.entry, nil, nil, nil, cp.list)
+	cp.popup = widget.NewPopUp(content, window.Canvas())
+	return cp
+}
+
+// Show 在窗口顶部居中显示命令面板
+func (cp *commandPalette) Show() {
+	cp.items = cp.source()
+	cp.entry.SetText("")
+	cp.filter()
+
+	canvasSize := cp.window.Canvas().Size()
+	size := fyne.NewSize(fyne.Min(paletteWidth, canvasSize.Width-40), fyne.Min(paletteHeight, canvasSize.Height-80))
+	cp.popup.Resize(size)
+	cp.popup.ShowAtPosition(fyne.NewPos((canvasSize.Width-size.Width)/2, 40))
+	cp.window.Canvas().Focus(cp.entry)
+}
+
+// Hide 关闭命令面板
+func (cp *commandPalette) Hide() {
+	cp.popup.Hide()
+}
+
+// filter 按输入内容重新匹配并排序
+func (cp *commandPalette) filter() {
+	query := cp.entry.Text
+	cp.results = cp.results[:0]
+
+	for _, item := range cp.items {
+		if match, ok := fuzzy.MatchString(query, item.title); ok {
+			cp.results = append(cp.results, paletteResult{item: item, score: match.Score, positions: match.Positions})
+			continue
+		}
+		// 标题不匹配时允许带上类别，如 “目录 第三章”
+		prefix := len([]rune(item.kind)) + 1
+		if match, ok := fuzzy.MatchString(query, item.kind+" "+item.title); ok {
+			var positions []int
+			for _, pos := range match.Positions {
+				if pos >= prefix {
+					positions = append(positions, pos-prefix)
+				}
+			}
+			cp.results = append(cp.results, paletteResult{item: item, score: match.Score - kindPenalty, positions: positions})
+		}
+	}
+
+	if query != "" {
+		sort.SliceStable(cp.results, func(i, j int) bool {
+			return cp.results[i].score > cp.results[j].score
+		})
+	}
+	if len(cp.results) > paletteMaxResults {
+		cp.results = cp.results[:paletteMaxResults]
+	}
+
+	cp.list.Refresh()
+	cp.selected = -1
+	cp.move(1)
+}
+
+// move 移动选中项并滚动到可见位置
+func (cp *commandPalette) move(delta int) {
+	if len(cp.results) == 0 {
+		cp.selected = -1
+		cp.list.UnselectAll()
+		return
+	}
+
+	selected := cp.selected + delta
+	if selected < 0 {
+		selected = 0
+	}
+	if selected >= len(cp.results) {
+		selected = len(cp.results) - 1
+	}
+
+	// 已经选中的项再次 Select 不会触发 OnSelected，直接记录选中项
+	cp.selected = selected
+	cp.moving = true
+	cp.list.Select(selected)
+	cp.list.ScrollTo(selected)
+	cp.moving = false
+}
+
+// execute 关闭面板并执行选中项
+func (cp *commandPalette) execute(id int) {
+	if id < 0 || id >= len(cp.results) {
+		return
+	}
+	run := cp.results[id].item.run
+	cp.Hide()
+	if run != nil {
+		run()
+	}
+}
+
+// highlightSegments 把标题拆成文本片段，匹配的字符加粗显示
+func highlightSegments(title string, positions []int) []widget.RichTextSegment {
+	matched := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		matched[pos] = true
+	}
+
+	var segments []widget.RichTextSegment
+	var run []rune
+	bold := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		style := widget.RichTextStyleInline
+		style.TextStyle.Bold = bold
+		if bold {
+			style.ColorName = fynetheme.ColorNamePrimary
+		}
+		segments = append(segments, &widget.TextSegment{Text: string(run), Style: style})
+		run = nil
+	}
+
+	for i, r := range []rune(title) {
+		if matched[i] != bold {
+			flush()
+			bold = matched[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+	return segments
+}
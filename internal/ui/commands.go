@@ -0,0 +1,434 @@
+package ui
+
+import (
+	"ai-reader/internal/command"
+	"ai-reader/internal/events"
+	"ai-reader/internal/i18n"
+	"ai-reader/internal/reader"
+	"ai-reader/pkg/document"
+	"errors"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 快捷键配置键
+const (
+	configKeymapPreset = "keymap_preset" // 键位预设名称
+	configKeymap       = "keymap"        // 在预设基础上覆盖的绑定：命令ID -> 按键组合列表
+)
+
+// 命令分类的消息ID
+const (
+	categoryNavigation = "category.navigation"
+	categoryDocument   = "category.document"
+	categoryView       = "category.view"
+	categoryTheme      = "category.theme"
+	categoryAI         = "category.ai"
+	categoryHelp       = "category.help"
+)
+
+// 命令面板中非命令条目类别的消息ID
+const (
+	kindTab      = "palette.tab"
+	kindRecent   = "palette.recent"
+	kindOutline  = "palette.outline"
+	kindBookmark = "palette.bookmark"
+)
+
+// themeOrder 切换主题命令的循环顺序，主题名称的消息ID为 "theme.<name>"
+var themeOrder = []string{"classic", "dark", "green", "minimal"}
+
+// presetName 键位预设的显示名称
+func presetName(name string) string {
+	if name == command.PresetVim {
+		return "Vim"
+	}
+	return i18n.T("preset." + name)
+}
+
+// registerCommands 注册所有命令
+func (mw *MainWindow) registerCommands() {
+	input := func(inputType string) func() {
+		return func() { mw.controller.HandleUserInput(inputType, nil) }
+	}
+
+	commands := []command.Command{
+		{ID: "page.next", Title: i18n.T("command.page_next"), Category: i18n.T(categoryNavigation), Run: input(reader.InputNextPage)},
+		{ID: "page.previous", Title: i18n.T("command.page_previous"), Category: i18n.T(categoryNavigation), Run: input(reader.InputPreviousPage)},
+		{ID: "page.first", Title: i18n.T("command.page_first"), Category: i18n.T(categoryNavigation), Run: func() {
+			mw.controller.HandleUserInput(reader.InputGoToPage, 1)
+		}},
+		{ID: "page.last", Title: i18n.T("command.page_last"), Category: i18n.T(categoryNavigation), Run: func() {
+			mw.controller.HandleUserInput(reader.InputGoToPage, mw.controller.GetTotalPages())
+		}},
+		{ID: "page.goto", Title: i18n.T("command.page_goto"), Category: i18n.T(categoryNavigation), Run: mw.showGoToPage},
+		{ID: "search.find", Title: i18n.T("command.find"), Category: i18n.T(categoryNavigation), Run: mw.showSearch},
+
+		{ID: "document.open", Title: i18n.T("menu.open"), Category: i18n.T(categoryDocument), Run: mw.handleOpenDocument},
+		{ID: "document.close", Title: i18n.T("menu.close"), Category: i18n.T(categoryDocument), Run: mw.handleCloseDocument},
+		{ID: "document.properties", Title: i18n.T("command.properties"), Category: i18n.T(categoryDocument), Run: mw.showDocumentInfo},
+		{ID: "bookmark.toggle", Title: i18n.T("command.bookmark_toggle"), Category: i18n.T(categoryDocument), Run: input(reader.InputToggleBookmark)},
+		{ID: "tab.next", Title: i18n.T("command.tab_next"), Category: i18n.T(categoryDocument), Run: input(reader.InputNextTab)},
+		{ID: "tab.previous", Title: i18n.T("command.tab_previous"), Category: i18n.T(categoryDocument), Run: input(reader.InputPreviousTab)},
+
+		{ID: "view.focus_mode", Title: i18n.T("menu.focus_mode"), Category: i18n.T(categoryView), Run: mw.handleToggleFocusMode},
+		{ID: "view.parallel", Title: i18n.T("menu.parallel"), Category: i18n.T(categoryView), Run: mw.handleToggleParallel},
+		{ID: "view.fullscreen", Title: i18n.T("menu.fullscreen"), Category: i18n.T(categoryView), Run: mw.handleToggleFullscreen},
+		{ID: "view.file_tree", Title: i18n.T("command.toggle_file_tree"), Category: i18n.T(categoryView), Run: func() {
+			mw.togglePanel(mw.treePanel)
+		}},
+		{ID: "view.ai_panel", Title: i18n.T("command.toggle_ai_panel"), Category: i18n.T(categoryView), Run: func() {
+			mw.togglePanel(mw.analysisPanel)
+		}},
+		{ID: "view.zoom_in", Title: i18n.T("menu.zoom_in"), Category: i18n.T(categoryView), Run: input(reader.InputZoomIn)},
+		{ID: "view.zoom_out", Title: i18n.T("menu.zoom_out"), Category: i18n.T(categoryView), Run: input(reader.InputZoomOut)},
+		{ID: "view.zoom_reset", Title: i18n.T("menu.zoom_reset"), Category: i18n.T(categoryView), Run: input(reader.InputZoomReset)},
+		{ID: "view.command_palette", Title: i18n.T("menu.command_palette"), Category: i18n.T(categoryView), Run: mw.showCommandPalette},
+
+		{ID: "theme.next", Title: i18n.T("command.theme_next"), Category: i18n.T(categoryTheme), Run: mw.nextTheme},
+	}
+	for _, name := range themeOrder {
+		name := name
+		commands = append(commands, command.Command{
+			ID:       "theme." + name,
+			Title:    i18n.T("theme." + name),
+			Category: i18n.T(categoryTheme),
+			Run:      func() { mw.handleThemeChange(name) },
+		})
+	}
+	commands = append(commands,
+		command.Command{ID: "selection.analyze", Title: i18n.T("command.analyze"), Category: i18n.T(categoryAI), Run: mw.aiPanel.handleAnalyze},
+		command.Command{ID: "selection.cancel_analysis", Title: i18n.T("command.cancel_analysis"), Category: i18n.T(categoryAI), Run: mw.aiPanel.CancelAnalysis},
+		command.Command{ID: "ai.clear_cache", Title: i18n.T("menu.ai_clear_cache"), Category: i18n.T(categoryAI), Run: mw.handleClearAICache},
+		command.Command{ID: "help.shortcuts", Title: i18n.T("command.shortcuts"), Category: i18n.T(categoryHelp), Run: mw.handleShowHelp},
+		command.Command{ID: "app.quit", Title: i18n.T("menu.quit"), Category: i18n.T(categoryHelp), Run: mw.handleExit},
+	)
+
+	for _, cmd := range commands {
+		mw.commands.Register(cmd)
+	}
+}
+
+// applyKeymap 按配置加载键位并注册快捷键
+func (mw *MainWindow) applyKeymap() {
+	keymap, err := command.Preset(mw.settings.GetString(configKeymapPreset))
+	if err != nil {
+		mw.publishError(err)
+		keymap = command.DefaultKeymap()
+	}
+
+	if overrides, ok := mw.settings.Get(configKeymap).(map[string]interface{}); ok {
+		custom, err := command.ParseKeymap(overrides)
+		if err != nil {
+			mw.publishError(err)
+		} else {
+			keymap = keymap.Merge(custom)
+		}
+	}
+	mw.commands.SetKeymap(keymap)
+
+	// 带修饰键的组合注册为画布快捷键，其余按键在 handleTypedKey 中分发
+	c := mw.window.Canvas()
+	for _, shortcut := range mw.shortcuts {
+		c.RemoveShortcut(shortcut)
+	}
+	mw.shortcuts = mw.shortcuts[:0]
+	for _, chord := range mw.commands.Chords() {
+		if !chord.IsShortcut() {
+			continue
+		}
+		chord := chord
+		shortcut := &desktop.CustomShortcut{KeyName: chord.Key, Modifier: chord.Modifier}
+		c.AddShortcut(shortcut, func(fyne.Shortcut) {
+			mw.executeChord(chord)
+		})
+		mw.shortcuts = append(mw.shortcuts, shortcut)
+	}
+}
+
+// handleTypedKey 分发不带Ctrl等修饰键的按键，返回是否执行了命令
+func (mw *MainWindow) handleTypedKey(evt *fyne.KeyEvent) bool {
+	chord := command.Chord{Key: evt.Name}
+	if drv, ok := fyne.CurrentApp().Driver().(desktop.Driver); ok {
+		chord.Modifier = drv.CurrentKeyModifiers() & fyne.KeyModifierShift
+	}
+	return mw.executeChord(chord)
+}
+
+// executeChord 执行按键组合绑定的命令
+func (mw *MainWindow) executeChord(chord command.Chord) bool {
+	id, ok := mw.commands.Lookup(chord)
+	if !ok {
+		return false
+	}
+	if err := mw.commands.Execute(id); err != nil {
+		mw.publishError(err)
+	}
+	return true
+}
+
+// setKeymapPreset 切换键位预设，配置中的自定义绑定仍覆盖在预设之上
+func (mw *MainWindow) setKeymapPreset(name string) {
+	mw.settings.Set(configKeymapPreset, name)
+	mw.applyKeymap()
+}
+
+// publishError 通过事件总线报告错误
+func (mw *MainWindow) publishError(err error) {
+	mw.eventBus.Publish(events.Event{
+		Type:    events.ErrorOccurred,
+		Payload: err,
+	})
+}
+
+// togglePanel 显示或隐藏侧边面板
+func (mw *MainWindow) togglePanel(panel fyne.CanvasObject) {
+	if panel.Visible() {
+		panel.Hide()
+	} else {
+		panel.Show()
+	}
+	mw.mainContent.Refresh()
+}
+
+// nextTheme 按顺序切换到下一个主题
+func (mw *MainWindow) nextTheme() {
+	next := themeOrder[0]
+	for i, name := range themeOrder {
+		if name == mw.themeName {
+			next = themeOrder[(i+1)%len(themeOrder)]
+			break
+		}
+	}
+	mw.handleThemeChange(next)
+}
+
+// showSearch 显示查找对话框
+func (mw *MainWindow) showSearch() {
+	if mw.search == nil {
+		mw.search = newSearchDialog(mw.window, mw.controller, mw.readerArea)
+	}
+	mw.search.Show()
+}
+
+// showGoToPage 显示跳转页码对话框
+func (mw *MainWindow) showGoToPage() {
+	total := mw.controller.GetTotalPages()
+
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("1 - " + i18n.FormatNumber(total))
+	entry.Validator = func(text string) error {
+		page, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil || page < 1 || page > total {
+			return errors.New(i18n.T("goto.invalid", i18n.Args{"Total": total}))
+		}
+		return nil
+	}
+
+	d := dialog.NewForm(i18n.T("goto.title"), i18n.T("goto.confirm"), i18n.T("dialog.cancel"),
+		[]*widget.FormItem{widget.NewFormItem(i18n.T("goto.page"), entry)},
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			page, _ := strconv.Atoi(strings.TrimSpace(entry.Text))
+			mw.controller.HandleUserInput(reader.InputGoToPage, page)
+		}, mw.window)
+	d.Show()
+	mw.window.Canvas().Focus(entry)
+}
+
+// showShortcuts 显示快捷键速查表，可切换键位预设
+func (mw *MainWindow) showShortcuts() {
+	list := container.NewVBox()
+	refresh := func() {
+		list.Objects = nil
+		category := ""
+		var rows *fyne.Container
+		for _, cmd := range mw.commands.All() {
+			if cmd.Category != category {
+				category = cmd.Category
+				list.Add(widget.NewLabelWithStyle(category, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+				rows = container.New(layout.NewFormLayout())
+				list.Add(rows)
+			}
+
+			var chords []string
+			for _, chord := range mw.commands.Bindings(cmd.ID) {
+				chords = append(chords, chord.String())
+			}
+			rows.Add(widget.NewLabel(cmd.Title))
+			rows.Add(widget.NewLabel(strings.Join(chords, "  ")))
+		}
+		list.Refresh()
+	}
+	refresh()
+
+	presets := command.Presets()
+	options := make([]string, len(presets))
+	for i, name := range presets {
+		options[i] = presetName(name)
+	}
+	presetSelect := widget.NewSelect(options, func(selected string) {
+		for _, name := range presets {
+			if presetName(name) == selected {
+				mw.setKeymapPreset(name)
+			}
+		}
+		refresh()
+	})
+	current := mw.settings.GetString(configKeymapPreset)
+	if current == "" {
+		current = command.PresetDefault
+	}
+	presetSelect.SetSelected(presetName(current))
+
+	header := container.NewHBox(widget.NewLabel(i18n.T("shortcuts.preset")), presetSelect)
+	content := container.NewBorder(header, nil, nil, nil, container.NewVScroll(list))
+
+	d := dialog.NewCustom(i18n.T("shortcuts.title"), i18n.T("dialog.close"), content, mw.window)
+	d.Resize(fyne.NewSize(520, 560))
+	d.Show()
+}
+
+// showCommandPalette 显示命令面板
+func (mw *MainWindow) showCommandPalette() {
+	if mw.palette == nil {
+		mw.palette = newCommandPalette(mw.window, mw.paletteItems)
+	}
+	mw.palette.Show()
+}
+
+// paletteItems 收集命令面板的候选条目：标签页、最近文档、书签、目录和所有命令
+func (mw *MainWindow) paletteItems() []paletteItem {
+	var items []paletteItem
+
+	currentFile := mw.controller.GetCurrentFile()
+	open := make(map[string]bool)
+	for _, t := range mw.controller.GetTabs() {
+		open[t.Filename] = true
+		if t.Filename == currentFile {
+			continue
+		}
+		id := t.ID
+		items = append(items, paletteItem{
+			kind:   i18n.T(kindTab),
+			title:  tabTitle(t),
+			detail: i18n.T("page.number", i18n.Args{"Page": t.Page}),
+			run:    func() { mw.controller.HandleUserInput(reader.InputSwitchTab, id) },
+		})
+	}
+
+	for _, filename := range mw.controller.GetRecentDocuments() {
+		if open[filename] {
+			continue
+		}
+		filename := filename
+		items = append(items, paletteItem{
+			kind:   i18n.T(kindRecent),
+			title:  filepath.Base(filename),
+			detail: filepath.Dir(filename),
+			run:    func() { mw.openDocument(filename) },
+		})
+	}
+
+	if currentFile != "" {
+		for _, b := range mw.controller.GetBookmarks(currentFile) {
+			page := b.Page
+			items = append(items, paletteItem{
+				kind:   i18n.T(kindBookmark),
+				title:  b.Title,
+				detail: i18n.T("page.number", i18n.Args{"Page": page}),
+				run:    func() { mw.controller.HandleUserInput(reader.InputGoToPage, page) },
+			})
+		}
+	}
+
+	for _, entry := range mw.outline() {
+		entry := entry
+		items = append(items, paletteItem{
+			kind:   i18n.T(kindOutline),
+			title:  strings.Repeat("  ", entry.Level-1) + entry.Title,
+			detail: i18n.T("page.number", i18n.Args{"Page": entry.Page}),
+			run:    func() { mw.goToOutlineEntry(entry) },
+		})
+	}
+
+	for _, cmd := range mw.commands.All() {
+		if cmd.ID == "view.command_palette" {
+			continue
+		}
+		id := cmd.ID
+		var chords []string
+		for _, chord := range mw.commands.Bindings(id) {
+			chords = append(chords, chord.String())
+		}
+		items = append(items, paletteItem{
+			kind:   cmd.Category,
+			title:  cmd.Title,
+			detail: strings.Join(chords, "  "),
+			run: func() {
+				if err := mw.commands.Execute(id); err != nil {
+					mw.publishError(err)
+				}
+			},
+		})
+	}
+
+	return items
+}
+
+// outline 获取当前文档的目录，同一文档只解析一次
+func (mw *MainWindow) outline() []document.OutlineEntry {
+	doc := mw.controller.GetCurrentDocument()
+	if doc == nil {
+		return nil
+	}
+	if doc != mw.outlineDoc {
+		mw.outlineDoc = doc
+		mw.outlineEntries = document.ExtractOutline(doc)
+	}
+	return mw.outlineEntries
+}
+
+// goToOutlineEntry 跳转到目录条目并选中标题
+func (mw *MainWindow) goToOutlineEntry(entry document.OutlineEntry) {
+	if entry.Page != mw.controller.GetCurrentPage() {
+		mw.controller.HandleUserInput(reader.InputGoToPage, entry.Page)
+	}
+	mw.readerArea.SelectRange(entry.Offset, entry.Offset+len([]rune(entry.Title)))
+}
+
+// openDocument 请求打开文档
+func (mw *MainWindow) openDocument(filename string) {
+	mw.eventBus.Publish(events.Event{
+		Type:    events.DocumentOpenRequest,
+		Payload: filename,
+	})
+}
+
+// createRecentMenu 创建最近文档子菜单
+func (mw *MainWindow) createRecentMenu() *fyne.Menu {
+	recent := mw.controller.GetRecentDocuments()
+	if len(recent) == 0 {
+		empty := fyne.NewMenuItem(i18n.T("menu.recent_empty"), nil)
+		empty.Disabled = true
+		return fyne.NewMenu(i18n.T("menu.recent"), empty)
+	}
+
+	items := make([]*fyne.MenuItem, 0, len(recent))
+	for _, filename := range recent {
+		filename := filename
+		items = append(items, fyne.NewMenuItem(filepath.Base(filename), func() {
+			mw.openDocument(filename)
+		}))
+	}
+	return fyne.NewMenu(i18n.T("menu.recent"), items...)
+}
@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"ai-reader/internal/i18n"
+	"ai-reader/pkg/document"
+	"ai-reader/pkg/textstats"
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"math"
+	"strings"
+	"time"
+)
+
+// 可读性评分分级
+const (
+	readabilityEasy     = 70
+	readabilityModerate = 40
+)
+
+// emptyValue 没有数据的字段显示的内容
+const emptyValue = "—"
+
+// computeStats 在后台统计文档，完成后更新状态栏
+func (mw *MainWindow) computeStats(doc document.Document) {
+	stats, err := textStats(doc)
+	if err != nil {
+		return
+	}
+
+	fyne.Do(func() {
+		if mw.controller.GetCurrentDocument() != doc {
+			return
+		}
+		mw.statsDoc = doc
+		mw.stats = stats
+		mw.statusBar.UpdateDocInfo(mw.docSummary(doc.GetTitle(), stats))
+	})
+}
+
+// documentStats 获取文档的统计结果，还没有统计时立即统计
+func (mw *MainWindow) documentStats(doc document.Document) textstats.Stats {
+	if mw.statsDoc != doc {
+		mw.statsDoc = doc
+		mw.stats, _ = textStats(doc)
+	}
+	return mw.stats
+}
+
+// textStats 统计文档全文，加载器已经统计过时直接使用
+func textStats(doc document.Document) (textstats.Stats, error) {
+	if provider, ok := doc.(document.StatsProvider); ok {
+		return provider.GetTextStats(), nil
+	}
+	content, err := doc.GetContent()
+	if err != nil {
+		return textstats.Stats{}, err
+	}
+	return textstats.Compute(content), nil
+}
+
+// showDocumentInfo 显示文档属性对话框
+func (mw *MainWindow) showDocumentInfo() {
+	doc := mw.controller.GetCurrentDocument()
+	if doc == nil {
+		dialog.ShowInformation(i18n.T("info.title"), i18n.T("search.no_document"), mw.window)
+		return
+	}
+	meta := doc.GetMetadata()
+	stats := mw.documentStats(doc)
+	readingTime := mw.controller.GetReadingSpeed().Estimate(stats)
+
+	general := infoForm(
+		i18n.T("info.doc_title"), meta.Title,
+		i18n.T("info.author"), meta.Author,
+		i18n.T("info.subject"), meta.Subject,
+		i18n.T("info.creator"), meta.Creator,
+		i18n.T("info.created"), formatMetaDate(meta.CreatedAt),
+		i18n.T("info.modified"), formatMetaDate(meta.ModifiedAt),
+		i18n.T("info.format"), meta.Format,
+		i18n.T("info.pages"), i18n.N("info.page_count", meta.PageCount),
+		i18n.T("info.word_count"), i18n.FormatNumber(meta.WordCount),
+		i18n.T("info.file_size"), formatFileSize(meta.FileSize),
+		i18n.T("info.path"), mw.controller.GetCurrentFile(),
+	)
+
+	statistics := infoForm(
+		i18n.T("info.characters"), i18n.FormatNumber(stats.Characters),
+		i18n.T("info.cjk_characters"), i18n.FormatNumber(stats.CJKCharacters),
+		i18n.T("info.latin_words"), i18n.FormatNumber(stats.LatinWords),
+		i18n.T("info.words"), i18n.FormatNumber(stats.Words),
+		i18n.T("info.unique_words"), i18n.FormatNumber(stats.UniqueWords),
+		i18n.T("info.sentences"), i18n.FormatNumber(stats.Sentences),
+		i18n.T("info.paragraphs"), i18n.FormatNumber(stats.Paragraphs),
+		i18n.T("info.reading_time"), formatReadingTime(readingTime),
+		i18n.T("info.reading_speed"), formatReadingSpeed(mw.controller.GetReadingSpeed()),
+		i18n.T("info.readability"), formatReadability(stats),
+	)
+
+	content := container.NewVBox(
+		widget.NewLabelWithStyle(i18n.T("info.general"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		general,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle(i18n.T("info.statistics"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		statistics,
+	)
+
+	d := dialog.NewCustom(i18n.T("info.title"), i18n.T("dialog.close"), container.NewVScroll(content), mw.window)
+	d.Resize(fyne.NewSize(480, 560))
+	d.Show()
+}
+
+// infoForm 两列的名称-值表格，值可以选中复制
+func infoForm(pairs ...string) *fyne.Container {
+	form := container.New(layout.NewFormLayout())
+	for i := 0; i+1 < len(pairs); i += 2 {
+		value := pairs[i+1]
+		if strings.TrimSpace(value) == "" {
+			value = emptyValue
+		}
+		label := widget.NewLabel(value)
+		label.Wrapping = fyne.TextWrapBreak
+		label.Selectable = true
+		form.Add(widget.NewLabelWithStyle(pairs[i], fyne.TextAlignTrailing, fyne.TextStyle{}))
+		form.Add(label)
+	}
+	return form
+}
+
+// docSummary 状态栏的文档摘要：标题、篇幅和按阅读速度估算的阅读时间
+func (mw *MainWindow) docSummary(title string, stats textstats.Stats) string {
+	length := i18n.N("info.words_summary", stats.LatinWords)
+	if stats.CJKCharacters >= stats.LatinWords {
+		length = i18n.N("info.characters_summary", stats.CJKCharacters)
+	}
+	readingTime := mw.controller.GetReadingSpeed().Estimate(stats)
+	return strings.Join([]string{title, length, formatReadingTime(readingTime)}, " · ")
+}
+
+// formatReadingSpeed 阅读速度，如“每分钟 320 字 / 240 词”
+func formatReadingSpeed(speed textstats.ReadingSpeed) string {
+	return i18n.T("info.reading_speed_value", i18n.Args{
+		"CJK":   int(math.Round(speed.CJKCharsPerMinute)),
+		"Latin": int(math.Round(speed.LatinWordsPerMinute)),
+	})
+}
+
+// formatReadingTime 格式化预计阅读时间，按分钟向上取整
+func formatReadingTime(d time.Duration) string {
+	minutes := int(math.Ceil(d.Minutes()))
+	switch {
+	case minutes < 1:
+		return i18n.T("info.reading_under_minute")
+	case minutes < 60:
+		return i18n.N("info.reading_minutes", minutes)
+	}
+	return i18n.T("info.reading_hours", i18n.Args{"Hours": minutes / 60, "Minutes": minutes % 60})
+}
+
+// formatReadability 可读性评分和等级
+func formatReadability(stats textstats.Stats) string {
+	if stats.Words == 0 {
+		return emptyValue
+	}
+	level := i18n.T("info.readability_hard")
+	switch {
+	case stats.Readability >= readabilityEasy:
+		level = i18n.T("info.readability_easy")
+	case stats.Readability >= readabilityModerate:
+		level = i18n.T("info.readability_moderate")
+	}
+	return i18n.T("info.readability_value", i18n.Args{"Score": int(math.Round(stats.Readability)), "Level": level})
+}
+
+// formatFileSize 格式化文件大小
+func formatFileSize(size int64) string {
+	if size <= 0 {
+		return emptyValue
+	}
+	units := []string{"KB", "MB", "GB"}
+	if size < 1024 {
+		return i18n.FormatNumber(size) + " B"
+	}
+	value := float64(size) / 1024
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%s %s", i18n.FormatNumber(value), units[unit])
+}
+
+// formatMetaDate 元数据中的日期按当前语言显示，无法解析时原样显示
+func formatMetaDate(value string) string {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "2006-01-02" {
+				return i18n.FormatDate(t)
+			}
+			return i18n.FormatDateTime(t)
+		}
+	}
+	return value
+}
@@ -1,51 +1,145 @@
 package ui
 
 import (
+	"ai-reader/internal/ai"
+	"ai-reader/internal/command"
 	"ai-reader/internal/events"
+	"ai-reader/internal/i18n"
+	"ai-reader/internal/reader"
+	"ai-reader/pkg/annotation"
+	"ai-reader/pkg/document"
+	"ai-reader/pkg/textstats"
+	"fmt"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/widget"
+	"sync"
 )
 
 // MainWindow 主窗口结构
 type MainWindow struct {
-	app       fyne.App
-	window    fyne.Window
-	eventBus  *events.Bus
+	app        fyne.App
+	window     fyne.Window
+	eventBus   *events.Bus
+	controller reader.ReaderController
+	settings   reader.Settings
 	
 	// UI组件
-	fileTree    *widget.Tree
-	readerArea  *ReaderArea
-	aiPanel     *AIPanel
-	statusBar   *StatusBar
-	menuBar     *fyne.MainMenu
+	fileTree       *widget.Tree
+	tabBar         *TabBar
+	readerArea     *ReaderArea
+	aiPanel        *AIPanel
+	statusBar      *StatusBar
+	menuBar        *fyne.MainMenu
+	transitionMenu *fyne.MenuItem
+	focusMenu      *fyne.MenuItem
+	recentMenu     *fyne.MenuItem
+	languageMenu   *fyne.MenuItem
 	
 	// 布局容器
 	leftPanel   *container.Split
 	rightPanel  *container.Split
 	mainContent *container.Split
+	content     fyne.CanvasObject
+	readerSlot  *fyne.Container
+	treePanel     *fyne.Container
+	analysisPanel *fyne.Container
+	
+	// 命令和快捷键
+	commands  *command.Registry
+	shortcuts []fyne.Shortcut
+	search    *searchDialog
+	palette   *commandPalette
+	
+	// 命令面板使用的目录缓存
+	outlineDoc     document.Document
+	outlineEntries []document.OutlineEntry
+	
+	// 当前文档的统计结果
+	statsDoc document.Document
+	stats    textstats.Stats
+	
+	// 专注模式
+	focusMode bool
+	themeName string
+	
+	// 并排阅读
+	parallel     *ParallelPane
+	parallelMode bool
+	alignMu      sync.Mutex // 保护 alignIndexes，索引在后台建立
+	alignIndexes map[document.Document]*alignIndex
+}
+
+// 专注模式配置键
+const (
+	configFocusMode             = "focus_mode"
+	configFocusCharsPerLine     = "focus_chars_per_line"
+	configFocusDimParagraphs    = "focus_dim_paragraphs"
+	configFocusTypewriterScroll = "focus_typewriter_scroll"
+)
+
+// defaultCharsPerLine 专注模式默认每行字符数
+const defaultCharsPerLine = 66
+
+// 窗口和布局配置键
+const (
+	configWindowWidth     = "window_width"
+	configWindowHeight    = "window_height"
+	configWindowMaximized = "window_maximized"
+	configPanelSplits     = "panel_splits" // 面板分割比例：面板名 -> 比例
+)
+
+// 默认窗口大小
+const (
+	defaultWindowWidth  = 1200
+	defaultWindowHeight = 800
+)
+
+// configLanguage 界面语言配置键，空字符串表示跟随系统
+const configLanguage = "language"
+
+// languageNames 界面语言的名称，以该语言本身显示
+var languageNames = map[string]string{
+	i18n.LocaleChinese: "中文",
+	i18n.LocaleEnglish: "English",
 }
 
 // NewMainWindow 创建主窗口
-func NewMainWindow(eventBus *events.Bus) *MainWindow {
+func NewMainWindow(eventBus *events.Bus, controller reader.ReaderController, settings reader.Settings) *MainWindow {
 	fyneApp := app.New()
 	fyneApp.SetIcon(nil) // TODO: 添加应用图标
 	
 	window := fyneApp.NewWindow("AI Reader")
 	window.SetMaster()
-	window.Resize(fyne.NewSize(1200, 800))
+	window.Resize(windowSize(settings))
 	window.CenterOnScreen()
 	
+	// Fyne 没有最大化窗口的接口，以全屏状态代替
+	if settings.GetBool(configWindowMaximized) {
+		window.SetFullScreen(true)
+	}
+	
 	mw := &MainWindow{
-		app:      fyneApp,
-		window:   window,
-		eventBus: eventBus,
+		app:        fyneApp,
+		window:     window,
+		eventBus:   eventBus,
+		controller: controller,
+		settings:   settings,
+		commands:   command.NewRegistry(),
+		themeName:  settings.GetString("default_theme"),
 	}
 	
 	mw.initializeComponents()
 	mw.setupLayout()
 	mw.setupEventHandlers()
+	mw.setupShortcuts()
+	
+	// 恢复上次退出时的专注模式
+	if settings.GetBool(configFocusMode) {
+		mw.setFocusMode(true)
+	}
 	
 	return mw
 }
@@ -55,11 +149,25 @@ func (mw *MainWindow) initializeComponents() {
 	// 文件树
 	mw.fileTree = mw.createFileTree()
 	
+	// 文档标签栏
+	mw.tabBar = NewTabBar()
+	mw.tabBar.OnSelected = func(id int) {
+		mw.controller.HandleUserInput(reader.InputSwitchTab, id)
+	}
+	mw.tabBar.OnClosed = func(id int) {
+		mw.controller.HandleUserInput(reader.InputCloseTab, id)
+	}
+	
 	// 阅读器区域
-	mw.readerArea = NewReaderArea(mw.eventBus)
+	mw.readerArea = NewReaderArea(mw.eventBus, mw.controller)
+	mw.readerArea.OnViewportChanged = mw.syncParallel
 	
 	// AI分析面板
 	mw.aiPanel = NewAIPanel(mw.eventBus)
+	mw.aiPanel.AlignSelection = mw.alignReaderSelection
+	mw.aiPanel.SelectionSource = func() (document.Document, int) {
+		return mw.controller.GetCurrentDocument(), mw.controller.GetCurrentPage()
+	}
 	
 	// 状态栏
 	mw.statusBar = NewStatusBar()
@@ -71,39 +179,43 @@ func (mw *MainWindow) initializeComponents() {
 // setupLayout 设置布局
 func (mw *MainWindow) setupLayout() {
 	// 左侧面板 - 文件树
-	leftContainer := container.NewBorder(
-		widget.NewLabel("文档浏览"), nil, nil, nil,
+	mw.treePanel = container.NewBorder(
+		widget.NewLabel(i18n.T("panel.documents")), nil, nil, nil,
 		container.NewScroll(mw.fileTree),
 	)
 	
 	// 右侧面板 - AI分析
-	rightContainer := container.NewBorder(
-		widget.NewLabel("AI分析"), nil, nil, nil,
+	mw.analysisPanel = container.NewBorder(
+		widget.NewLabel(i18n.T("panel.ai")), nil, nil, nil,
 		mw.aiPanel.GetContainer(),
 	)
 	
+	// 阅读区域放在单独的容器中，专注模式下移出，并排阅读时与对照文档并列
+	mw.readerSlot = container.NewStack(mw.readerArea.GetContainer())
+	
 	// 主要内容区域
 	mw.mainContent = container.NewHSplit(
-		leftContainer,
+		mw.treePanel,
 		container.NewHSplit(
-			mw.readerArea.GetContainer(),
-			rightContainer,
+			container.NewBorder(mw.tabBar.GetContainer(), nil, nil, nil, mw.readerSlot),
+			mw.analysisPanel,
 		),
 	)
 	
-	// 设置分割比例
-	mw.mainContent.SetOffset(0.2) // 左侧占20%
-	mw.mainContent.Trailing.(*container.Split).SetOffset(0.75) // 中间占75%，右侧占25%
+	// 设置分割比例，默认左侧占20%，中间占75%，右侧占25%
+	mw.mainContent.SetOffset(mw.panelSplit("file_tree", 0.2))
+	mw.mainContent.Trailing.(*container.Split).SetOffset(mw.panelSplit("ai_panel", 0.75))
+	mw.aiPanel.SetSplitOffset(mw.panelSplit("ai_history", 0.7))
 	
 	// 主布局
-	content := container.NewBorder(
+	mw.content = container.NewBorder(
 		nil, // 顶部
 		mw.statusBar.GetContainer(), // 底部
 		nil, nil, // 左右
 		mw.mainContent, // 中心
 	)
 	
-	mw.window.SetContent(content)
+	mw.window.SetContent(mw.content)
 	mw.window.SetMainMenu(mw.menuBar)
 }
 
@@ -139,7 +251,7 @@ func (mw *MainWindow) createFileTree() *widget.Tree {
 	tree.OnSelected = func(uid string) {
 		// 处理文件选择
 		mw.eventBus.Publish(events.Event{
-			Type:    events.DocumentOpened,
+			Type:    events.DocumentOpenRequest,
 			Payload: uid,
 		})
 	}
@@ -150,55 +262,219 @@ func (mw *MainWindow) createFileTree() *widget.Tree {
 // createMenuBar 创建菜单栏
 func (mw *MainWindow) createMenuBar() *fyne.MainMenu {
 	// 文件菜单
-	fileMenu := fyne.NewMenu("文件",
-		fyne.NewMenuItem("打开文档...", mw.handleOpenDocument),
-		fyne.NewMenuItem("最近文档", nil),
+	mw.recentMenu = fyne.NewMenuItem(i18n.T("menu.recent"), nil)
+	mw.recentMenu.ChildMenu = mw.createRecentMenu()
+	
+	fileMenu := fyne.NewMenu(i18n.T("menu.file"),
+		fyne.NewMenuItem(i18n.T("menu.open"), mw.handleOpenDocument),
+		mw.recentMenu,
+		fyne.NewMenuItem(i18n.T("menu.close"), mw.handleCloseDocument),
+		fyne.NewMenuItem(i18n.T("command.properties"), mw.showDocumentInfo),
+		fyne.NewMenuItem(i18n.T("menu.import_highlights"), mw.handleImportHighlights),
 		fyne.NewMenuItemSeparator(),
-		fyne.NewMenuItem("退出", mw.handleExit),
+		fyne.NewMenuItem(i18n.T("menu.quit"), mw.handleExit),
 	)
 	
 	// 视图菜单
-	viewMenu := fyne.NewMenu("视图",
-		fyne.NewMenuItem("全屏模式", mw.handleToggleFullscreen),
-		fyne.NewMenuItem("专注模式", mw.handleToggleFocusMode),
+	mw.transitionMenu = fyne.NewMenuItem(i18n.T("menu.page_turn"), nil)
+	mw.transitionMenu.ChildMenu = mw.createTransitionMenu()
+	
+	mw.focusMenu = fyne.NewMenuItem(i18n.T("menu.focus_options"), nil)
+	mw.focusMenu.ChildMenu = mw.createFocusMenu()
+	
+	mw.languageMenu = fyne.NewMenuItem(i18n.T("menu.language"), nil)
+	mw.languageMenu.ChildMenu = mw.createLanguageMenu()
+	
+	viewMenu := fyne.NewMenu(i18n.T("menu.view"),
+		fyne.NewMenuItem(i18n.T("menu.command_palette"), mw.showCommandPalette),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(i18n.T("menu.fullscreen"), mw.handleToggleFullscreen),
+		fyne.NewMenuItem(i18n.T("menu.focus_mode"), mw.handleToggleFocusMode),
+		mw.focusMenu,
+		fyne.NewMenuItem(i18n.T("menu.parallel"), mw.handleToggleParallel),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem(i18n.T("menu.zoom_in"), func() { mw.controller.HandleUserInput(reader.InputZoomIn, nil) }),
+		fyne.NewMenuItem(i18n.T("menu.zoom_out"), func() { mw.controller.HandleUserInput(reader.InputZoomOut, nil) }),
+		fyne.NewMenuItem(i18n.T("menu.zoom_reset"), func() { mw.controller.HandleUserInput(reader.InputZoomReset, nil) }),
+		mw.transitionMenu,
 		fyne.NewMenuItemSeparator(),
-		fyne.NewMenuItem("缩放", nil),
+		mw.languageMenu,
 	)
 	
 	// 主题菜单
-	themeMenu := fyne.NewMenu("主题",
-		fyne.NewMenuItem("经典主题", func() { mw.handleThemeChange("classic") }),
-		fyne.NewMenuItem("夜间主题", func() { mw.handleThemeChange("dark") }),
-		fyne.NewMenuItem("护眼主题", func() { mw.handleThemeChange("green") }),
-		fyne.NewMenuItem("简约主题", func() { mw.handleThemeChange("minimal") }),
+	themeMenu := fyne.NewMenu(i18n.T("menu.theme"),
+		fyne.NewMenuItem(i18n.T("theme.classic"), func() { mw.handleThemeChange("classic") }),
+		fyne.NewMenuItem(i18n.T("theme.dark"), func() { mw.handleThemeChange("dark") }),
+		fyne.NewMenuItem(i18n.T("theme.green"), func() { mw.handleThemeChange("green") }),
+		fyne.NewMenuItem(i18n.T("theme.minimal"), func() { mw.handleThemeChange("minimal") }),
 	)
 	
 	// AI菜单
-	aiMenu := fyne.NewMenu("AI",
-		fyne.NewMenuItem("分析设置", mw.handleAISettings),
-		fyne.NewMenuItem("清除历史", mw.handleClearAIHistory),
+	aiMenu := fyne.NewMenu(i18n.T("menu.ai"),
+		fyne.NewMenuItem(i18n.T("menu.ai_settings"), mw.handleAISettings),
+		fyne.NewMenuItem(i18n.T("menu.ai_clear_history"), mw.handleClearAIHistory),
+		fyne.NewMenuItem(i18n.T("menu.ai_clear_cache"), mw.handleClearAICache),
 	)
 	
 	// 帮助菜单
-	helpMenu := fyne.NewMenu("帮助",
-		fyne.NewMenuItem("使用说明", mw.handleShowHelp),
-		fyne.NewMenuItem("关于", mw.handleShowAbout),
+	helpMenu := fyne.NewMenu(i18n.T("menu.help"),
+		fyne.NewMenuItem(i18n.T("menu.user_guide"), mw.handleShowHelp),
+		fyne.NewMenuItem(i18n.T("menu.about"), mw.handleShowAbout),
 	)
 	
 	return fyne.NewMainMenu(fileMenu, viewMenu, themeMenu, aiMenu, helpMenu)
 }
 
+// transitionName 翻页动画的显示名称，没有翻译的类型直接显示类型名
+func transitionName(transitionType string) string {
+	id := "transition." + transitionType
+	if name := i18n.T(id); name != id {
+		return name
+	}
+	return transitionType
+}
+
+// createTransitionMenu 创建翻页动画子菜单，勾选当前设置
+func (mw *MainWindow) createTransitionMenu() *fyne.Menu {
+	turner := mw.controller.GetPageTurner()
+	current := turner.GetTransition()
+	
+	types := append([]string{reader.TransitionFollowTheme}, turner.GetTransitionTypes()...)
+	items := make([]*fyne.MenuItem, 0, len(types)+2)
+	for _, transitionType := range types {
+		transitionType := transitionType
+		item := fyne.NewMenuItem(transitionName(transitionType), func() {
+			mw.handleTransitionChange(transitionType)
+		})
+		item.Checked = transitionType == current
+		items = append(items, item)
+	}
+	
+	reducedMotion := fyne.NewMenuItem(i18n.T("menu.reduced_motion"), mw.handleToggleReducedMotion)
+	reducedMotion.Checked = turner.IsReducedMotion()
+	items = append(items, fyne.NewMenuItemSeparator(), reducedMotion)
+	
+	return fyne.NewMenu(i18n.T("menu.page_turn"), items...)
+}
+
+// createFocusMenu 创建专注模式选项子菜单
+func (mw *MainWindow) createFocusMenu() *fyne.Menu {
+	options := mw.focusOptions()
+	
+	dim := fyne.NewMenuItem(i18n.T("menu.focus_dim"), func() {
+		mw.toggleFocusOption(configFocusDimParagraphs)
+	})
+	dim.Checked = options.DimParagraphs
+	
+	typewriter := fyne.NewMenuItem(i18n.T("menu.focus_typewriter"), func() {
+		mw.toggleFocusOption(configFocusTypewriterScroll)
+	})
+	typewriter.Checked = options.TypewriterScroll
+	
+	return fyne.NewMenu(i18n.T("menu.focus_options"), dim, typewriter)
+}
+
+// createLanguageMenu 创建界面语言子菜单，勾选当前设置
+func (mw *MainWindow) createLanguageMenu() *fyne.Menu {
+	current := mw.settings.GetString(configLanguage)
+	
+	auto := fyne.NewMenuItem(i18n.T("language.auto"), func() {
+		mw.handleLanguageChange(i18n.LocaleAuto)
+	})
+	auto.Checked = current == i18n.LocaleAuto
+	items := []*fyne.MenuItem{auto, fyne.NewMenuItemSeparator()}
+	
+	for _, locale := range i18n.Locales() {
+		locale := locale
+		item := fyne.NewMenuItem(languageNames[locale], func() {
+			mw.handleLanguageChange(locale)
+		})
+		item.Checked = locale == current
+		items = append(items, item)
+	}
+	
+	return fyne.NewMenu(i18n.T("menu.language"), items...)
+}
+
 // setupEventHandlers 设置事件处理器
 func (mw *MainWindow) setupEventHandlers() {
 	// 监听主题变化事件
 	mw.eventBus.Subscribe(events.ThemeChanged, func(event events.Event) {
 		// TODO: 应用新主题
+		if themeName, ok := event.Payload.(string); ok {
+			fyne.Do(func() {
+				mw.themeName = themeName
+			})
+		}
 	})
 	
 	// 监听页面变化事件
 	mw.eventBus.Subscribe(events.PageChanged, func(event events.Event) {
 		mw.statusBar.UpdatePageInfo(event.Payload)
 	})
+	
+	// 监听缩放变化事件
+	mw.eventBus.Subscribe(events.ZoomChanged, func(event events.Event) {
+		if zoom, ok := event.Payload.(float32); ok {
+			fyne.Do(func() {
+				mw.statusBar.UpdateZoomInfo(zoom)
+			})
+		}
+	})
+	
+	// 监听文档打开事件
+	mw.eventBus.Subscribe(events.DocumentOpened, func(event events.Event) {
+		fyne.Do(func() {
+			mw.recentMenu.ChildMenu = mw.createRecentMenu()
+			mw.menuBar.Refresh()
+		})
+	})
+	
+	// 监听标签页变化事件，打开、关闭和切换文档都会触发
+	mw.eventBus.Subscribe(events.TabsChanged, func(event events.Event) {
+		fyne.Do(mw.refreshTabs)
+	})
+	
+	// 监听错误事件
+	mw.eventBus.Subscribe(events.ErrorOccurred, func(event events.Event) {
+		if err, ok := event.Payload.(error); ok {
+			fyne.Do(func() {
+				dialog.ShowError(err, mw.window)
+			})
+		}
+	})
+	
+	// 监听分析缓存清除结果
+	mw.eventBus.Subscribe(events.AICacheCleared, func(event events.Event) {
+		stats := event.Payload.(ai.CacheStats)
+		fyne.Do(func() {
+			mw.showCacheCleared(stats)
+		})
+	})
+	
+	// 监听标注导入结果
+	mw.eventBus.Subscribe(events.HighlightsImported, func(event events.Event) {
+		fyne.Do(func() {
+			switch payload := event.Payload.(type) {
+			case *annotation.ImportReport:
+				mw.showImportReport(payload)
+			case error:
+				dialog.ShowError(payload, mw.window)
+			}
+		})
+	})
+}
+
+// setupShortcuts 注册命令并按配置绑定快捷键
+func (mw *MainWindow) setupShortcuts() {
+	mw.registerCommands()
+	mw.applyKeymap()
+	
+	// 阅读区域没有焦点时由画布分发按键，有焦点时由阅读区域转发
+	mw.window.Canvas().SetOnTypedKey(func(evt *fyne.KeyEvent) {
+		mw.handleTypedKey(evt)
+	})
+	mw.readerArea.SetOnTypedKey(mw.handleTypedKey)
 }
 
 // Show 显示窗口
@@ -206,9 +482,147 @@ func (mw *MainWindow) Show() {
 	mw.window.ShowAndRun()
 }
 
+// refreshTabs 更新标签栏、AI面板、状态栏和窗口标题，使其与活动标签页一致
+func (mw *MainWindow) refreshTabs() {
+	tabs := mw.controller.GetTabs()
+	active := mw.controller.GetActiveTab()
+	
+	ids := make([]int, 0, len(tabs))
+	for _, t := range tabs {
+		ids = append(ids, t.ID)
+	}
+	mw.tabBar.Update(tabs, active)
+	mw.aiPanel.SwitchTab(active)
+	mw.aiPanel.RetainTabs(ids)
+	mw.refreshParallel(tabs)
+	mw.syncParallel()
+	
+	doc := mw.controller.GetCurrentDocument()
+	if doc == nil {
+		mw.statusBar.UpdateDocInfo(i18n.T("status.no_document"))
+		mw.window.SetTitle("AI Reader")
+		return
+	}
+	mw.window.SetTitle(doc.GetTitle() + " - AI Reader")
+	if doc == mw.statsDoc {
+		mw.statusBar.UpdateDocInfo(mw.docSummary(doc.GetTitle(), mw.stats))
+		return
+	}
+	mw.statusBar.UpdateDocInfo(doc.GetTitle())
+	go mw.computeStats(doc)
+}
+
+// SaveState 保存窗口大小和面板分割比例，下次启动时恢复
+func (mw *MainWindow) SaveState() {
+	fullScreen := mw.window.FullScreen()
+	mw.settings.Set(configWindowMaximized, fullScreen)
+	if size := mw.window.Canvas().Size(); !fullScreen && size.Width > 0 && size.Height > 0 {
+		mw.settings.Set(configWindowWidth, float64(size.Width))
+		mw.settings.Set(configWindowHeight, float64(size.Height))
+	}
+	
+	mw.settings.Set(configPanelSplits, map[string]interface{}{
+		"file_tree":  mw.mainContent.Offset,
+		"ai_panel":   mw.mainContent.Trailing.(*container.Split).Offset,
+		"ai_history": mw.aiPanel.SplitOffset(),
+	})
+}
+
+// panelSplit 读取保存的面板分割比例，没有时使用默认值
+func (mw *MainWindow) panelSplit(name string, fallback float64) float64 {
+	if splits, ok := mw.settings.Get(configPanelSplits).(map[string]interface{}); ok {
+		if offset, ok := splits[name].(float64); ok && offset > 0 && offset < 1 {
+			return offset
+		}
+	}
+	return fallback
+}
+
+// windowSize 读取保存的窗口大小，没有时使用默认大小
+func windowSize(settings reader.Settings) fyne.Size {
+	width, height := settings.GetFloat(configWindowWidth), settings.GetFloat(configWindowHeight)
+	if width <= 0 || height <= 0 {
+		width, height = defaultWindowWidth, defaultWindowHeight
+	}
+	return fyne.NewSize(float32(width), float32(height))
+}
+
 // 菜单事件处理器
 func (mw *MainWindow) handleOpenDocument() {
-	// TODO: 实现文档打开对话框
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		path := reader.URI().Path()
+		reader.Close()
+		
+		mw.openDocument(path)
+	}, mw.window)
+}
+
+func (mw *MainWindow) handleCloseDocument() {
+	mw.controller.HandleUserInput(reader.InputCloseDocument, nil)
+}
+
+func (mw *MainWindow) handleImportHighlights() {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		path := reader.URI().Path()
+		reader.Close()
+		
+		mw.statusBar.SetStatus(i18n.T("status.importing"))
+		mw.eventBus.Publish(events.Event{
+			Type:    events.HighlightsImportRequest,
+			Payload: path,
+		})
+	}, mw.window)
+}
+
+// showImportReport 显示标注导入结果，列出未匹配的摘录
+func (mw *MainWindow) showImportReport(report *annotation.ImportReport) {
+	mw.statusBar.SetStatus(i18n.T("status.ready"))
+	
+	summary := widget.NewLabel(i18n.N("import.summary", report.Total, i18n.Args{
+		"Source":     report.Source,
+		"Imported":   len(report.Imported),
+		"Duplicates": report.Duplicates,
+		"Unmatched":  len(report.Unmatched),
+	}))
+	
+	if len(report.Unmatched) == 0 {
+		dialog.ShowCustom(i18n.T("import.title"), i18n.T("dialog.ok"), summary, mw.window)
+		return
+	}
+	
+	unmatched := widget.NewList(
+		func() int {
+			return len(report.Unmatched)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id int, obj fyne.CanvasObject) {
+			item := report.Unmatched[id]
+			text := item.Clipping.Text
+			if text == "" {
+				text = item.Clipping.Note
+			}
+			obj.(*widget.Label).SetText(i18n.T("import.unmatched", i18n.Args{
+				"Title":  item.Clipping.Title,
+				"Text":   text,
+				"Reason": fmt.Sprint(item.Reason),
+			}))
+		},
+	)
+	
+	content := container.NewBorder(summary, nil, nil, nil, unmatched)
+	d := dialog.NewCustom(i18n.T("import.title"), i18n.T("dialog.ok"), content, mw.window)
+	d.Resize(fyne.NewSize(600, 400))
+	d.Show()
 }
 
 func (mw *MainWindow) handleExit() {
@@ -220,7 +634,80 @@ func (mw *MainWindow) handleToggleFullscreen() {
 }
 
 func (mw *MainWindow) handleToggleFocusMode() {
-	// TODO: 实现专注模式
+	mw.setFocusMode(!mw.focusMode)
+	mw.settings.Set(configFocusMode, mw.focusMode)
+}
+
+// setFocusMode 进入专注模式时只保留阅读区域，隐藏文件树、AI面板、菜单和状态栏
+func (mw *MainWindow) setFocusMode(enabled bool) {
+	mw.focusMode = enabled
+	
+	readerContent := mw.readerArea.GetContainer()
+	if enabled {
+		mw.readerSlot.Objects = nil
+		mw.window.SetMainMenu(nil)
+		mw.window.SetContent(readerContent)
+	} else {
+		mw.layoutReaderSlot()
+		mw.window.SetMainMenu(mw.menuBar)
+		mw.window.SetContent(mw.content)
+	}
+	
+	mw.readerArea.SetFocusMode(enabled, mw.focusOptions())
+}
+
+// focusOptions 从配置读取专注模式选项
+func (mw *MainWindow) focusOptions() FocusOptions {
+	charsPerLine := mw.settings.GetInt(configFocusCharsPerLine)
+	if charsPerLine <= 0 {
+		charsPerLine = defaultCharsPerLine
+	}
+	return FocusOptions{
+		CharsPerLine:     charsPerLine,
+		DimParagraphs:    mw.settings.GetBool(configFocusDimParagraphs),
+		TypewriterScroll: mw.settings.GetBool(configFocusTypewriterScroll),
+	}
+}
+
+// toggleFocusOption 切换专注模式的布尔选项
+func (mw *MainWindow) toggleFocusOption(key string) {
+	mw.settings.Set(key, !mw.settings.GetBool(key))
+	
+	mw.focusMenu.ChildMenu = mw.createFocusMenu()
+	mw.menuBar.Refresh()
+	
+	if mw.focusMode {
+		mw.readerArea.SetFocusMode(true, mw.focusOptions())
+	}
+}
+
+func (mw *MainWindow) handleTransitionChange(transitionType string) {
+	mw.controller.HandleUserInput(reader.InputSetTransition, transitionType)
+	mw.refreshTransitionMenu()
+}
+
+func (mw *MainWindow) handleToggleReducedMotion() {
+	enabled := !mw.controller.GetPageTurner().IsReducedMotion()
+	mw.controller.HandleUserInput(reader.InputReducedMotion, enabled)
+	mw.refreshTransitionMenu()
+}
+
+// refreshTransitionMenu 更新翻页动画菜单的勾选状态
+func (mw *MainWindow) refreshTransitionMenu() {
+	mw.transitionMenu.ChildMenu = mw.createTransitionMenu()
+	mw.menuBar.Refresh()
+}
+
+// handleLanguageChange 保存界面语言设置，重新启动后生效
+func (mw *MainWindow) handleLanguageChange(locale string) {
+	if locale == mw.settings.GetString(configLanguage) {
+		return
+	}
+	mw.settings.Set(configLanguage, locale)
+	
+	mw.languageMenu.ChildMenu = mw.createLanguageMenu()
+	mw.menuBar.Refresh()
+	dialog.ShowInformation(i18n.T("menu.language"), i18n.T("language.restart"), mw.window)
 }
 
 func (mw *MainWindow) handleThemeChange(themeName string) {
@@ -238,8 +725,29 @@ func (mw *MainWindow) handleClearAIHistory() {
 	// TODO: 清除AI历史
 }
 
+// handleClearAICache 确认后清除内存和磁盘上缓存的分析结果
+func (mw *MainWindow) handleClearAICache() {
+	dialog.ShowConfirm(i18n.T("menu.ai_clear_cache"), i18n.T("ai.cache_confirm"), func(ok bool) {
+		if ok {
+			mw.eventBus.Publish(events.Event{Type: events.AICacheClearRequest})
+		}
+	}, mw.window)
+}
+
+// showCacheCleared 报告清除的缓存数量和占用的空间
+func (mw *MainWindow) showCacheCleared(stats ai.CacheStats) {
+	message := i18n.T("ai.cache_empty")
+	if entries := max(stats.DiskEntries, stats.MemoryEntries); entries > 0 {
+		message = i18n.N("ai.cache_cleared", entries, i18n.Args{
+			"Disk":   formatFileSize(stats.DiskBytes),
+			"Memory": formatFileSize(stats.MemoryBytes),
+		})
+	}
+	dialog.ShowInformation(i18n.T("menu.ai_clear_cache"), message, mw.window)
+}
+
 func (mw *MainWindow) handleShowHelp() {
-	// TODO: 显示帮助对话框
+	mw.showShortcuts()
 }
 
 func (mw *MainWindow) handleShowAbout() {
@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"ai-reader/internal/reader"
+	"ai-reader/pkg/animation"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/software"
+	"image"
+)
+
+// waveLift 波浪动画中竖条显现时的下沉距离，占页面高度的比例
+const waveLift = 0.04
+
+// pageTransition 翻页动画播放器，在阅读区域上方叠加新旧页面的快照并逐帧变换
+type pageTransition struct {
+	overlay *fyne.Container
+	clip    *container.Scroll
+	oldPage *canvas.Image
+	newPage *canvas.Image
+	strips  []*canvas.Image
+
+	size    fyne.Size
+	running *fyne.Animation
+}
+
+// newPageTransition 创建翻页动画播放器，初始隐藏
+func newPageTransition() *pageTransition {
+	pt := &pageTransition{
+		oldPage: canvas.NewImageFromImage(nil),
+		newPage: canvas.NewImageFromImage(nil),
+	}
+	pt.overlay = container.NewWithoutLayout(pt.oldPage, pt.newPage)
+
+	// 不可滚动的滚动容器只用来裁剪移出页面范围的图层
+	pt.clip = container.NewScroll(pt.overlay)
+	pt.clip.Direction = container.ScrollNone
+	pt.clip.Hide()
+	return pt
+}
+
+// GetContainer 获取叠加层
+func (pt *pageTransition) GetContainer() fyne.CanvasObject {
+	return pt.clip
+}
+
+// play 播放从旧页面到新页面的过渡动画
+func (pt *pageTransition) play(oldImage, newImage image.Image, size fyne.Size, params reader.PageTransition) {
+	pt.stop()
+
+	transition := animation.Get(params.Type)
+	if transition == nil {
+		return
+	}
+
+	pt.size = size
+	pt.oldPage.Image = oldImage
+	pt.newPage.Image = newImage
+	pt.prepareStrips(newImage, len(transition.Frame(0, params.Forward).Strips))
+	pt.overlay.Resize(size)
+	pt.apply(transition.Frame(0, params.Forward))
+	pt.clip.Show()
+
+	anim := fyne.NewAnimation(params.Duration, func(progress float32) {
+		pt.apply(transition.Frame(progress, params.Forward))
+		if progress >= 1 {
+			pt.finish()
+		}
+	})
+	anim.Curve = fyne.AnimationCurve(animation.GetEasing(params.Easing))
+	pt.running = anim
+	anim.Start()
+}
+
+// stop 停止正在播放的动画并移除叠加层
+func (pt *pageTransition) stop() {
+	if pt.running != nil {
+		pt.running.Stop()
+	}
+	pt.finish()
+}
+
+// finish 动画结束，隐藏叠加层并释放快照
+func (pt *pageTransition) finish() {
+	pt.running = nil
+	pt.clip.Hide()
+	pt.oldPage.Image = nil
+	pt.newPage.Image = nil
+	for _, strip := range pt.strips {
+		strip.Image = nil
+	}
+}
+
+// prepareStrips 把新页面快照切成等宽竖条
+func (pt *pageTransition) prepareStrips(img image.Image, count int) {
+	pt.strips = pt.strips[:0]
+	if count == 0 || img == nil {
+		return
+	}
+
+	sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return
+	}
+
+	bounds := img.Bounds()
+	for i := 0; i < count; i++ {
+		x0 := bounds.Min.X + bounds.Dx()*i/count
+		x1 := bounds.Min.X + bounds.Dx()*(i+1)/count
+		strip := canvas.NewImageFromImage(sub.SubImage(image.Rect(x0, bounds.Min.Y, x1, bounds.Max.Y)))
+		pt.strips = append(pt.strips, strip)
+	}
+}
+
+// apply 按帧状态摆放各图层
+func (pt *pageTransition) apply(frame animation.Frame) {
+	w, h := pt.size.Width, pt.size.Height
+
+	placeLayer(pt.oldPage, frame.Old, w, h)
+
+	objects := []fyne.CanvasObject{pt.oldPage}
+	if len(frame.Strips) > 0 && len(pt.strips) == len(frame.Strips) {
+		pt.newPage.Hide()
+		stripWidth := w / float32(len(pt.strips))
+		for i, strip := range pt.strips {
+			progress := frame.Strips[i]
+			if progress <= 0 {
+				strip.Hide()
+				continue
+			}
+			strip.Move(fyne.NewPos(stripWidth*float32(i), -(1-progress)*h*waveLift))
+			strip.Resize(fyne.NewSize(stripWidth, h))
+			strip.Translucency = float64(1 - progress)
+			strip.Show()
+			objects = append(objects, strip)
+		}
+	} else {
+		placeLayer(pt.newPage, frame.New, w, h)
+		if frame.NewOnTop {
+			objects = append(objects, pt.newPage)
+		} else {
+			objects = []fyne.CanvasObject{pt.newPage, pt.oldPage}
+		}
+	}
+
+	pt.overlay.Objects = objects
+	pt.overlay.Refresh()
+}
+
+// placeLayer 根据图层状态设置图像的位置、宽度和透明度
+func placeLayer(img *canvas.Image, layer animation.Layer, w, h float32) {
+	if !layer.Visible || layer.Alpha <= 0 || layer.ScaleX <= 0 {
+		img.Hide()
+		return
+	}
+
+	width := w * layer.ScaleX
+	img.Move(fyne.NewPos(layer.OffsetX*w+(w-width)/2, 0))
+	img.Resize(fyne.NewSize(width, h))
+	img.Translucency = float64(1 - layer.Alpha)
+	img.Show()
+}
+
+// renderPageImage 离屏渲染文本在给定视口中的画面，pos 为文本相对视口的位置，width 为文本宽度
+func renderPageImage(content string, textSize float32, size fyne.Size, pos fyne.Position, width, scale float32) image.Image {
+	page := NewSelectableText(content)
+	page.textSize = textSize
+	page.Resize(fyne.NewSize(width, 0))
+	page.Resize(fyne.NewSize(width, page.MinSize().Height))
+	page.Move(pos)
+
+	c := software.NewCanvas()
+	c.SetPadded(false)
+	c.SetScale(scale)
+	c.SetContent(container.NewWithoutLayout(page))
+	c.Resize(size)
+	return c.Capture()
+}
@@ -0,0 +1,415 @@
+package ui
+
+import (
+	"ai-reader/internal/i18n"
+	"ai-reader/internal/reader"
+	"ai-reader/pkg/align"
+	"ai-reader/pkg/document"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 并排阅读配置键
+const (
+	configParallelAlign = "parallel_align" // 对齐方式
+	configParallelSync  = "parallel_sync"  // 是否跟随主阅读区同步滚动
+)
+
+// alignIndex 文档的位置索引，在后台建立
+type alignIndex struct {
+	ready chan struct{} // 索引建好后关闭
+	index *align.Index
+}
+
+// ParallelPane 并排阅读时的对照文档区域，可跟随主阅读区同步滚动
+type ParallelPane struct {
+	container *fyne.Container
+
+	// UI组件
+	docSelect  *widget.Select
+	modeSelect *widget.Select
+	syncCheck  *widget.Check
+	content    *SelectableText
+	scroll     *zoomScroll
+	pageInfo   *widget.Label
+	prevBtn    *widget.Button
+	nextBtn    *widget.Button
+
+	// 状态
+	tabs     []reader.TabInfo // 可选的对照文档
+	doc      document.Document
+	page     int
+	updating bool // 程序更新选项时为true，不触发 OnChanged
+
+	// OnChanged 对照文档、对齐方式或同步选项变化时调用
+	OnChanged func()
+	// OnSelectionChanged 在对照文档中选中文本时调用，start、end为页内rune偏移
+	OnSelectionChanged func(text string, start, end int)
+}
+
+// NewParallelPane 创建对照文档区域
+func NewParallelPane(mode align.Mode, sync bool) *ParallelPane {
+	pp := &ParallelPane{page: 1}
+
+	pp.docSelect = widget.NewSelect(nil, func(string) {
+		pp.selectDocument(pp.docSelect.SelectedIndex())
+	})
+	pp.docSelect.PlaceHolder = i18n.T("parallel.choose_document")
+
+	modes := align.Modes()
+	names := make([]string, len(modes))
+	for i, m := range modes {
+		names[i] = i18n.T("align." + string(m))
+	}
+	pp.modeSelect = widget.NewSelect(names, func(string) { pp.changed() })
+	pp.modeSelect.SetSelectedIndex(0)
+	for i, m := range modes {
+		if m == mode {
+			pp.modeSelect.SetSelectedIndex(i)
+		}
+	}
+
+	pp.syncCheck = widget.NewCheck(i18n.T("parallel.sync"), func(bool) { pp.changed() })
+	pp.syncCheck.SetChecked(sync)
+
+	pp.content = NewSelectableText(i18n.T("parallel.empty"))
+	pp.content.OnSelectionChanged = func(text string, start, end int) {
+		if text != "" && pp.OnSelectionChanged != nil {
+			pp.OnSelectionChanged(text, start, end)
+		}
+	}
+	pp.scroll = newZoomScroll(container.New(&columnLayout{}, pp.content))
+	pp.scroll.SetMinSize(fyne.NewSize(300, 300))
+
+	pp.pageInfo = widget.NewLabel(pageInfoText(1, 1))
+	pp.prevBtn = widget.NewButton(i18n.T("page.previous_button"), func() { pp.showPage(pp.page - 1) })
+	pp.nextBtn = widget.NewButton(i18n.T("page.next_button"), func() { pp.showPage(pp.page + 1) })
+
+	header := container.NewBorder(nil, nil, nil, container.NewHBox(pp.modeSelect, pp.syncCheck), pp.docSelect)
+	toolbar := container.NewHBox(pp.prevBtn, pp.pageInfo, pp.nextBtn)
+	pp.container = container.NewBorder(header, toolbar, nil, nil, pp.scroll)
+
+	pp.updatePageInfo()
+	return pp
+}
+
+// SetDocuments 更新可选的对照文档，当前文档不在其中时改选第一个
+func (pp *ParallelPane) SetDocuments(tabs []reader.TabInfo) {
+	pp.tabs = tabs
+
+	options := make([]string, len(tabs))
+	selected := -1
+	for i, t := range tabs {
+		options[i] = tabTitle(t)
+		if t.Document == pp.doc {
+			selected = i
+		}
+	}
+	if selected < 0 && len(tabs) > 0 {
+		selected = 0
+	}
+
+	pp.updating = true
+	pp.docSelect.SetOptions(options)
+	if selected >= 0 {
+		pp.docSelect.SetSelectedIndex(selected)
+	} else {
+		pp.docSelect.ClearSelected()
+	}
+	pp.updating = false
+
+	pp.selectDocument(selected)
+}
+
+// selectDocument 显示第index个可选文档，index无效时显示提示
+func (pp *ParallelPane) selectDocument(index int) {
+	var doc document.Document
+	if index >= 0 && index < len(pp.tabs) {
+		doc = pp.tabs[index].Document
+	}
+	if doc == pp.doc {
+		return
+	}
+
+	pp.doc = doc
+	pp.page = 1
+	if doc == nil {
+		pp.content.SetContent(i18n.T("parallel.empty"))
+		pp.updatePageInfo()
+	} else {
+		pp.showPage(1)
+	}
+	pp.changed()
+}
+
+// ShowPosition 显示对照文档中的位置，使其位于视口顶部
+func (pp *ParallelPane) ShowPosition(pos align.Position) {
+	if pp.doc == nil {
+		return
+	}
+	if pos.Page != pp.page && !pp.showPage(pos.Page) {
+		return
+	}
+	y := pp.content.Position().Y + pp.content.getLayout().positionOf(pos.Offset).Y
+	pp.scroll.ScrollToOffset(fyne.NewPos(0, y))
+}
+
+// showPage 切换到对照文档的指定页并滚动到页首
+func (pp *ParallelPane) showPage(page int) bool {
+	if pp.doc == nil || page < 1 || page > pp.doc.GetPages() {
+		return false
+	}
+	content, err := pp.doc.GetPage(page)
+	if err != nil {
+		return false
+	}
+
+	pp.page = page
+	pp.content.SetContent(content)
+	pp.scroll.ScrollToTop()
+	pp.updatePageInfo()
+	return true
+}
+
+// updatePageInfo 更新页码和翻页按钮状态
+func (pp *ParallelPane) updatePageInfo() {
+	total := 1
+	if pp.doc != nil {
+		total = pp.doc.GetPages()
+	}
+	pp.pageInfo.SetText(pageInfoText(pp.page, total))
+
+	pp.prevBtn.Enable()
+	pp.nextBtn.Enable()
+	if pp.page <= 1 {
+		pp.prevBtn.Disable()
+	}
+	if pp.page >= total {
+		pp.nextBtn.Disable()
+	}
+}
+
+// changed 通知选项变化
+func (pp *ParallelPane) changed() {
+	if !pp.updating && pp.OnChanged != nil {
+		pp.OnChanged()
+	}
+}
+
+// SetTextSize 设置正文字号，与主阅读区保持一致
+func (pp *ParallelPane) SetTextSize(size float32) {
+	pp.content.SetTextSize(size)
+}
+
+// Document 获取对照文档，没有选择时为nil
+func (pp *ParallelPane) Document() document.Document {
+	return pp.doc
+}
+
+// Page 获取对照文档的当前页码
+func (pp *ParallelPane) Page() int {
+	return pp.page
+}
+
+// Mode 获取对齐方式
+func (pp *ParallelPane) Mode() align.Mode {
+	modes := align.Modes()
+	if i := pp.modeSelect.SelectedIndex(); i >= 0 && i < len(modes) {
+		return modes[i]
+	}
+	return align.ByPercent
+}
+
+// SyncEnabled 是否跟随主阅读区同步滚动
+func (pp *ParallelPane) SyncEnabled() bool {
+	return pp.syncCheck.Checked
+}
+
+// GetContainer 获取容器
+func (pp *ParallelPane) GetContainer() *fyne.Container {
+	return pp.container
+}
+
+// handleToggleParallel 进入或退出并排阅读
+func (mw *MainWindow) handleToggleParallel() {
+	mw.setParallelMode(!mw.parallelMode)
+}
+
+// setParallelMode 并排阅读时阅读区右侧显示对照文档，对照文档从其他标签页中选择
+func (mw *MainWindow) setParallelMode(enabled bool) {
+	mw.parallelMode = enabled
+	if enabled && mw.parallel == nil {
+		mode := align.Mode(mw.settings.GetString(configParallelAlign))
+		mw.parallel = NewParallelPane(mode, mw.settings.GetBool(configParallelSync))
+		mw.parallel.OnChanged = func() {
+			mw.settings.Set(configParallelAlign, string(mw.parallel.Mode()))
+			mw.settings.Set(configParallelSync, mw.parallel.SyncEnabled())
+			mw.syncParallel()
+		}
+		mw.parallel.OnSelectionChanged = mw.alignParallelSelection
+	}
+
+	if enabled {
+		mw.parallel.SetDocuments(mw.parallelCandidates())
+	} else {
+		mw.alignMu.Lock()
+		mw.alignIndexes = nil
+		mw.alignMu.Unlock()
+		mw.aiPanel.SetAlignedText("")
+	}
+	if !mw.focusMode {
+		mw.layoutReaderSlot()
+	}
+	mw.syncParallel()
+}
+
+// layoutReaderSlot 按是否并排阅读放置阅读区
+func (mw *MainWindow) layoutReaderSlot() {
+	readerContent := mw.readerArea.GetContainer()
+	if mw.parallelMode {
+		mw.readerSlot.Objects = []fyne.CanvasObject{container.NewHSplit(readerContent, mw.parallel.GetContainer())}
+	} else {
+		mw.readerSlot.Objects = []fyne.CanvasObject{readerContent}
+	}
+	mw.readerSlot.Refresh()
+}
+
+// parallelCandidates 可作为对照文档的标签页，即活动标签页以外的所有标签页
+func (mw *MainWindow) parallelCandidates() []reader.TabInfo {
+	active := mw.controller.GetActiveTab()
+	var candidates []reader.TabInfo
+	for _, t := range mw.controller.GetTabs() {
+		if t.ID != active {
+			candidates = append(candidates, t)
+		}
+	}
+	return candidates
+}
+
+// syncParallel 把对照文档滚动到与主阅读区视口顶部对齐的位置
+func (mw *MainWindow) syncParallel() {
+	if !mw.parallelMode || !mw.parallel.SyncEnabled() {
+		return
+	}
+	mw.withParallelIndexes(func(src, dst *align.Index) {
+		page, offset := mw.readerArea.ViewportPosition()
+		mw.parallel.SetTextSize(mw.readerArea.contentArea.GetTextSize())
+		mw.parallel.ShowPosition(align.Map(src, dst, align.Position{Page: page, Offset: offset}, mw.parallel.Mode()))
+	})
+}
+
+// alignReaderSelection 返回主阅读区选中的文本在对照文档中对齐的段落，不在并排阅读时返回空。
+// 由AI面板在处理选择事件时调用，不在UI线程中运行，需要时等待索引建好
+func (mw *MainWindow) alignReaderSelection(selection reader.Selection) string {
+	var primary, secondary document.Document
+	var mode align.Mode
+	fyne.DoAndWait(func() {
+		if mw.parallelMode {
+			primary, secondary = mw.controller.GetCurrentDocument(), mw.parallel.Document()
+			mode = mw.parallel.Mode()
+		}
+	})
+	if primary == nil || secondary == nil {
+		return ""
+	}
+
+	page := mw.controller.GetCurrentPage()
+	return align.Passage(mw.documentIndex(primary), mw.documentIndex(secondary),
+		align.Position{Page: page, Offset: selection.Start},
+		align.Position{Page: page, Offset: selection.End},
+		mode)
+}
+
+// alignParallelSelection 在对照文档中选中文本时，找出主阅读区文档中对齐的段落
+func (mw *MainWindow) alignParallelSelection(text string, start, end int) {
+	doc, page := mw.parallel.Document(), mw.parallel.Page()
+	mw.withParallelIndexes(func(src, dst *align.Index) {
+		aligned := align.Passage(dst, src,
+			align.Position{Page: page, Offset: start},
+			align.Position{Page: page, Offset: end},
+			mw.parallel.Mode())
+		mw.aiPanel.SetSelection(reader.Selection{Text: text, Start: start, End: end}, aligned, doc, page)
+	})
+}
+
+// withParallelIndexes 用主阅读区文档和对照文档的位置索引调用fn。索引已建好时直接调用，
+// 否则在后台建立索引，建好后在UI线程调用；期间文档有变化时不再调用
+func (mw *MainWindow) withParallelIndexes(fn func(src, dst *align.Index)) {
+	primary := mw.controller.GetCurrentDocument()
+	secondary := mw.parallel.Document()
+	if primary == nil || secondary == nil {
+		return
+	}
+
+	if src, dst := mw.builtIndex(primary), mw.builtIndex(secondary); src != nil && dst != nil {
+		fn(src, dst)
+		return
+	}
+	go func() {
+		src, dst := mw.documentIndex(primary), mw.documentIndex(secondary)
+		fyne.Do(func() {
+			if mw.parallelMode && mw.controller.GetCurrentDocument() == primary && mw.parallel.Document() == secondary {
+				fn(src, dst)
+			}
+		})
+	}()
+}
+
+// documentIndex 获取文档的位置索引，同一文档只建立一次。建立索引要读取整个文档，不应在UI线程调用
+func (mw *MainWindow) documentIndex(doc document.Document) *align.Index {
+	mw.alignMu.Lock()
+	if mw.alignIndexes == nil {
+		mw.alignIndexes = make(map[document.Document]*alignIndex)
+	}
+	entry, ok := mw.alignIndexes[doc]
+	if !ok {
+		entry = &alignIndex{ready: make(chan struct{})}
+		mw.alignIndexes[doc] = entry
+	}
+	mw.alignMu.Unlock()
+
+	if ok {
+		<-entry.ready
+		return entry.index
+	}
+	entry.index = align.NewIndex(doc)
+	close(entry.ready)
+	return entry.index
+}
+
+// builtIndex 已经建好的文档位置索引，尚未建好时返回nil
+func (mw *MainWindow) builtIndex(doc document.Document) *align.Index {
+	mw.alignMu.Lock()
+	entry, ok := mw.alignIndexes[doc]
+	mw.alignMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	select {
+	case <-entry.ready:
+		return entry.index
+	default:
+		return nil
+	}
+}
+
+// refreshParallel 标签页变化后更新可选的对照文档，丢弃已关闭文档的索引
+func (mw *MainWindow) refreshParallel(tabs []reader.TabInfo) {
+	if !mw.parallelMode {
+		return
+	}
+	open := make(map[document.Document]bool, len(tabs))
+	for _, t := range tabs {
+		open[t.Document] = true
+	}
+	mw.alignMu.Lock()
+	for doc := range mw.alignIndexes {
+		if !open[doc] {
+			delete(mw.alignIndexes, doc)
+		}
+	}
+	mw.alignMu.Unlock()
+	mw.parallel.SetDocuments(mw.parallelCandidates())
+}
@@ -2,36 +2,67 @@ package ui
 
 import (
 	"ai-reader/internal/events"
+	"ai-reader/internal/i18n"
+	"ai-reader/internal/reader"
 	"ai-reader/pkg/document"
+	"ai-reader/pkg/segment"
+	"ai-reader/pkg/theme"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	fynetheme "fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
-	"strconv"
+	"strings"
+	"sync"
 )
 
-// ReaderArea 阅读器区域
+var _ reader.ReaderView = (*ReaderArea)(nil)
+
+// FocusOptions 专注模式选项
+type FocusOptions struct {
+	CharsPerLine     int  // 文本栏宽度，按每行字符数计算
+	DimParagraphs    bool // 淡化当前段落以外的文字
+	TypewriterScroll bool // 滚动使当前行保持在视口中央
+}
+
+// ReaderArea 阅读器区域，作为 reader.Controller 的视图
 type ReaderArea struct {
 	eventBus    *events.Bus
+	controller  reader.ReaderController
 	container   *fyne.Container
 	
 	// UI组件
 	contentArea *SelectableText
+	column      *fyne.Container
+	columns     *columnLayout
+	scroll      *zoomScroll
+	pageTurn    *pageTransition
 	toolbar     *fyne.Container
 	pageInfo    *widget.Label
 	prevBtn     *widget.Button
 	nextBtn     *widget.Button
 	
-	// 状态
+	// 状态。文档和页码由控制器在后台设置、在UI线程读取，视图状态在UI线程记录、由控制器读取，
+	// 都由 stateMu 保护
+	stateMu     sync.RWMutex
 	currentDoc  document.Document
 	currentPage int
 	totalPages  int
+	viewState   reader.ViewState
 	zoom        float32
+	fontSize    float32
+	theme       theme.Theme
+	focusMode   bool
+	focus       FocusOptions
+	
+	// OnViewportChanged 滚动或换页后调用，用于并排阅读时同步对照文档
+	OnViewportChanged func()
 }
 
 // NewReaderArea 创建阅读器区域
-func NewReaderArea(eventBus *events.Bus) *ReaderArea {
+func NewReaderArea(eventBus *events.Bus, controller reader.ReaderController) *ReaderArea {
 	ra := &ReaderArea{
 		eventBus:    eventBus,
+		controller:  controller,
 		currentPage: 1,
 		totalPages:  1,
 		zoom:        1.0,
@@ -41,25 +72,30 @@ func NewReaderArea(eventBus *events.Bus) *ReaderArea {
 	ra.setupLayout()
 	ra.setupEventHandlers()
 	
+	controller.AttachView(ra, ra.contentArea)
+	
 	return ra
 }
 
 // initializeComponents 初始化组件
 func (ra *ReaderArea) initializeComponents() {
 	// 内容显示区域 - 使用可选择文本组件
-	ra.contentArea = NewSelectableText("欢迎使用AI阅读器！\n\n请打开文档开始阅读。\n\n您可以选择文本进行AI分析。")
+	ra.contentArea = NewSelectableText(i18n.T("app.welcome"))
+	
+	// 翻页动画叠加层
+	ra.pageTurn = newPageTransition()
 	
 	// 页面信息
-	ra.pageInfo = widget.NewLabel("第 1 页，共 1 页")
+	ra.pageInfo = widget.NewLabel(pageInfoText(1, 1))
 	
 	// 导航按钮
-	ra.prevBtn = widget.NewButton("◀ 上一页", ra.handlePreviousPage)
-	ra.nextBtn = widget.NewButton("下一页 ▶", ra.handleNextPage)
+	ra.prevBtn = widget.NewButton(i18n.T("page.previous_button"), ra.handlePreviousPage)
+	ra.nextBtn = widget.NewButton(i18n.T("page.next_button"), ra.handleNextPage)
 	
 	// 工具栏
 	zoomInBtn := widget.NewButton("🔍+", ra.handleZoomIn)
 	zoomOutBtn := widget.NewButton("🔍-", ra.handleZoomOut)
-	zoomResetBtn := widget.NewButton("100%", ra.handleZoomReset)
+	zoomResetBtn := widget.NewButton(i18n.FormatPercent(1), ra.handleZoomReset)
 	
 	toolbar := container.NewHBox(
 		ra.prevBtn,
@@ -77,143 +113,435 @@ func (ra *ReaderArea) initializeComponents() {
 // setupLayout 设置布局
 func (ra *ReaderArea) setupLayout() {
 	// 内容滚动区域
-	scrollContent := container.NewScroll(ra.contentArea)
-	scrollContent.SetMinSize(fyne.NewSize(400, 300))
+	// 文本按宽度折行，只需要垂直滚动
+	ra.columns = &columnLayout{}
+	ra.column = container.New(ra.columns, ra.contentArea)
+	ra.scroll = newZoomScroll(ra.column)
+	ra.scroll.SetMinSize(fyne.NewSize(400, 300))
+	ra.scroll.OnZoom = func(steps int) {
+		if steps > 0 {
+			ra.handleZoomIn()
+		} else {
+			ra.handleZoomOut()
+		}
+	}
+	ra.scroll.OnScrolled = func(fyne.Position) {
+		ra.recordScroll()
+		ra.updateActiveParagraph(ra.viewportCenterOffset())
+		ra.notifyViewportChanged()
+	}
 	
 	// 主容器
 	ra.container = container.NewBorder(
 		nil,          // 顶部
 		ra.toolbar,   // 底部工具栏
 		nil, nil,     // 左右
-		scrollContent, // 中心内容
+		container.NewStack(ra.scroll, ra.pageTurn.GetContainer()), // 中心内容，翻页动画叠加在上方
 	)
 }
 
 // setupEventHandlers 设置事件处理器
 func (ra *ReaderArea) setupEventHandlers() {
-	// 监听文档打开事件
-	ra.eventBus.Subscribe(events.DocumentOpened, func(event events.Event) {
-		filename := event.Payload.(string)
-		ra.loadDocument(filename)
-	})
-	
 	// 添加文本选择处理 - 使用鼠标事件实现
 	ra.setupTextSelection()
 }
 
-// loadDocument 加载文档
-func (ra *ReaderArea) loadDocument(filename string) {
-	// TODO: 通过文档管理器加载文档
-	// 这里先用占位文本
-	content := "# " + filename + "\n\n这是一个示例文档内容。\n\n## 主要功能\n\n- 智能文本分析\n- 多种主题切换\n- 丰富的翻页动画\n- 文档格式支持\n\n您可以选择文本进行AI分析，系统会为您提供背景信息和概念解释。"
-	ra.contentArea.SetContent(content)
-	ra.currentPage = 1
-	ra.totalPages = 1
-	ra.updatePageInfo()
-}
-
 // setupTextSelection 设置文本选择功能
 func (ra *ReaderArea) setupTextSelection() {
 	// 设置文本选择回调
-	ra.contentArea.OnSelectionChanged = func(selectedText string) {
+	ra.contentArea.OnSelectionChanged = func(selectedText string, start, end int) {
+		ra.stateMu.Lock()
+		ra.viewState.Selection = reader.Selection{Text: selectedText, Start: start, End: end}
+		ra.stateMu.Unlock()
+		
 		if selectedText != "" {
 			ra.eventBus.Publish(events.Event{
-				Type:    events.TextSelected,
-				Payload: selectedText,
+				Type: events.TextSelected,
+				Payload: reader.Selection{
+					Text:  selectedText,
+					Start: start,
+					End:   end,
+				},
 			})
 		}
 	}
-}
-
-// getSelectedText 获取当前选中的文本
-func (ra *ReaderArea) getSelectedText() string {
-	return ra.contentArea.GetSelectedText()
+	
+	// 专注模式下跟随光标
+	ra.contentArea.OnCaretMoved = func(offset int) {
+		if ra.focusMode && ra.focus.TypewriterScroll {
+			ra.centerOffset(offset)
+		}
+		ra.updateActiveParagraph(offset)
+	}
 }
 
 // handlePreviousPage 处理上一页
 func (ra *ReaderArea) handlePreviousPage() {
-	if ra.currentPage > 1 {
-		ra.currentPage--
-		ra.updatePage()
-		ra.publishPageChanged()
-	}
+	ra.controller.HandleUserInput(reader.InputPreviousPage, nil)
 }
 
 // handleNextPage 处理下一页
 func (ra *ReaderArea) handleNextPage() {
-	if ra.currentPage < ra.totalPages {
-		ra.currentPage++
-		ra.updatePage()
-		ra.publishPageChanged()
-	}
+	ra.controller.HandleUserInput(reader.InputNextPage, nil)
 }
 
 // handleZoomIn 放大
 func (ra *ReaderArea) handleZoomIn() {
-	if ra.zoom < 3.0 {
-		ra.zoom += 0.1
-		ra.applyZoom()
-	}
+	ra.controller.HandleUserInput(reader.InputZoomIn, nil)
 }
 
 // handleZoomOut 缩小
 func (ra *ReaderArea) handleZoomOut() {
-	if ra.zoom > 0.5 {
-		ra.zoom -= 0.1
-		ra.applyZoom()
-	}
+	ra.controller.HandleUserInput(reader.InputZoomOut, nil)
 }
 
 // handleZoomReset 重置缩放
 func (ra *ReaderArea) handleZoomReset() {
-	ra.zoom = 1.0
-	ra.applyZoom()
+	ra.controller.HandleUserInput(reader.InputZoomReset, nil)
 }
 
-// applyZoom 应用缩放
+// applyZoom 按缩放级别调整正文字号，重新排版后保持视口顶部的文字位置不变
 func (ra *ReaderArea) applyZoom() {
-	// TODO: 实现文本缩放
-	// 可能需要调整字体大小或容器缩放
-}
-
-// updatePage 更新页面内容
-func (ra *ReaderArea) updatePage() {
-	if ra.currentDoc != nil {
-		content, err := ra.currentDoc.GetPage(ra.currentPage)
-		if err == nil {
-			ra.contentArea.SetContent(content)
-		}
+	base := ra.fontSize
+	if base <= 0 {
+		base = fynetheme.TextSize()
 	}
-	ra.updatePageInfo()
+	size := base * ra.zoom
+	if size == ra.contentArea.GetTextSize() {
+		return
+	}
+	
+	anchor := ra.contentArea.getLayout().offsetAt(fyne.NewPos(0, ra.scroll.Offset.Y))
+	ra.contentArea.SetTextSize(size)
+	ra.updateColumn()
+	ra.scroll.Refresh()
+	
+	ra.scroll.ScrollToOffset(fyne.NewPos(0, ra.contentArea.getLayout().positionOf(anchor).Y))
+	ra.recordScroll()
 }
 
 // updatePageInfo 更新页面信息
 func (ra *ReaderArea) updatePageInfo() {
-	ra.pageInfo.SetText("第 " + strconv.Itoa(ra.currentPage) + " 页，共 " + strconv.Itoa(ra.totalPages) + " 页")
+	ra.stateMu.RLock()
+	currentPage, totalPages := ra.currentPage, ra.totalPages
+	ra.stateMu.RUnlock()
+	
+	ra.pageInfo.SetText(pageInfoText(currentPage, totalPages))
 	
 	// 更新按钮状态
 	ra.prevBtn.Enable()
 	ra.nextBtn.Enable()
 	
-	if ra.currentPage <= 1 {
+	if currentPage <= 1 {
 		ra.prevBtn.Disable()
 	}
-	if ra.currentPage >= ra.totalPages {
+	if currentPage >= totalPages {
 		ra.nextBtn.Disable()
 	}
 }
 
-// publishPageChanged 发布页面变化事件
-func (ra *ReaderArea) publishPageChanged() {
-	ra.eventBus.Publish(events.Event{
-		Type: events.PageChanged,
-		Payload: map[string]interface{}{
-			"current": ra.currentPage,
-			"total":   ra.totalPages,
-		},
+// DisplayDocument 显示文档，doc为nil时显示欢迎页
+func (ra *ReaderArea) DisplayDocument(doc document.Document) error {
+	content := i18n.T("app.welcome")
+	totalPages := 1
+	if doc != nil {
+		page, err := doc.GetPage(1)
+		if err != nil {
+			return err
+		}
+		content = page
+		totalPages = doc.GetPages()
+	}
+	
+	ra.stateMu.Lock()
+	ra.currentDoc = doc
+	ra.currentPage = 1
+	ra.totalPages = totalPages
+	ra.stateMu.Unlock()
+	
+	fyne.Do(func() {
+		ra.contentArea.SetContent(content)
+		ra.updatePageInfo()
+		ra.scrollToStart()
+	})
+	return nil
+}
+
+// SetPage 设置当前页
+func (ra *ReaderArea) SetPage(pageNum int) error {
+	doc := ra.document()
+	if doc == nil {
+		return nil
+	}
+	
+	content, err := doc.GetPage(pageNum)
+	if err != nil {
+		return err
+	}
+	ra.stateMu.Lock()
+	ra.currentPage = pageNum
+	ra.stateMu.Unlock()
+	
+	fyne.Do(func() {
+		ra.pageTurn.stop()
+		ra.contentArea.SetContent(content)
+		ra.updatePageInfo()
+		ra.scrollToStart()
+	})
+	return nil
+}
+
+// TurnPage 播放翻页动画并切换到指定页
+func (ra *ReaderArea) TurnPage(pageNum int, transition reader.PageTransition) error {
+	if transition.Type == "none" || transition.Duration <= 0 {
+		return ra.SetPage(pageNum)
+	}
+	doc := ra.document()
+	if doc == nil {
+		return nil
+	}
+	
+	content, err := doc.GetPage(pageNum)
+	if err != nil {
+		return err
+	}
+	ra.stateMu.Lock()
+	ra.currentPage = pageNum
+	ra.stateMu.Unlock()
+	
+	fyne.Do(func() {
+		size := ra.scroll.Size()
+		oldContent := ra.contentArea.GetContent()
+		pagePos := ra.contentArea.Position()
+		offset := ra.scroll.Offset.Y
+		
+		ra.contentArea.SetContent(content)
+		ra.updatePageInfo()
+		ra.scrollToStart()
+		
+		if size.Width <= 0 || size.Height <= 0 {
+			return
+		}
+		
+		// 按窗口缩放渲染快照，避免动画期间文字模糊
+		scale := float32(1)
+		if c := fyne.CurrentApp().Driver().CanvasForObject(ra.scroll); c != nil {
+			scale = c.Scale()
+		}
+		
+		textSize, width := ra.contentArea.GetTextSize(), ra.contentArea.Size().Width
+		oldImage := renderPageImage(oldContent, textSize, size, pagePos.SubtractXY(0, offset), width, scale)
+		newImage := renderPageImage(content, textSize, size, ra.contentArea.Position().SubtractXY(0, ra.scroll.Offset.Y), width, scale)
+		ra.pageTurn.play(oldImage, newImage, size, transition)
+	})
+	return nil
+}
+
+// GetCurrentPage 获取当前页
+func (ra *ReaderArea) GetCurrentPage() int {
+	ra.stateMu.RLock()
+	defer ra.stateMu.RUnlock()
+	return ra.currentPage
+}
+
+// document 当前显示的文档，显示欢迎页时为nil
+func (ra *ReaderArea) document() document.Document {
+	ra.stateMu.RLock()
+	defer ra.stateMu.RUnlock()
+	return ra.currentDoc
+}
+
+// SetZoom 设置缩放级别
+func (ra *ReaderArea) SetZoom(level float32) {
+	ra.zoom = level
+	fyne.Do(ra.applyZoom)
+}
+
+// GetZoom 获取当前缩放级别
+func (ra *ReaderArea) GetZoom() float32 {
+	return ra.zoom
+}
+
+// SetFontSize 设置基准字号
+func (ra *ReaderArea) SetFontSize(size float32) {
+	ra.fontSize = size
+	fyne.Do(ra.applyZoom)
+}
+
+// ApplyTheme 应用主题
+func (ra *ReaderArea) ApplyTheme(t theme.Theme) {
+	ra.theme = t
+	fyne.Do(ra.Refresh)
+}
+
+// GetSelectedText 获取选中的文本
+func (ra *ReaderArea) GetSelectedText() string {
+	text, _, _ := ra.contentArea.GetSelectedText()
+	return text
+}
+
+// ClearSelection 清除选择
+func (ra *ReaderArea) ClearSelection() {
+	fyne.Do(ra.contentArea.clearSelection)
+}
+
+// ScrollToPosition 滚动到指定位置（0-1之间的比例）
+func (ra *ReaderArea) ScrollToPosition(position float32) {
+	fyne.Do(func() {
+		maxOffset := ra.contentArea.MinSize().Height - ra.scroll.Size().Height
+		if maxOffset < 0 {
+			maxOffset = 0
+		}
+		ra.scroll.Offset.Y = maxOffset * position
+		ra.scroll.Refresh()
+		ra.recordScroll()
+	})
+}
+
+// GetScrollPosition 获取滚动位置（0-1之间的比例）
+func (ra *ReaderArea) GetScrollPosition() float32 {
+	maxOffset := ra.contentArea.MinSize().Height - ra.scroll.Size().Height
+	if maxOffset <= 0 {
+		return 0
+	}
+	return ra.scroll.Offset.Y / maxOffset
+}
+
+// Snapshot 获取UI线程最近记录的滚动位置和选区
+func (ra *ReaderArea) Snapshot() reader.ViewState {
+	ra.stateMu.RLock()
+	defer ra.stateMu.RUnlock()
+	return ra.viewState
+}
+
+// recordScroll 记录滚动位置，程序滚动后也要调用，ScrollToOffset 不会触发 OnScrolled
+func (ra *ReaderArea) recordScroll() {
+	scroll := ra.GetScrollPosition()
+	ra.stateMu.Lock()
+	ra.viewState.Scroll = scroll
+	ra.stateMu.Unlock()
+}
+
+// SetOnTypedKey 设置阅读区域获得焦点时的按键处理，返回true表示按键已被处理
+func (ra *ReaderArea) SetOnTypedKey(handler func(evt *fyne.KeyEvent) bool) {
+	ra.contentArea.OnTypedKey = handler
+}
+
+// SelectRange 选中当前页中的区间并滚动到可见位置
+func (ra *ReaderArea) SelectRange(start, end int) {
+	fyne.Do(func() {
+		ra.contentArea.selectSpan(segment.Span{Start: start, End: end})
+		ra.revealOffset(start)
 	})
 }
 
+// revealOffset 偏移所在的行不在视口内时滚动使其可见
+func (ra *ReaderArea) revealOffset(offset int) {
+	if ra.focusMode && ra.focus.TypewriterScroll {
+		ra.centerOffset(offset)
+		return
+	}
+	
+	layout := ra.contentArea.getLayout()
+	y := ra.contentArea.Position().Y + layout.positionOf(offset).Y
+	top, height := ra.scroll.Offset.Y, ra.scroll.Size().Height
+	if y >= top && y+layout.lineHeight <= top+height {
+		return
+	}
+	// 把目标行放在视口上方三分之一处，保留上文
+	ra.scroll.ScrollToOffset(fyne.NewPos(0, y-height/3))
+	ra.recordScroll()
+	ra.updateActiveParagraph(offset)
+}
+
+// SetFocusMode 进入或退出专注模式
+func (ra *ReaderArea) SetFocusMode(enabled bool, options FocusOptions) {
+	ra.focusMode = enabled
+	ra.focus = options
+	
+	ra.updateColumn()
+	ra.column.Refresh()
+	ra.scroll.Refresh()
+	ra.updateActiveParagraph(ra.viewportCenterOffset())
+}
+
+// updateColumn 根据专注模式设置文本栏宽度和上下留白
+func (ra *ReaderArea) updateColumn() {
+	ra.columns.maxWidth = 0
+	ra.columns.overscroll = 0
+	if !ra.focusMode {
+		return
+	}
+	
+	if ra.focus.CharsPerLine > 0 {
+		// 以拉丁字母宽度估算每行字符数，中文每字约占两个字母宽
+		sample := strings.Repeat("x", ra.focus.CharsPerLine)
+		width := fyne.MeasureText(sample, ra.contentArea.GetTextSize(), fyne.TextStyle{}).Width
+		ra.columns.maxWidth = width + fynetheme.InnerPadding()*2
+	}
+	if ra.focus.TypewriterScroll {
+		ra.columns.overscroll = ra.scroll.Size().Height / 2
+	}
+}
+
+// scrollToStart 滚动到页首，打字机滚动时让首行位于视口中央
+func (ra *ReaderArea) scrollToStart() {
+	if ra.focusMode && ra.focus.TypewriterScroll {
+		ra.centerOffset(0)
+	} else {
+		ra.scroll.ScrollToTop()
+	}
+	ra.recordScroll()
+	ra.updateActiveParagraph(0)
+	ra.notifyViewportChanged()
+}
+
+// ViewportPosition 当前页码和视口顶部的rune偏移
+func (ra *ReaderArea) ViewportPosition() (page, offset int) {
+	y := ra.scroll.Offset.Y - ra.contentArea.Position().Y
+	return ra.GetCurrentPage(), ra.contentArea.getLayout().offsetAt(fyne.NewPos(0, y))
+}
+
+// notifyViewportChanged 通知视口位置变化
+func (ra *ReaderArea) notifyViewportChanged() {
+	if ra.OnViewportChanged != nil && ra.document() != nil {
+		ra.OnViewportChanged()
+	}
+}
+
+// viewportCenterOffset 视口中央所在的rune偏移
+func (ra *ReaderArea) viewportCenterOffset() int {
+	y := ra.scroll.Offset.Y + ra.scroll.Size().Height/2 - ra.contentArea.Position().Y
+	return ra.contentArea.getLayout().offsetAt(fyne.NewPos(0, y))
+}
+
+// centerOffset 滚动使偏移所在的行位于视口中央
+func (ra *ReaderArea) centerOffset(offset int) {
+	// 视口高度可能已变化，先更新留白
+	ra.updateColumn()
+	ra.column.Refresh()
+	
+	layout := ra.contentArea.getLayout()
+	y := ra.contentArea.Position().Y + layout.positionOf(offset).Y + layout.lineHeight/2 - ra.scroll.Size().Height/2
+	ra.scroll.ScrollToOffset(fyne.NewPos(0, y))
+	ra.recordScroll()
+}
+
+// updateActiveParagraph 专注模式下淡化偏移所在段落以外的文字
+func (ra *ReaderArea) updateActiveParagraph(offset int) {
+	if !ra.focusMode || !ra.focus.DimParagraphs || ra.document() == nil {
+		ra.contentArea.ClearActiveSpan()
+		return
+	}
+	runes := []rune(ra.contentArea.GetContent())
+	ra.contentArea.SetActiveSpan(segment.ParagraphAt(runes, offset))
+}
+
+// Refresh 刷新显示
+func (ra *ReaderArea) Refresh() {
+	ra.container.Refresh()
+}
+
 // GetContainer 获取容器
 func (ra *ReaderArea) GetContainer() *fyne.Container {
 	return ra.container
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"ai-reader/internal/i18n"
+	"ai-reader/internal/reader"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"unicode"
+)
+
+// searchDialog 在当前文档中查找文本，从当前页向后查找并循环到开头
+type searchDialog struct {
+	window     fyne.Window
+	controller reader.ReaderController
+	readerArea *ReaderArea
+
+	entry  *widget.Entry
+	status *widget.Label
+	dialog dialog.Dialog
+
+	// 上一次匹配的位置，用于查找下一个
+	lastQuery string
+	lastPage  int
+	lastEnd   int
+}
+
+// newSearchDialog 创建查找对话框
+func newSearchDialog(window fyne.Window, controller reader.ReaderController, readerArea *ReaderArea) *searchDialog {
+	sd := &searchDialog{
+		window:     window,
+		controller: controller,
+		readerArea: readerArea,
+	}
+
+	sd.entry = widget.NewEntry()
+	sd.entry.SetPlaceHolder(i18n.T("search.placeholder"))
+	sd.entry.OnSubmitted = func(string) { sd.findNext() }
+
+	sd.status = widget.NewLabel("")
+
+	content := container.NewVBox(
+		sd.entry,
+		container.NewHBox(widget.NewButton(i18n.T("search.next"), sd.findNext), sd.status),
+	)
+	sd.dialog = dialog.NewCustom(i18n.T("search.title"), i18n.T("dialog.close"), content, window)
+	sd.dialog.Resize(fyne.NewSize(400, 0))
+	return sd
+}
+
+// Show 显示对话框并聚焦输入框
+func (sd *searchDialog) Show() {
+	sd.status.SetText("")
+	sd.dialog.Show()
+	sd.window.Canvas().Focus(sd.entry)
+}
+
+// findNext 查找下一个匹配
+func (sd *searchDialog) findNext() {
+	doc := sd.controller.GetCurrentDocument()
+	if doc == nil {
+		sd.status.SetText(i18n.T("search.no_document"))
+		return
+	}
+
+	query := []rune(sd.entry.Text)
+	if len(query) == 0 {
+		return
+	}
+
+	total := doc.GetPages()
+	if total < 1 {
+		return
+	}
+	startPage := sd.controller.GetCurrentPage()
+	from := 0
+	if sd.entry.Text == sd.lastQuery && sd.lastPage == startPage {
+		from = sd.lastEnd
+	}
+
+	// 多查一次起始页，覆盖起始位置之前的部分
+	for i := 0; i <= total; i++ {
+		page := (startPage-1+i)%total + 1
+		content, err := doc.GetPage(page)
+		if err != nil {
+			sd.status.SetText(err.Error())
+			return
+		}
+
+		offset := 0
+		if i == 0 {
+			offset = from
+		}
+		index := indexFold([]rune(content), query, offset)
+		if index < 0 {
+			continue
+		}
+
+		sd.lastQuery = sd.entry.Text
+		sd.lastPage = page
+		sd.lastEnd = index + len(query)
+
+		if page != sd.controller.GetCurrentPage() {
+			sd.controller.HandleUserInput(reader.InputGoToPage, page)
+		}
+		sd.readerArea.SelectRange(index, index+len(query))
+		sd.status.SetText(i18n.T("page.number", i18n.Args{"Page": page}))
+		return
+	}
+
+	sd.status.SetText(i18n.T("search.not_found"))
+}
+
+// indexFold 从from开始查找query，忽略大小写，返回rune偏移，未找到返回-1
+func indexFold(text, query []rune, from int) int {
+	for i := from; i+len(query) <= len(text); i++ {
+		matched := true
+		for j, r := range query {
+			if unicode.ToLower(text[i+j]) != unicode.ToLower(r) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
@@ -1,52 +1,135 @@
 package ui
 
 import (
+	"ai-reader/internal/reader"
+	"ai-reader/pkg/segment"
 	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/widget"
 	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	fynetheme "fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
 	"image/color"
-	"strings"
+	"time"
+)
+
+const (
+	// multiClickInterval 多击判定的最大间隔
+	multiClickInterval = 400 * time.Millisecond
+
+	// multiClickSlop 多击判定允许的指针移动距离
+	multiClickSlop = 4
+
+	// dimmedAlpha 专注模式下非当前段落文字的不透明度
+	dimmedAlpha = 0.35
 )
 
+var _ reader.TextSelector = (*SelectableText)(nil)
+
 // SelectableText 可选择的文本组件
 type SelectableText struct {
 	widget.BaseWidget
-	
-	content      string
-	richText     *widget.RichText
-	overlay      *canvas.Rectangle
-	
-	// 选择状态
+
+	content  string
+	runes    []rune
+	textSize float32
+
+	// 排版缓存
+	layout     *textLayout
+	layoutSize fyne.Size
+	runeWidths map[rune]float32
+
+	// 选择状态，以rune偏移表示；anchor为选择起点，caret为当前端点
 	isSelecting  bool
-	selectionStart fyne.Position
-	selectionEnd   fyne.Position
-	selectedText   string
-	
+	anchor       int
+	caret        int
+	selectedText string
+
+	// 多击检测：双击选词、三击选句、四击或Alt+单击选段
+	lastTapTime time.Time
+	lastTapPos  fyne.Position
+	tapCount    int
+	tapModifier fyne.KeyModifier
+
+	// 键盘选择
+	focused           bool
+	shiftDown         bool
+	keyboardSelecting bool
+
+	// 专注模式下只有该区间内的文字正常显示，其余淡化
+	dimming    bool
+	activeSpan segment.Span
+
 	// 回调函数
-	OnSelectionChanged func(selectedText string)
+	OnSelectionChanged func(selectedText string, start, end int)
+	OnCaretMoved       func(offset int)
+
+	// OnTypedKey 处理不移动光标的按键，返回true表示按键已被处理（如绑定了命令）
+	OnTypedKey func(evt *fyne.KeyEvent) bool
 }
 
 // NewSelectableText 创建新的可选择文本组件
 func NewSelectableText(content string) *SelectableText {
 	st := &SelectableText{
-		content:  content,
-		richText: widget.NewRichText(),
-		overlay:  canvas.NewRectangle(color.RGBA{0, 123, 255, 50}), // 半透明蓝色选择框
-	}
-	
-	st.richText.ParseMarkdown(content)
-	st.richText.Wrapping = fyne.TextWrapWord
-	st.overlay.Hide()
-	
+		textSize:   fynetheme.TextSize(),
+		runeWidths: make(map[rune]float32),
+	}
+	st.setContent(content)
+
 	st.ExtendBaseWidget(st)
 	return st
 }
 
 // SetContent 设置文本内容
 func (st *SelectableText) SetContent(content string) {
-	st.content = content
-	st.richText.ParseMarkdown(content)
+	st.setContent(content)
 	st.clearSelection()
+	st.Refresh()
+}
+
+func (st *SelectableText) setContent(content string) {
+	st.content = content
+	st.runes = []rune(content)
+	st.layout = nil
+}
+
+// SetTextSize 设置字号并重新排版
+func (st *SelectableText) SetTextSize(size float32) {
+	if size == st.textSize {
+		return
+	}
+	st.textSize = size
+	st.runeWidths = make(map[rune]float32)
+	st.layout = nil
+	st.Refresh()
+}
+
+// GetTextSize 获取字号
+func (st *SelectableText) GetTextSize() float32 {
+	return st.textSize
+}
+
+// SetActiveSpan 淡化区间之外的文字
+func (st *SelectableText) SetActiveSpan(span segment.Span) {
+	if st.dimming && st.activeSpan == span {
+		return
+	}
+	st.dimming = true
+	st.activeSpan = span
+	st.Refresh()
+}
+
+// ClearActiveSpan 取消淡化
+func (st *SelectableText) ClearActiveSpan() {
+	if !st.dimming {
+		return
+	}
+	st.dimming = false
+	st.Refresh()
+}
+
+// GetCaret 获取光标的rune偏移
+func (st *SelectableText) GetCaret() int {
+	return st.caret
 }
 
 // GetContent 获取文本内容
@@ -54,32 +137,324 @@ func (st *SelectableText) GetContent() string {
 	return st.content
 }
 
-// GetSelectedText 获取选中的文本
-func (st *SelectableText) GetSelectedText() string {
+// GetSelectedText 获取选中的文本及其在内容中的rune偏移
+func (st *SelectableText) GetSelectedText() (text string, start, end int) {
+	start, end = st.GetSelectionBounds()
+	return st.selectedText, start, end
+}
+
+// StartSelection 在指定坐标开始选择
+func (st *SelectableText) StartSelection(x, y float32) {
+	offset := st.getLayout().offsetAt(fyne.NewPos(x, y))
+	st.isSelecting = true
+	st.anchor = offset
+	st.caret = offset
+	st.Refresh()
+}
+
+// UpdateSelection 将选择端点更新到指定坐标
+func (st *SelectableText) UpdateSelection(x, y float32) {
+	if !st.isSelecting {
+		return
+	}
+	st.caret = st.getLayout().offsetAt(fyne.NewPos(x, y))
+	st.Refresh()
+}
+
+// EndSelection 结束选择并返回选中的文本
+func (st *SelectableText) EndSelection() string {
+	st.isSelecting = false
+	st.finalizeSelection()
 	return st.selectedText
 }
 
+// GetSelectionBounds 获取选择范围 [start,end)
+func (st *SelectableText) GetSelectionBounds() (start, end int) {
+	if st.anchor <= st.caret {
+		return st.anchor, st.caret
+	}
+	return st.caret, st.anchor
+}
+
 // clearSelection 清除选择
 func (st *SelectableText) clearSelection() {
+	hadSelection := st.selectedText != ""
 	st.selectedText = ""
-	st.overlay.Hide()
+	st.anchor = 0
+	st.caret = 0
 	st.isSelecting = false
-	if st.OnSelectionChanged != nil {
-		st.OnSelectionChanged("")
+	st.Refresh()
+	if hadSelection && st.OnSelectionChanged != nil {
+		st.OnSelectionChanged("", 0, 0)
 	}
 }
 
 // CreateRenderer 创建渲染器
 func (st *SelectableText) CreateRenderer() fyne.WidgetRenderer {
-	return &selectableTextRenderer{
+	r := &selectableTextRenderer{
 		selectableText: st,
-		objects:       []fyne.CanvasObject{st.richText, st.overlay},
+		caret:          canvas.NewRectangle(fynetheme.Color(fynetheme.ColorNamePrimary)),
 	}
+	r.Refresh()
+	return r
 }
 
-// Tapped 处理单击事件
+// Cursor 鼠标悬停时显示文本光标
+func (st *SelectableText) Cursor() desktop.Cursor {
+	return desktop.TextCursor
+}
+
+// Tapped 处理单击事件，连续点击依次选择词、句子和段落
 func (st *SelectableText) Tapped(evt *fyne.PointEvent) {
+	st.requestFocus()
+
+	now := time.Now()
+	delta := evt.Position.Subtract(st.lastTapPos)
+	if now.Sub(st.lastTapTime) <= multiClickInterval &&
+		delta.X*delta.X+delta.Y*delta.Y <= multiClickSlop*multiClickSlop {
+		st.tapCount++
+	} else {
+		st.tapCount = 1
+	}
+	st.lastTapTime = now
+	st.lastTapPos = evt.Position
+
+	x, y := evt.Position.X, evt.Position.Y
+	if st.tapModifier&fyne.KeyModifierAlt != 0 {
+		st.SelectParagraph(x, y)
+		return
+	}
+
+	switch st.tapCount {
+	case 1:
+		offset := st.getLayout().offsetAt(evt.Position)
+		st.clearSelection()
+		st.anchor = offset
+		st.caret = offset
+		st.Refresh()
+		st.notifyCaretMoved()
+	case 2:
+		st.SelectWord(x, y)
+	case 3:
+		st.SelectSentence(x, y)
+	default:
+		st.SelectParagraph(x, y)
+	}
+}
+
+// MouseDown 记录点击时按下的修饰键
+func (st *SelectableText) MouseDown(evt *desktop.MouseEvent) {
+	st.tapModifier = evt.Modifier
+}
+
+// MouseUp 鼠标抬起
+func (st *SelectableText) MouseUp(evt *desktop.MouseEvent) {
+}
+
+// SelectWord 选择坐标处的词，中文按词典分词
+func (st *SelectableText) SelectWord(x, y float32) string {
+	offset := st.getLayout().runeAt(fyne.NewPos(x, y))
+	if offset < 0 {
+		return ""
+	}
+	return st.selectSpan(segment.Default().WordAt(st.runes, offset))
+}
+
+// SelectSentence 选择坐标处的句子
+func (st *SelectableText) SelectSentence(x, y float32) string {
+	offset := st.getLayout().runeAt(fyne.NewPos(x, y))
+	if offset < 0 {
+		return ""
+	}
+	return st.selectSpan(segment.SentenceAt(st.runes, offset))
+}
+
+// SelectParagraph 选择坐标处的段落
+func (st *SelectableText) SelectParagraph(x, y float32) string {
+	offset := st.getLayout().runeAt(fyne.NewPos(x, y))
+	if offset < 0 {
+		return ""
+	}
+	return st.selectSpan(segment.ParagraphAt(st.runes, offset))
+}
+
+// selectSpan 选中指定区间
+func (st *SelectableText) selectSpan(span segment.Span) string {
+	st.isSelecting = false
+	st.anchor = span.Start
+	st.caret = span.End
+	st.finalizeSelection()
+	st.Refresh()
+	return st.selectedText
+}
+
+// requestFocus 获取键盘焦点，以便用方向键扩展选择
+func (st *SelectableText) requestFocus() {
+	if c := fyne.CurrentApp().Driver().CanvasForObject(st); c != nil {
+		c.Focus(st)
+	}
+}
+
+// FocusGained 获得焦点
+func (st *SelectableText) FocusGained() {
+	st.focused = true
+	st.Refresh()
+}
+
+// FocusLost 失去焦点
+func (st *SelectableText) FocusLost() {
+	st.focused = false
+	st.shiftDown = false
+	st.Refresh()
+}
+
+// TypedRune 文本组件只读，忽略字符输入
+func (st *SelectableText) TypedRune(r rune) {
+}
+
+// TypedKey 方向键、Home和End移动光标，按住Shift时扩展选择；其余按键交给 OnTypedKey
+func (st *SelectableText) TypedKey(evt *fyne.KeyEvent) {
+	offset, ok := st.caretTarget(evt.Name, false)
+	if !ok {
+		if st.OnTypedKey != nil {
+			st.OnTypedKey(evt)
+		}
+		return
+	}
+	st.moveCaret(offset, st.shiftDown)
+}
+
+// TypedShortcut Ctrl+左右方向键按词移动光标，再按住Shift时按词扩展选择。
+// 驱动把带Ctrl的按键作为快捷键发给有焦点的组件，其余快捷键转交画布，由画布上注册的命令处理
+func (st *SelectableText) TypedShortcut(shortcut fyne.Shortcut) {
+	if custom, ok := shortcut.(*desktop.CustomShortcut); ok && (custom.KeyName == fyne.KeyLeft || custom.KeyName == fyne.KeyRight) {
+		word := custom.Modifier &^ fyne.KeyModifierShift
+		if word == fyne.KeyModifierControl || word == fyne.KeyModifierSuper {
+			offset, _ := st.caretTarget(custom.KeyName, true)
+			st.moveCaret(offset, custom.Modifier&fyne.KeyModifierShift != 0)
+			return
+		}
+	}
+
+	if c, ok := fyne.CurrentApp().Driver().CanvasForObject(st).(fyne.Shortcutable); ok {
+		c.TypedShortcut(shortcut)
+	}
+}
+
+// moveCaret 移动光标，extend为true时从锚点扩展选择，否则取消选择
+func (st *SelectableText) moveCaret(offset int, extend bool) {
+	if extend {
+		st.caret = offset
+		st.keyboardSelecting = true
+		st.Refresh()
+		st.notifyCaretMoved()
+		return
+	}
+
 	st.clearSelection()
+	st.anchor = offset
+	st.caret = offset
+	st.Refresh()
+	st.notifyCaretMoved()
+}
+
+// notifyCaretMoved 通知光标位置变化
+func (st *SelectableText) notifyCaretMoved() {
+	if st.OnCaretMoved != nil {
+		st.OnCaretMoved(st.caret)
+	}
+}
+
+// KeyDown 跟踪Shift键状态
+func (st *SelectableText) KeyDown(evt *fyne.KeyEvent) {
+	switch evt.Name {
+	case desktop.KeyShiftLeft, desktop.KeyShiftRight:
+		st.shiftDown = true
+	}
+}
+
+// KeyUp 松开Shift时完成键盘选择
+func (st *SelectableText) KeyUp(evt *fyne.KeyEvent) {
+	switch evt.Name {
+	case desktop.KeyShiftLeft, desktop.KeyShiftRight:
+		st.shiftDown = false
+		if st.keyboardSelecting {
+			st.keyboardSelecting = false
+			st.finalizeSelection()
+		}
+	}
+}
+
+// caretTarget 计算按键后光标的目标偏移，word为true时左右方向键按词移动
+func (st *SelectableText) caretTarget(key fyne.KeyName, word bool) (int, bool) {
+	layout := st.getLayout()
+	offset := st.caret
+
+	switch key {
+	case fyne.KeyLeft:
+		if word {
+			offset = st.previousWordStart(offset)
+		} else {
+			offset--
+		}
+	case fyne.KeyRight:
+		if word {
+			offset = st.nextWordEnd(offset)
+		} else {
+			offset++
+		}
+	case fyne.KeyUp, fyne.KeyDown:
+		pos := layout.positionOf(offset)
+		if key == fyne.KeyUp {
+			pos.Y -= layout.lineHeight
+		} else {
+			pos.Y += layout.lineHeight
+		}
+		if pos.Y < layout.padding {
+			return 0, true
+		}
+		offset = layout.offsetAt(fyne.NewPos(pos.X, pos.Y+layout.lineHeight/2))
+	case fyne.KeyHome:
+		if line := layout.lineOf(offset); line >= 0 {
+			offset = layout.lines[line].start
+		}
+	case fyne.KeyEnd:
+		if line := layout.lineOf(offset); line >= 0 {
+			offset = layout.lines[line].end
+		}
+	default:
+		return 0, false
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(st.runes) {
+		offset = len(st.runes)
+	}
+	return offset, true
+}
+
+// previousWordStart 光标前一个词的起点
+func (st *SelectableText) previousWordStart(offset int) int {
+	result := 0
+	for _, span := range segment.Default().Words(st.runes) {
+		if span.Start >= offset {
+			break
+		}
+		result = span.Start
+	}
+	return result
+}
+
+// nextWordEnd 光标后一个词的终点
+func (st *SelectableText) nextWordEnd(offset int) int {
+	for _, span := range segment.Default().Words(st.runes) {
+		if span.End > offset {
+			return span.End
+		}
+	}
+	return len(st.runes)
 }
 
 // TappedSecondary 处理右键点击
@@ -90,102 +465,140 @@ func (st *SelectableText) TappedSecondary(evt *fyne.PointEvent) {
 // Dragged 处理拖拽事件（用于文本选择）
 func (st *SelectableText) Dragged(evt *fyne.DragEvent) {
 	if !st.isSelecting {
-		st.isSelecting = true
-		st.selectionStart = evt.Position
-		st.overlay.Show()
+		// 拖拽事件在移动一段距离后才触发，起点需要减去已拖动的距离
+		origin := evt.Position.Subtract(evt.Dragged)
+		st.StartSelection(origin.X, origin.Y)
 	}
-	
-	st.selectionEnd = evt.Position
-	st.updateSelection()
+
+	st.UpdateSelection(evt.Position.X, evt.Position.Y)
 }
 
 // DragEnd 拖拽结束
 func (st *SelectableText) DragEnd() {
 	if st.isSelecting {
-		st.finalizeSelection()
+		st.EndSelection()
 	}
 }
 
-// updateSelection 更新选择区域
-func (st *SelectableText) updateSelection() {
-	if !st.isSelecting {
-		return
-	}
-	
-	// 计算选择矩形
-	x1, y1 := st.selectionStart.X, st.selectionStart.Y
-	x2, y2 := st.selectionEnd.X, st.selectionEnd.Y
-	
-	// 确保矩形坐标正确
-	if x1 > x2 {
-		x1, x2 = x2, x1
-	}
-	if y1 > y2 {
-		y1, y2 = y2, y1
-	}
-	
-	st.overlay.Move(fyne.NewPos(x1, y1))
-	st.overlay.Resize(fyne.NewSize(x2-x1, y2-y1))
-	st.overlay.Refresh()
-}
-
-// finalizeSelection 完成选择
+// finalizeSelection 完成选择，提取选区内的原始文本
 func (st *SelectableText) finalizeSelection() {
-	st.isSelecting = false
-	
-	// 这里应该根据选择区域提取实际的文本
-	// 为了简化，我们提取内容的一部分作为示例
-	st.selectedText = st.extractTextFromSelection()
-	
+	start, end := st.GetSelectionBounds()
+	st.selectedText = string(st.runes[start:end])
+
 	if st.OnSelectionChanged != nil && st.selectedText != "" {
-		st.OnSelectionChanged(st.selectedText)
+		st.OnSelectionChanged(st.selectedText, start, end)
 	}
 }
 
-// extractTextFromSelection 从选择区域提取文本
-func (st *SelectableText) extractTextFromSelection() string {
-	if st.content == "" {
-		return ""
+// getLayout 获取当前尺寸下的排版结果
+func (st *SelectableText) getLayout() *textLayout {
+	size := st.Size()
+	if st.layout == nil || size.Width != st.layoutSize.Width {
+		st.layout = layoutText(st.runes, size.Width, fynetheme.InnerPadding(), st.textHeight(), st.measureRune)
+		st.layoutSize = size
 	}
-	
-	// 简化实现：基于选择区域的相对位置提取文本
-	// 实际实现中需要更复杂的文本位置计算
-	lines := strings.Split(st.content, "\n")
-	if len(lines) == 0 {
-		return ""
+	return st.layout
+}
+
+// textHeight 单行文字高度
+func (st *SelectableText) textHeight() float32 {
+	return fyne.MeasureText("M", st.textSize, fyne.TextStyle{}).Height
+}
+
+// measureRune 测量单个字符宽度，结果按字符缓存
+func (st *SelectableText) measureRune(r rune) float32 {
+	if r == '\t' {
+		return st.measureRune(' ') * 4
 	}
-	
-	// 模拟选择了第一段内容
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line != "" && !strings.HasPrefix(line, "#") {
-			// 返回第一个非标题行
-			if len(line) > 50 {
-				return line[:50] + "..."
-			}
-			return line
-		}
+	if w, ok := st.runeWidths[r]; ok {
+		return w
 	}
-	
-	return "选中的文本内容"
+	w := fyne.MeasureText(string(r), st.textSize, fyne.TextStyle{}).Width
+	st.runeWidths[r] = w
+	return w
 }
 
-// selectableTextRenderer 渲染器实现
+// selectableTextRenderer 渲染器实现，逐行绘制文本并在下方绘制选择高亮
 type selectableTextRenderer struct {
 	selectableText *SelectableText
+	caret          *canvas.Rectangle
+	highlights     []*canvas.Rectangle
+	lines          []*canvas.Text
 	objects        []fyne.CanvasObject
 }
 
 func (r *selectableTextRenderer) Layout(size fyne.Size) {
-	r.selectableText.richText.Resize(size)
+	r.Refresh()
 }
 
 func (r *selectableTextRenderer) MinSize() fyne.Size {
-	return r.selectableText.richText.MinSize()
+	st := r.selectableText
+	if st.Size().Width == 0 {
+		return fyne.NewSize(fynetheme.InnerPadding()*2, st.textHeight())
+	}
+	// 宽度随容器折行，只有高度由内容决定
+	return fyne.NewSize(fynetheme.InnerPadding()*2, st.getLayout().size().Height)
 }
 
 func (r *selectableTextRenderer) Refresh() {
-	r.selectableText.richText.Refresh()
+	st := r.selectableText
+	layout := st.getLayout()
+
+	// 选择高亮
+	start, end := st.GetSelectionBounds()
+	rects := layout.selectionRects(start, end)
+	selectionColor := fynetheme.Color(fynetheme.ColorNameSelection)
+	for len(r.highlights) < len(rects) {
+		r.highlights = append(r.highlights, canvas.NewRectangle(selectionColor))
+	}
+	r.highlights = r.highlights[:len(rects)]
+	for i, rect := range rects {
+		r.highlights[i].FillColor = selectionColor
+		r.highlights[i].Move(rect.pos)
+		r.highlights[i].Resize(rect.size)
+		r.highlights[i].Refresh()
+	}
+
+	// 文本行
+	textColor := fynetheme.Color(fynetheme.ColorNameForeground)
+	dimmedColor := fadeColor(textColor, dimmedAlpha)
+	for len(r.lines) < len(layout.lines) {
+		r.lines = append(r.lines, canvas.NewText("", color.Black))
+	}
+	r.lines = r.lines[:len(layout.lines)]
+	for i, line := range layout.lines {
+		text := r.lines[i]
+		text.Text = string(st.runes[line.start:line.end])
+		text.TextSize = st.textSize
+		text.Color = textColor
+		if st.dimming && (line.end <= st.activeSpan.Start || line.start >= st.activeSpan.End) {
+			text.Color = dimmedColor
+		}
+		text.Move(fyne.NewPos(line.xs[0], line.y+(layout.lineHeight-layout.textHeight)/2))
+		text.Resize(fyne.NewSize(line.xs[len(line.xs)-1]-line.xs[0], layout.textHeight))
+		text.Refresh()
+	}
+
+	// 获得焦点且没有选区时显示光标
+	if st.focused && start == end {
+		pos := layout.positionOf(st.caret)
+		r.caret.FillColor = fynetheme.Color(fynetheme.ColorNamePrimary)
+		r.caret.Move(pos)
+		r.caret.Resize(fyne.NewSize(1.5, layout.lineHeight))
+		r.caret.Show()
+	} else {
+		r.caret.Hide()
+	}
+	r.caret.Refresh()
+
+	r.objects = r.objects[:0]
+	for _, h := range r.highlights {
+		r.objects = append(r.objects, h)
+	}
+	for _, t := range r.lines {
+		r.objects = append(r.objects, t)
+	}
+	r.objects = append(r.objects, r.caret)
 }
 
 func (r *selectableTextRenderer) Objects() []fyne.CanvasObject {
@@ -194,4 +607,11 @@ func (r *selectableTextRenderer) Objects() []fyne.CanvasObject {
 
 func (r *selectableTextRenderer) Destroy() {
 	// 清理资源
-}
\ No newline at end of file
+}
+
+// fadeColor 按比例降低颜色的不透明度
+func fadeColor(c color.Color, alpha float32) color.Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	n.A = uint8(float32(n.A) * alpha)
+	return n
+}
@@ -1,10 +1,10 @@
 package ui
 
 import (
+	"ai-reader/internal/i18n"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
-	"fmt"
 )
 
 // StatusBar 状态栏
@@ -30,13 +30,13 @@ func NewStatusBar() *StatusBar {
 // initializeComponents 初始化组件
 func (sb *StatusBar) initializeComponents() {
 	// 页面信息
-	sb.pageInfo = widget.NewLabel("第 1 页，共 1 页")
+	sb.pageInfo = widget.NewLabel(pageInfoText(1, 1))
 	
 	// 缩放信息
-	sb.zoomInfo = widget.NewLabel("100%")
+	sb.zoomInfo = widget.NewLabel(i18n.FormatPercent(1))
 	
 	// 文档信息
-	sb.docInfo = widget.NewLabel("无文档")
+	sb.docInfo = widget.NewLabel(i18n.T("status.no_document"))
 	
 	// 进度条
 	sb.progressBar = widget.NewProgressBar()
@@ -44,7 +44,7 @@ func (sb *StatusBar) initializeComponents() {
 	sb.progressBar.Hide()
 	
 	// 状态文本
-	sb.statusText = widget.NewLabel("就绪")
+	sb.statusText = widget.NewLabel(i18n.T("status.ready"))
 }
 
 // setupLayout 设置布局
@@ -79,13 +79,13 @@ func (sb *StatusBar) UpdatePageInfo(pageData interface{}) {
 	if data, ok := pageData.(map[string]interface{}); ok {
 		current := data["current"].(int)
 		total := data["total"].(int)
-		sb.pageInfo.SetText(fmt.Sprintf("第 %d 页，共 %d 页", current, total))
+		sb.pageInfo.SetText(pageInfoText(current, total))
 	}
 }
 
 // UpdateZoomInfo 更新缩放信息
 func (sb *StatusBar) UpdateZoomInfo(zoom float32) {
-	sb.zoomInfo.SetText(fmt.Sprintf("%.0f%%", zoom*100))
+	sb.zoomInfo.SetText(i18n.FormatPercent(float64(zoom)))
 }
 
 // UpdateDocInfo 更新文档信息
@@ -120,4 +120,9 @@ func (sb *StatusBar) HideProgress() {
 // GetContainer 获取容器
 func (sb *StatusBar) GetContainer() *fyne.Container {
 	return sb.container
+}
+
+// pageInfoText 页码信息，如“第 3 页，共 10 页”
+func pageInfoText(current, total int) string {
+	return i18n.N("page.info", total, i18n.Args{"Current": current})
 }
\ No newline at end of file
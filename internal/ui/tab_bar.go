@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"ai-reader/internal/reader"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	fynetheme "fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"path/filepath"
+)
+
+// maxTabTitleLength 标签标题的最大字符数，超出部分以省略号代替
+const maxTabTitleLength = 24
+
+// TabBar 文档标签栏，没有打开文档时隐藏
+type TabBar struct {
+	container *fyne.Container
+	tabs      *fyne.Container
+
+	// OnSelected 点击标签时调用
+	OnSelected func(id int)
+	// OnClosed 点击标签的关闭按钮时调用
+	OnClosed func(id int)
+}
+
+// NewTabBar 创建标签栏
+func NewTabBar() *TabBar {
+	tb := &TabBar{
+		tabs: container.NewHBox(),
+	}
+	tb.container = container.NewBorder(nil, widget.NewSeparator(), nil, nil, container.NewHScroll(tb.tabs))
+	tb.container.Hide()
+	return tb
+}
+
+// Update 按标签页列表重建标签，突出显示活动标签页
+func (tb *TabBar) Update(tabs []reader.TabInfo, active int) {
+	tb.tabs.Objects = nil
+	for _, t := range tabs {
+		id := t.ID
+
+		title := widget.NewButton(tabTitle(t), func() {
+			if tb.OnSelected != nil {
+				tb.OnSelected(id)
+			}
+		})
+		title.Importance = widget.LowImportance
+		if id == active {
+			title.Importance = widget.HighImportance
+		}
+
+		closeBtn := widget.NewButtonWithIcon("", fynetheme.CancelIcon(), func() {
+			if tb.OnClosed != nil {
+				tb.OnClosed(id)
+			}
+		})
+		closeBtn.Importance = widget.LowImportance
+
+		tb.tabs.Add(container.NewHBox(title, closeBtn))
+	}
+
+	if len(tabs) == 0 {
+		tb.container.Hide()
+	} else {
+		tb.container.Show()
+	}
+	tb.tabs.Refresh()
+}
+
+// tabTitle 标签标题，文档没有标题时使用文件名
+func tabTitle(t reader.TabInfo) string {
+	title := filepath.Base(t.Filename)
+	if t.Document != nil && t.Document.GetTitle() != "" {
+		title = t.Document.GetTitle()
+	}
+	if runes := []rune(title); len(runes) > maxTabTitleLength {
+		title = string(runes[:maxTabTitleLength-1]) + "…"
+	}
+	return title
+}
+
+// GetContainer 获取容器
+func (tb *TabBar) GetContainer() *fyne.Container {
+	return tb.container
+}
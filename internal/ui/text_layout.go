@@ -0,0 +1,255 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"unicode"
+)
+
+// lineSpacing 行高相对于字体高度的倍数
+const lineSpacing = 1.4
+
+// textLine 排版后的一行文本
+type textLine struct {
+	start int       // 行首在内容中的rune偏移
+	end   int       // 行尾rune偏移（不含）
+	xs    []float32 // 每个rune左边缘的x坐标，长度为 end-start+1
+	y     float32
+}
+
+// textLayout 按宽度折行后的文本，负责坐标与rune偏移之间的相互映射
+type textLayout struct {
+	runes      []rune
+	lines      []textLine
+	lineHeight float32
+	textHeight float32
+	padding    float32
+}
+
+// layoutText 对文本进行折行排版，英文在空格处折行，CJK字符之间可以任意折行
+func layoutText(runes []rune, width, padding, textHeight float32, measure func(rune) float32) *textLayout {
+	l := &textLayout{
+		runes:      runes,
+		lineHeight: textHeight * lineSpacing,
+		textHeight: textHeight,
+		padding:    padding,
+	}
+
+	available := width - padding*2
+	if available < textHeight {
+		available = textHeight
+	}
+
+	paragraphStart := 0
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && runes[i] != '\n' {
+			continue
+		}
+		l.wrapParagraph(paragraphStart, i, available, measure)
+		paragraphStart = i + 1
+	}
+
+	return l
+}
+
+// wrapParagraph 将一个段落折成若干行
+func (l *textLayout) wrapParagraph(start, end int, available float32, measure func(rune) float32) {
+	lineStart := start
+	lastBreak := -1
+	var x float32
+
+	for i := start; i < end; i++ {
+		r := l.runes[i]
+		if isWideRune(r) && i > lineStart {
+			lastBreak = i
+		}
+
+		w := measure(r)
+		if x+w > available && i > lineStart && r != ' ' {
+			breakAt := i
+			if lastBreak > lineStart {
+				breakAt = lastBreak
+			}
+			l.appendLine(lineStart, breakAt, measure)
+			lineStart = breakAt
+			lastBreak = -1
+			x = 0
+			for j := breakAt; j < i; j++ {
+				x += measure(l.runes[j])
+			}
+		}
+
+		x += w
+		if r == ' ' || r == '\t' || isWideRune(r) {
+			lastBreak = i + 1
+		}
+	}
+
+	l.appendLine(lineStart, end, measure)
+}
+
+// appendLine 追加一行并计算每个rune的x坐标
+func (l *textLayout) appendLine(start, end int, measure func(rune) float32) {
+	xs := make([]float32, end-start+1)
+	x := l.padding
+	xs[0] = x
+	for i := start; i < end; i++ {
+		x += measure(l.runes[i])
+		xs[i-start+1] = x
+	}
+
+	l.lines = append(l.lines, textLine{
+		start: start,
+		end:   end,
+		xs:    xs,
+		y:     l.padding + float32(len(l.lines))*l.lineHeight,
+	})
+}
+
+// size 排版后的总尺寸
+func (l *textLayout) size() fyne.Size {
+	var width float32
+	for _, line := range l.lines {
+		if w := line.xs[len(line.xs)-1]; w > width {
+			width = w
+		}
+	}
+	return fyne.NewSize(width+l.padding, l.padding*2+float32(len(l.lines))*l.lineHeight)
+}
+
+// lineAt 根据y坐标找到所在行
+func (l *textLayout) lineAt(y float32) int {
+	if len(l.lines) == 0 {
+		return -1
+	}
+	idx := int((y - l.padding) / l.lineHeight)
+	if idx < 0 {
+		return 0
+	}
+	if idx >= len(l.lines) {
+		return len(l.lines) - 1
+	}
+	return idx
+}
+
+// lineOf 找到rune偏移所在的行
+func (l *textLayout) lineOf(offset int) int {
+	for i, line := range l.lines {
+		if offset <= line.end {
+			return i
+		}
+	}
+	return len(l.lines) - 1
+}
+
+// offsetAt 将坐标映射为最近的字符边界偏移
+func (l *textLayout) offsetAt(pos fyne.Position) int {
+	if len(l.lines) == 0 {
+		return 0
+	}
+	if pos.Y < l.padding {
+		return 0
+	}
+	if pos.Y >= l.padding+float32(len(l.lines))*l.lineHeight {
+		return len(l.runes)
+	}
+
+	line := l.lines[l.lineAt(pos.Y)]
+	for i := 0; i < len(line.xs)-1; i++ {
+		mid := (line.xs[i] + line.xs[i+1]) / 2
+		if pos.X < mid {
+			return line.start + i
+		}
+	}
+	return line.end
+}
+
+// runeAt 返回坐标下的字符偏移（不取最近边界），坐标不在任何字符上时返回-1
+func (l *textLayout) runeAt(pos fyne.Position) int {
+	if len(l.lines) == 0 || pos.Y < l.padding {
+		return -1
+	}
+	if pos.Y >= l.padding+float32(len(l.lines))*l.lineHeight {
+		return -1
+	}
+
+	line := l.lines[l.lineAt(pos.Y)]
+	for i := 0; i < len(line.xs)-1; i++ {
+		if pos.X < line.xs[i+1] {
+			return line.start + i
+		}
+	}
+	if line.end > line.start {
+		return line.end - 1
+	}
+	return line.start
+}
+
+// positionOf 返回偏移处字符左边缘的坐标
+func (l *textLayout) positionOf(offset int) fyne.Position {
+	if len(l.lines) == 0 {
+		return fyne.NewPos(l.padding, l.padding)
+	}
+	line := l.lines[l.lineOf(offset)]
+	idx := offset - line.start
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(line.xs) {
+		idx = len(line.xs) - 1
+	}
+	return fyne.NewPos(line.xs[idx], line.y)
+}
+
+// selectionRect 选择高亮的矩形
+type selectionRect struct {
+	pos  fyne.Position
+	size fyne.Size
+}
+
+// selectionRects 计算选择区间 [start,end) 在各行上的高亮矩形，跨行时跟随文本流
+func (l *textLayout) selectionRects(start, end int) []selectionRect {
+	if start >= end {
+		return nil
+	}
+
+	var rects []selectionRect
+	for i, line := range l.lines {
+		if end <= line.start || start > line.end {
+			continue
+		}
+		from := start
+		if from < line.start {
+			from = line.start
+		}
+		to := end
+		if to > line.end {
+			to = line.end
+		}
+
+		x1 := line.xs[from-line.start]
+		x2 := line.xs[to-line.start]
+		// 选择跨越行尾时，为换行位置留出一点宽度
+		if end > line.end && i < len(l.lines)-1 {
+			x2 += l.textHeight / 3
+		}
+		if x2 <= x1 {
+			continue
+		}
+
+		rects = append(rects, selectionRect{
+			pos:  fyne.NewPos(x1, line.y),
+			size: fyne.NewSize(x2-x1, l.lineHeight),
+		})
+	}
+	return rects
+}
+
+// isWideRune 判断是否为可在任意位置折行的CJK字符
+func isWideRune(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r) ||
+		(r >= 0x3000 && r <= 0x303F) || // CJK标点
+		(r >= 0xFF00 && r <= 0xFFEF) // 全角字符
+}
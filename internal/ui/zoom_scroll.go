@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// zoomScroll 垂直滚动容器，按住Ctrl滚动滚轮时改为缩放
+type zoomScroll struct {
+	container.Scroll
+
+	// OnZoom 滚轮缩放回调，steps为正表示放大
+	OnZoom func(steps int)
+}
+
+// newZoomScroll 创建支持Ctrl+滚轮缩放的垂直滚动容器
+func newZoomScroll(content fyne.CanvasObject) *zoomScroll {
+	s := &zoomScroll{}
+	s.Content = content
+	s.Direction = container.ScrollVerticalOnly
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// Scrolled 处理滚轮事件
+func (s *zoomScroll) Scrolled(ev *fyne.ScrollEvent) {
+	if s.OnZoom == nil || !ctrlPressed() {
+		s.Scroll.Scrolled(ev)
+		return
+	}
+
+	switch {
+	case ev.Scrolled.DY > 0:
+		s.OnZoom(1)
+	case ev.Scrolled.DY < 0:
+		s.OnZoom(-1)
+	}
+}
+
+// ctrlPressed 检查当前是否按住了Ctrl（macOS上为Command）
+func ctrlPressed() bool {
+	drv, ok := fyne.CurrentApp().Driver().(desktop.Driver)
+	if !ok {
+		return false
+	}
+	return drv.CurrentKeyModifiers()&fyne.KeyModifierShortcutDefault != 0
+}
@@ -0,0 +1,175 @@
+package align
+
+import (
+	"ai-reader/pkg/document"
+	"ai-reader/pkg/segment"
+	"sort"
+	"strings"
+)
+
+// Mode 两个文档之间的对齐方式
+type Mode string
+
+const (
+	ByPercent   Mode = "percent"   // 按全文位置的比例
+	ByOutline   Mode = "outline"   // 按目录条目的顺序，章节内按比例
+	ByParagraph Mode = "paragraph" // 按段落序号，段落内按比例
+)
+
+// Modes 所有对齐方式
+func Modes() []Mode {
+	return []Mode{ByPercent, ByOutline, ByParagraph}
+}
+
+// Position 文档中的位置：页码和页内rune偏移
+type Position struct {
+	Page   int
+	Offset int
+}
+
+// Index 文档的位置索引，页面、段落和章节的起点都以全文rune偏移表示
+type Index struct {
+	text            []rune
+	pageStarts      []int // 每页的起点，最后一项为全文长度
+	paragraphs      []segment.Span
+	paragraphStarts []int
+	chapters        []int
+}
+
+// NewIndex 为文档建立位置索引，全文由各页内容拼接而成
+func NewIndex(doc document.Document) *Index {
+	ix := &Index{}
+	for page := 1; page <= doc.GetPages(); page++ {
+		content, _ := doc.GetPage(page)
+		ix.pageStarts = append(ix.pageStarts, len(ix.text))
+		ix.text = append(ix.text, []rune(content)...)
+	}
+	ix.pageStarts = append(ix.pageStarts, len(ix.text))
+
+	ix.paragraphs = segment.Paragraphs(ix.text)
+	for _, span := range ix.paragraphs {
+		ix.paragraphStarts = append(ix.paragraphStarts, span.Start)
+	}
+	for _, entry := range document.ExtractOutline(doc) {
+		ix.chapters = append(ix.chapters, ix.Global(Position{Page: entry.Page, Offset: entry.Offset}))
+	}
+	return ix
+}
+
+// Len 全文长度
+func (ix *Index) Len() int {
+	return len(ix.text)
+}
+
+// Global 把页内位置换算为全文偏移
+func (ix *Index) Global(pos Position) int {
+	pages := len(ix.pageStarts) - 1
+	if pages <= 0 {
+		return 0
+	}
+	page := clamp(pos.Page, 1, pages)
+	start, end := ix.pageStarts[page-1], ix.pageStarts[page]
+	return clamp(start+pos.Offset, start, end)
+}
+
+// Local 把全文偏移换算为页内位置
+func (ix *Index) Local(offset int) Position {
+	pages := len(ix.pageStarts) - 1
+	if pages <= 0 {
+		return Position{Page: 1}
+	}
+	offset = clamp(offset, 0, ix.Len())
+	// 第一个终点大于偏移的页，偏移等于全文长度时落在最后一页
+	page := sort.Search(pages, func(i int) bool { return ix.pageStarts[i+1] > offset }) + 1
+	if page > pages {
+		page = pages
+	}
+	return Position{Page: page, Offset: offset - ix.pageStarts[page-1]}
+}
+
+// Map 把源文档中的位置换算为目标文档中对齐的位置
+func Map(src, dst *Index, pos Position, mode Mode) Position {
+	return dst.Local(mapOffset(src, dst, src.Global(pos), mode))
+}
+
+// Passage 获取源文档区间 [start,end) 在目标文档中对齐的文字，扩展到完整段落
+func Passage(src, dst *Index, start, end Position, mode Mode) string {
+	from := mapOffset(src, dst, src.Global(start), mode)
+	to := mapOffset(src, dst, src.Global(end), mode)
+	if to < from {
+		from, to = to, from
+	}
+
+	from = dst.paragraphAt(from).Start
+	if to > from {
+		to = dst.paragraphAt(to - 1).End
+	}
+	if to <= from {
+		to = dst.paragraphAt(from).End
+	}
+	return strings.TrimSpace(string(dst.text[from:to]))
+}
+
+// paragraphAt 偏移所在的段落，与 segment.ParagraphAt 的规则一致
+func (ix *Index) paragraphAt(offset int) segment.Span {
+	if len(ix.paragraphs) == 0 {
+		return segment.Span{Start: offset, End: offset}
+	}
+	i := sort.Search(len(ix.paragraphs), func(i int) bool { return offset <= ix.paragraphs[i].End })
+	if i == len(ix.paragraphs) {
+		i--
+	}
+	return ix.paragraphs[i]
+}
+
+// mapOffset 按对齐方式的锚点做分段线性插值
+func mapOffset(src, dst *Index, offset int, mode Mode) int {
+	var from, to []int
+	switch mode {
+	case ByOutline:
+		from, to = anchors(src.chapters, dst.chapters)
+	case ByParagraph:
+		from, to = anchors(src.paragraphStarts, dst.paragraphStarts)
+	}
+	// 两端总是对齐，没有可用的锚点时即为按比例对齐
+	from = append(append([]int{0}, from...), src.Len())
+	to = append(append([]int{0}, to...), dst.Len())
+	return interpolate(offset, from, to)
+}
+
+// anchors 按序号配对两个文档的锚点，多出的锚点被忽略
+func anchors(src, dst []int) ([]int, []int) {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+	return src[:n], dst[:n]
+}
+
+// interpolate 在锚点之间做线性插值，from和to一一对应且都不递减
+func interpolate(offset int, from, to []int) int {
+	// 最后一个不大于偏移的锚点
+	i := sort.Search(len(from), func(i int) bool { return from[i] > offset }) - 1
+	if i < 0 {
+		return to[0]
+	}
+	if i >= len(from)-1 {
+		return to[len(to)-1]
+	}
+
+	span := from[i+1] - from[i]
+	if span <= 0 {
+		return to[i]
+	}
+	return to[i] + (offset-from[i])*(to[i+1]-to[i])/span
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,45 @@
+package animation
+
+import "math"
+
+// EasingFunc 缓动函数，将线性进度 0-1 映射为动画进度
+type EasingFunc func(t float32) float32
+
+// Linear 线性
+func Linear(t float32) float32 {
+	return t
+}
+
+// EaseIn 三次缓入
+func EaseIn(t float32) float32 {
+	return t * t * t
+}
+
+// EaseOut 三次缓出
+func EaseOut(t float32) float32 {
+	inv := 1 - t
+	return 1 - inv*inv*inv
+}
+
+// EaseInOut 三次缓入缓出
+func EaseInOut(t float32) float32 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	return 1 - float32(math.Pow(float64(-2*t+2), 3))/2
+}
+
+var easings = map[string]EasingFunc{
+	"linear":      Linear,
+	"ease-in":     EaseIn,
+	"ease-out":    EaseOut,
+	"ease-in-out": EaseInOut,
+}
+
+// GetEasing 按名称获取缓动函数，未知名称返回线性
+func GetEasing(name string) EasingFunc {
+	if easing, exists := easings[name]; exists {
+		return easing
+	}
+	return Linear
+}
@@ -0,0 +1,162 @@
+package animation
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Layer 动画帧中一个页面图层的状态
+type Layer struct {
+	OffsetX float32 // 水平偏移，相对页面宽度的比例
+	ScaleX  float32 // 水平缩放 0-1，以页面中心为轴
+	Alpha   float32 // 不透明度 0-1
+	Visible bool
+}
+
+// Frame 动画某一时刻的画面状态
+type Frame struct {
+	Old      Layer
+	New      Layer
+	NewOnTop bool
+
+	// Strips 新页面按竖条分别显示时每条的进度 0-1，为空表示整体显示
+	Strips []float32
+}
+
+// Transition 翻页过渡效果
+type Transition interface {
+	// GetName 获取过渡效果名称
+	GetName() string
+
+	// Frame 计算进度 progress (0-1，已应用缓动) 时的画面，forward 表示向后翻页
+	Frame(progress float32, forward bool) Frame
+}
+
+var (
+	transitions = make(map[string]Transition)
+	mu          sync.RWMutex
+)
+
+func init() {
+	Register(&noneTransition{})
+	Register(&fadeTransition{})
+	Register(&slideTransition{})
+	Register(&flipTransition{})
+	Register(&waveTransition{strips: 12})
+}
+
+// Register 注册过渡效果
+func Register(transition Transition) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	transitions[transition.GetName()] = transition
+}
+
+// Get 获取过渡效果，不存在时返回nil
+func Get(name string) Transition {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return transitions[name]
+}
+
+// Names 获取所有过渡效果名称
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(transitions))
+	for name := range transitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func fullLayer() Layer {
+	return Layer{ScaleX: 1, Alpha: 1, Visible: true}
+}
+
+// noneTransition 无动画，直接显示新页面
+type noneTransition struct{}
+
+func (t *noneTransition) GetName() string { return "none" }
+
+func (t *noneTransition) Frame(progress float32, forward bool) Frame {
+	return Frame{New: fullLayer(), NewOnTop: true}
+}
+
+// fadeTransition 淡入淡出
+type fadeTransition struct{}
+
+func (t *fadeTransition) GetName() string { return "fade" }
+
+func (t *fadeTransition) Frame(progress float32, forward bool) Frame {
+	old := fullLayer()
+	old.Alpha = 1 - progress
+	next := fullLayer()
+	next.Alpha = progress
+	return Frame{Old: old, New: next, NewOnTop: true}
+}
+
+// slideTransition 滑动翻页，新页面从翻页方向推入
+type slideTransition struct{}
+
+func (t *slideTransition) GetName() string { return "slide" }
+
+func (t *slideTransition) Frame(progress float32, forward bool) Frame {
+	direction := float32(1)
+	if !forward {
+		direction = -1
+	}
+	old := fullLayer()
+	old.OffsetX = -progress * direction
+	next := fullLayer()
+	next.OffsetX = (1 - progress) * direction
+	return Frame{Old: old, New: next, NewOnTop: true}
+}
+
+// flipTransition 翻转：旧页面收窄到中轴后，新页面从中轴展开
+type flipTransition struct{}
+
+func (t *flipTransition) GetName() string { return "flip" }
+
+func (t *flipTransition) Frame(progress float32, forward bool) Frame {
+	if progress < 0.5 {
+		old := fullLayer()
+		old.ScaleX = 1 - progress*2
+		// 收窄时略微变暗，模拟纸张转向
+		old.Alpha = 1 - progress
+		return Frame{Old: old, NewOnTop: false}
+	}
+	next := fullLayer()
+	next.ScaleX = progress*2 - 1
+	next.Alpha = progress
+	return Frame{New: next, NewOnTop: true}
+}
+
+// waveTransition 波浪：新页面按竖条依次显现，从翻页方向一侧开始
+type waveTransition struct {
+	strips int
+}
+
+func (t *waveTransition) GetName() string { return "wave" }
+
+func (t *waveTransition) Frame(progress float32, forward bool) Frame {
+	const spread = 0.6 // 首尾竖条开始时间的间隔，占总时长的比例
+
+	strips := make([]float32, t.strips)
+	for i := range strips {
+		order := i
+		if forward {
+			order = t.strips - 1 - i
+		}
+		delay := spread * float32(order) / float32(t.strips-1)
+		p := (progress - delay) / (1 - spread)
+		strips[i] = float32(math.Max(0, math.Min(1, float64(p))))
+	}
+
+	return Frame{Old: fullLayer(), New: fullLayer(), NewOnTop: true, Strips: strips}
+}
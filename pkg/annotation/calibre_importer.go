@@ -0,0 +1,78 @@
+package annotation
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// calibreExport Calibre阅读器导出的标注文件结构
+type calibreExport struct {
+	Type       string             `json:"type"`
+	Version    int                `json:"version"`
+	Highlights []calibreHighlight `json:"highlights"`
+}
+
+type calibreHighlight struct {
+	Type            string   `json:"type"`
+	HighlightedText string   `json:"highlighted_text"`
+	Notes           string   `json:"notes"`
+	Timestamp       string   `json:"timestamp"`
+	TocFamilyTitles []string `json:"toc_family_titles"`
+	SpineIndex      int      `json:"spine_index"`
+	Removed         bool     `json:"removed"`
+}
+
+// CalibreImporter Calibre标注导出（.calibre_highlights）导入器
+type CalibreImporter struct{}
+
+func NewCalibreImporter() *CalibreImporter {
+	return &CalibreImporter{}
+}
+
+func (i *CalibreImporter) GetName() string {
+	return "calibre"
+}
+
+func (i *CalibreImporter) CanHandle(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".calibre_highlights" || ext == ".json"
+}
+
+func (i *CalibreImporter) ImportFromReader(reader io.Reader, filename string) ([]Clipping, error) {
+	var export calibreExport
+	if err := json.NewDecoder(reader).Decode(&export); err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if export.Type != "calibre_highlights" {
+		return nil, ErrUnsupportedFormat
+	}
+
+	// Calibre导出文件不包含书名，按惯例使用文件名作为书名
+	title := filepath.Base(filename)
+	title = strings.TrimSuffix(title, filepath.Ext(title))
+
+	clippings := make([]Clipping, 0, len(export.Highlights))
+	for _, h := range export.Highlights {
+		if h.Removed || (h.Type != "" && h.Type != "highlight") {
+			continue
+		}
+
+		chapter := ""
+		if n := len(h.TocFamilyTitles); n > 0 {
+			chapter = h.TocFamilyTitles[n-1]
+		}
+
+		clippings = append(clippings, Clipping{
+			Title:   title,
+			Text:    strings.TrimSpace(h.HighlightedText),
+			Note:    strings.TrimSpace(h.Notes),
+			Chapter: chapter,
+			AddedAt: h.Timestamp,
+			Source:  "calibre",
+		})
+	}
+
+	return clippings, nil
+}
@@ -0,0 +1,17 @@
+package annotation
+
+import "errors"
+
+var (
+	// ErrUnsupportedFormat 不支持的摘录格式
+	ErrUnsupportedFormat = errors.New("unsupported clipping format")
+
+	// ErrNoDocumentMatch 书库中没有匹配的文档
+	ErrNoDocumentMatch = errors.New("no matching document in library")
+
+	// ErrTextNotFound 在文档中找不到摘录文本
+	ErrTextNotFound = errors.New("clipping text not found in document")
+
+	// ErrEmptyClipping 摘录没有文本内容
+	ErrEmptyClipping = errors.New("clipping has no text")
+)
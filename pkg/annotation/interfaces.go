@@ -0,0 +1,83 @@
+package annotation
+
+import (
+	"ai-reader/pkg/document"
+	"io"
+)
+
+// Highlight 文档内的高亮标注
+type Highlight struct {
+	ID           string `json:"id"`
+	DocumentPath string `json:"document_path"`
+	Start        int    `json:"start"` // 在文档全文中的起始rune偏移
+	End          int    `json:"end"`   // 在文档全文中的结束rune偏移（不含）
+	PageNumber   int    `json:"page_number"`
+	Text         string `json:"text"`
+	Note         string `json:"note,omitempty"`
+	CreatedAt    string `json:"created_at,omitempty"`
+	Source       string `json:"source,omitempty"` // 来源，如 "kindle"、"calibre"
+}
+
+// Clipping 从外部阅读器导出的摘录
+type Clipping struct {
+	Title    string
+	Author   string
+	Text     string
+	Note     string
+	Page     int
+	Location string
+	Chapter  string
+	AddedAt  string
+	Source   string
+}
+
+// LibraryEntry 书库中的文档
+type LibraryEntry struct {
+	Path     string
+	Document document.Document
+}
+
+// ImportReport 导入结果报告
+type ImportReport struct {
+	Source     string
+	Total      int
+	Imported   []Highlight
+	Duplicates int
+	Unmatched  []UnmatchedClipping
+}
+
+// UnmatchedClipping 未能匹配的摘录及原因
+type UnmatchedClipping struct {
+	Clipping Clipping
+	Reason   error
+}
+
+// ClippingImporter 摘录导入器接口
+type ClippingImporter interface {
+	// GetName 获取导入器名称
+	GetName() string
+
+	// CanHandle 检查是否能处理指定文件
+	CanHandle(filename string) bool
+
+	// ImportFromReader 从Reader解析摘录
+	ImportFromReader(reader io.Reader, filename string) ([]Clipping, error)
+}
+
+// HighlightStore 高亮存储接口
+type HighlightStore interface {
+	// Add 添加高亮，已存在时返回false
+	Add(highlight Highlight) bool
+
+	// GetHighlights 获取指定文档的所有高亮
+	GetHighlights(documentPath string) []Highlight
+
+	// Remove 删除高亮
+	Remove(id string)
+
+	// Save 保存到磁盘
+	Save() error
+
+	// Load 从磁盘加载
+	Load() error
+}
@@ -0,0 +1,249 @@
+package annotation
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const kindleSeparator = "=========="
+
+var (
+	kindlePageRe     = regexp.MustCompile(`(?i)(?:page|第)\s*(\d+)`)
+	kindleLocationRe = regexp.MustCompile(`(?i)(?:location|loc\.|位置)\s*#?\s*(\d+)(?:\s*-\s*(\d+))?`)
+	kindleAddedRe    = regexp.MustCompile(`(?i)(?:added on|添加于)\s*(.+)$`)
+)
+
+// kindleEntryKind Kindle摘录条目类型
+type kindleEntryKind int
+
+const (
+	kindleHighlight kindleEntryKind = iota
+	kindleNote
+	kindleBookmark
+)
+
+// kindleEntry 解析后的条目，笔记的内容在Note中
+type kindleEntry struct {
+	kind     kindleEntryKind
+	clipping Clipping
+}
+
+// KindleImporter Kindle "My Clippings.txt" 导入器
+type KindleImporter struct{}
+
+func NewKindleImporter() *KindleImporter {
+	return &KindleImporter{}
+}
+
+func (i *KindleImporter) GetName() string {
+	return "kindle"
+}
+
+func (i *KindleImporter) CanHandle(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".txt"
+}
+
+func (i *KindleImporter) ImportFromReader(reader io.Reader, filename string) ([]Clipping, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var entries []kindleEntry
+	var block []string
+	sawSeparator := false
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(strings.TrimPrefix(line, "\ufeff")) == kindleSeparator {
+			sawSeparator = true
+			entries = i.appendEntry(entries, block)
+			block = block[:0]
+			continue
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// 最后一条可能没有分隔符
+	entries = i.appendEntry(entries, block)
+
+	if !sawSeparator {
+		return nil, ErrUnsupportedFormat
+	}
+	return attachKindleNotes(entries), nil
+}
+
+// appendEntry 解析单个条目并追加，忽略书签和没有文字的标注
+func (i *KindleImporter) appendEntry(entries []kindleEntry, block []string) []kindleEntry {
+	// 去掉首尾空行
+	for len(block) > 0 && strings.TrimSpace(strings.TrimPrefix(block[0], "\ufeff")) == "" {
+		block = block[1:]
+	}
+	if len(block) < 2 {
+		return entries
+	}
+
+	title, author := parseKindleTitle(strings.TrimPrefix(block[0], "\ufeff"))
+	meta := block[1]
+	kind := kindleEntryKindOf(meta)
+	if kind == kindleBookmark {
+		return entries
+	}
+
+	text := strings.TrimSpace(strings.Join(block[2:], "\n"))
+	page, location := parseKindleMeta(meta)
+	added := ""
+	if m := kindleAddedRe.FindStringSubmatch(meta); m != nil {
+		added = strings.TrimSpace(m[1])
+	}
+
+	clipping := Clipping{
+		Title:    title,
+		Author:   author,
+		Page:     page,
+		Location: location,
+		AddedAt:  added,
+		Source:   "kindle",
+	}
+	switch {
+	case kind == kindleNote:
+		clipping.Note = text
+	case text == "":
+		return entries
+	default:
+		clipping.Text = text
+	}
+	return append(entries, kindleEntry{kind: kind, clipping: clipping})
+}
+
+// attachKindleNotes 把笔记合并到对应的标注上。Kindle把笔记作为单独条目导出，
+// 位置落在所属标注的范围内，但可能在标注之前或之后；找不到标注的笔记作为单独的摘录保留
+func attachKindleNotes(entries []kindleEntry) []Clipping {
+	attached := make([]bool, len(entries))
+	for idx, entry := range entries {
+		if entry.kind != kindleNote {
+			continue
+		}
+		if target := kindleNoteTarget(entries, idx); target >= 0 {
+			entries[target].clipping.Note = entry.clipping.Note
+			attached[idx] = true
+		}
+	}
+
+	clippings := make([]Clipping, 0, len(entries))
+	for idx, entry := range entries {
+		if !attached[idx] {
+			clippings = append(clippings, entry.clipping)
+		}
+	}
+	return clippings
+}
+
+// kindleNoteTarget 查找笔记所属的标注：同一本书中还没有笔记、位置范围包含笔记位置的标注，
+// 先找前面最近的，再找后面最近的。找不到时返回-1
+func kindleNoteTarget(entries []kindleEntry, note int) int {
+	matches := func(idx int) bool {
+		c := entries[idx].clipping
+		return entries[idx].kind == kindleHighlight && c.Title == entries[note].clipping.Title &&
+			c.Note == "" && locationContains(c.Location, entries[note].clipping.Location)
+	}
+	for idx := note - 1; idx >= 0; idx-- {
+		if matches(idx) {
+			return idx
+		}
+	}
+	for idx := note + 1; idx < len(entries); idx++ {
+		if matches(idx) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// parseKindleTitle 解析 "书名 (作者)" 格式的标题行
+func parseKindleTitle(line string) (title, author string) {
+	line = strings.TrimSpace(line)
+	if strings.HasSuffix(line, ")") {
+		depth := 0
+		for idx := len(line) - 1; idx >= 0; idx-- {
+			switch line[idx] {
+			case ')':
+				depth++
+			case '(':
+				depth--
+				if depth == 0 {
+					return strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1 : len(line)-1])
+				}
+			}
+		}
+	}
+	return line, ""
+}
+
+// kindleEntryKindOf 根据元数据行判断条目类型
+func kindleEntryKindOf(meta string) kindleEntryKind {
+	lower := strings.ToLower(meta)
+	switch {
+	case strings.Contains(lower, "bookmark") || strings.Contains(meta, "书签"):
+		return kindleBookmark
+	case strings.Contains(lower, "note") || strings.Contains(meta, "笔记"):
+		return kindleNote
+	default:
+		return kindleHighlight
+	}
+}
+
+// parseKindleMeta 解析元数据行中的页码和位置
+func parseKindleMeta(meta string) (page int, location string) {
+	// 位置信息中也有数字，先去掉位置部分再找页码
+	locMatch := kindleLocationRe.FindStringSubmatch(meta)
+	if locMatch != nil {
+		location = locMatch[1]
+		if locMatch[2] != "" {
+			location += "-" + locMatch[2]
+		}
+	}
+
+	rest := kindleLocationRe.ReplaceAllString(meta, "")
+	if m := kindlePageRe.FindStringSubmatch(rest); m != nil {
+		page, _ = strconv.Atoi(m[1])
+	}
+	return page, location
+}
+
+// locationContains 检查位置范围是否包含另一个位置
+func locationContains(rangeLoc, loc string) bool {
+	if rangeLoc == "" || loc == "" {
+		return false
+	}
+	start, end := parseLocationRange(rangeLoc)
+	pos, _ := parseLocationRange(loc)
+	return pos >= start && pos <= end
+}
+
+func parseLocationRange(loc string) (start, end int) {
+	parts := strings.SplitN(loc, "-", 2)
+	start, _ = strconv.Atoi(parts[0])
+	end = start
+	if len(parts) == 2 {
+		end, _ = strconv.Atoi(parts[1])
+		// Kindle会省略结束位置的公共前缀，如 "1234-56"
+		if end < start {
+			digits := len(parts[1])
+			base := start
+			for d := 0; d < digits; d++ {
+				base /= 10
+			}
+			for d := 0; d < digits; d++ {
+				base *= 10
+			}
+			end += base
+		}
+	}
+	return start, end
+}
@@ -0,0 +1,80 @@
+package annotation
+
+import (
+	"strings"
+	"testing"
+)
+
+// kindleClipping 按 My Clippings.txt 的格式写出一条摘录
+func kindleClipping(title, meta, text string) string {
+	return title + "\n- " + meta + "\n\n" + text + "\n==========\n"
+}
+
+func TestKindleNotesAttachInAnyOrder(t *testing.T) {
+	highlight := kindleClipping("Tides (R. Carson)", "Your Highlight on page 12 | Location 180-184 | Added on Monday, 3 June 2024 10:00:00", "The moon pulls the sea.")
+	other := kindleClipping("Tides (R. Carson)", "Your Highlight on page 30 | Location 400-402 | Added on Monday, 3 June 2024 10:05:00", "Waves break on the shore.")
+	note := kindleClipping("Tides (R. Carson)", "Your Note on page 12 | Location 184 | Added on Monday, 3 June 2024 10:01:00", "Gravity!")
+	stray := kindleClipping("Tides (R. Carson)", "Your Note on page 50 | Location 900 | Added on Monday, 3 June 2024 10:09:00", "No highlight here")
+	otherBook := kindleClipping("Another Book", "Your Highlight on page 12 | Location 180-184 | Added on Monday, 3 June 2024 10:00:00", "Same location, other book.")
+	bookmark := kindleClipping("Tides (R. Carson)", "Your Bookmark on page 13 | Location 190", "")
+
+	tests := []struct {
+		name  string
+		input string
+		want  []Clipping // 只比较书名、文字和笔记
+	}{
+		{
+			name:  "note after highlight",
+			input: highlight + note + other,
+			want:  []Clipping{{Title: "Tides", Text: "The moon pulls the sea.", Note: "Gravity!"}, {Title: "Tides", Text: "Waves break on the shore."}},
+		},
+		{
+			name:  "note before highlight",
+			input: note + other + highlight,
+			want:  []Clipping{{Title: "Tides", Text: "Waves break on the shore."}, {Title: "Tides", Text: "The moon pulls the sea.", Note: "Gravity!"}},
+		},
+		{
+			name:  "note of another book",
+			input: otherBook + note + highlight,
+			want:  []Clipping{{Title: "Another Book", Text: "Same location, other book."}, {Title: "Tides", Text: "The moon pulls the sea.", Note: "Gravity!"}},
+		},
+		{
+			name:  "note without highlight",
+			input: stray + highlight + bookmark,
+			want:  []Clipping{{Title: "Tides", Note: "No highlight here"}, {Title: "Tides", Text: "The moon pulls the sea."}},
+		},
+	}
+	for _, tt := range tests {
+		clippings, err := NewKindleImporter().ImportFromReader(strings.NewReader(tt.input), "My Clippings.txt")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(clippings) != len(tt.want) {
+			t.Fatalf("%s: %d clippings, want %d: %+v", tt.name, len(clippings), len(tt.want), clippings)
+		}
+		for idx, want := range tt.want {
+			got := clippings[idx]
+			if got.Title != want.Title || got.Text != want.Text || got.Note != want.Note {
+				t.Errorf("%s: clipping %d = %q %q %q, want %q %q %q", tt.name, idx, got.Title, got.Text, got.Note, want.Title, want.Text, want.Note)
+			}
+		}
+	}
+}
+
+func TestKindleMeta(t *testing.T) {
+	clippings, err := NewKindleImporter().ImportFromReader(strings.NewReader(
+		"\ufeffTides (R. Carson)\r\n- Your Highlight on page 12 | Location 1234-56 | Added on Monday, 3 June 2024 10:00:00\r\n\r\nThe moon pulls the sea.\r\n==========\r\n"), "My Clippings.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clippings) != 1 {
+		t.Fatalf("%d clippings, want 1", len(clippings))
+	}
+	c := clippings[0]
+	if c.Title != "Tides" || c.Author != "R. Carson" || c.Page != 12 || c.Location != "1234-56" || c.AddedAt != "Monday, 3 June 2024 10:00:00" {
+		t.Fatalf("clipping = %+v", c)
+	}
+	if !locationContains(c.Location, "1250") || locationContains(c.Location, "1257") {
+		t.Fatal("abbreviated location range 1234-56 should cover 1234 to 1256")
+	}
+}
@@ -0,0 +1,120 @@
+package annotation
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// koreaderBook KOReader导出插件生成的单本书结构
+type koreaderBook struct {
+	Title   string          `json:"title"`
+	Author  string          `json:"author"`
+	File    string          `json:"file"`
+	Entries []koreaderEntry `json:"entries"`
+}
+
+type koreaderEntry struct {
+	Sort    string `json:"sort"`
+	Chapter string `json:"chapter"`
+	Text    string `json:"text"`
+	Note    string `json:"note"`
+	Page    int    `json:"page"`
+	Time    int64  `json:"time"`
+}
+
+// KOReaderImporter KOReader JSON导出导入器
+type KOReaderImporter struct{}
+
+func NewKOReaderImporter() *KOReaderImporter {
+	return &KOReaderImporter{}
+}
+
+func (i *KOReaderImporter) GetName() string {
+	return "koreader"
+}
+
+func (i *KOReaderImporter) CanHandle(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".json"
+}
+
+func (i *KOReaderImporter) ImportFromReader(reader io.Reader, filename string) ([]Clipping, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	books, err := decodeKOReaderBooks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var clippings []Clipping
+	for _, book := range books {
+		title := book.Title
+		if title == "" && book.File != "" {
+			title = filepath.Base(book.File)
+			title = strings.TrimSuffix(title, filepath.Ext(title))
+		}
+
+		for _, entry := range book.Entries {
+			if entry.Sort != "" && entry.Sort != "highlight" {
+				continue
+			}
+
+			added := ""
+			if entry.Time > 0 {
+				added = time.Unix(entry.Time, 0).Format("2006-01-02 15:04:05")
+			}
+
+			clippings = append(clippings, Clipping{
+				Title:   title,
+				Author:  book.Author,
+				Text:    strings.TrimSpace(entry.Text),
+				Note:    strings.TrimSpace(entry.Note),
+				Page:    entry.Page,
+				Chapter: entry.Chapter,
+				AddedAt: added,
+				Source:  "koreader",
+			})
+		}
+	}
+
+	return clippings, nil
+}
+
+// decodeKOReaderBooks 兼容单本书、{"documents": [...]} 以及数组三种导出形式
+func decodeKOReaderBooks(data []byte) ([]koreaderBook, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, ErrUnsupportedFormat
+	}
+
+	if data[0] == '[' {
+		var books []koreaderBook
+		if err := json.Unmarshal(data, &books); err != nil {
+			return nil, ErrUnsupportedFormat
+		}
+		return books, nil
+	}
+
+	var wrapper struct {
+		koreaderBook
+		Documents []koreaderBook `json:"documents"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	switch {
+	case len(wrapper.Documents) > 0:
+		return wrapper.Documents, nil
+	case wrapper.Entries != nil:
+		return []koreaderBook{wrapper.koreaderBook}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
@@ -0,0 +1,100 @@
+package annotation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Manager 摘录导入管理器
+type Manager struct {
+	importers []ClippingImporter
+	mu        sync.RWMutex
+}
+
+// NewManager 创建导入管理器
+func NewManager() *Manager {
+	manager := &Manager{
+		importers: make([]ClippingImporter, 0),
+	}
+
+	// 注册默认导入器
+	manager.RegisterImporter(NewKindleImporter())
+	manager.RegisterImporter(NewCalibreImporter())
+	manager.RegisterImporter(NewKOReaderImporter())
+
+	return manager
+}
+
+func (m *Manager) RegisterImporter(importer ClippingImporter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.importers = append(m.importers, importer)
+}
+
+// ParseFile 用第一个能识别该文件的导入器解析摘录
+func (m *Manager) ParseFile(filename string) ([]Clipping, string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, importer := range m.importers {
+		if !importer.CanHandle(filename) {
+			continue
+		}
+
+		file, err := os.Open(filename)
+		if err != nil {
+			return nil, "", err
+		}
+		clippings, err := importer.ImportFromReader(file, filename)
+		file.Close()
+
+		if errors.Is(err, ErrUnsupportedFormat) {
+			// 同一扩展名可能对应多种格式，继续尝试下一个导入器
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return clippings, importer.GetName(), nil
+	}
+
+	return nil, "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, filepath.Base(filename))
+}
+
+// Import 解析摘录文件，匹配到书库文档并写入高亮存储
+func (m *Manager) Import(filename string, library []LibraryEntry, store HighlightStore) (*ImportReport, error) {
+	clippings, source, err := m.ParseFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		Source: source,
+		Total:  len(clippings),
+	}
+
+	matcher := NewMatcher(library)
+	for _, clipping := range clippings {
+		highlight, err := matcher.Match(clipping)
+		if err != nil {
+			report.Unmatched = append(report.Unmatched, UnmatchedClipping{
+				Clipping: clipping,
+				Reason:   err,
+			})
+			continue
+		}
+
+		highlight.ID = HighlightID(highlight.DocumentPath, highlight.Start, highlight.End)
+		if store.Add(highlight) {
+			report.Imported = append(report.Imported, highlight)
+		} else {
+			report.Duplicates++
+		}
+	}
+
+	return report, store.Save()
+}
@@ -0,0 +1,392 @@
+package annotation
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// titleMatchThreshold 书名相似度阈值
+	titleMatchThreshold = 0.6
+
+	// textMatchThreshold 模糊匹配文本的相似度阈值
+	textMatchThreshold = 0.8
+
+	// anchorLength 模糊匹配时用作锚点的rune数
+	anchorLength = 12
+
+	// minContentOnlyLength 书名不匹配时，仅凭文本匹配所需的最短长度
+	minContentOnlyLength = 20
+)
+
+// Matcher 将摘录匹配到书库文档中的具体位置
+type Matcher struct {
+	entries []matcherEntry
+}
+
+type matcherEntry struct {
+	LibraryEntry
+	title   string
+	author  string
+	content *normalizedText
+	pages   []int // 每页起始rune偏移
+}
+
+// NewMatcher 创建匹配器
+func NewMatcher(library []LibraryEntry) *Matcher {
+	m := &Matcher{}
+	for _, entry := range library {
+		metadata := entry.Document.GetMetadata()
+		title := metadata.Title
+		if title == "" {
+			title = entry.Document.GetTitle()
+		}
+		m.entries = append(m.entries, matcherEntry{
+			LibraryEntry: entry,
+			title:        normalizeTitle(title),
+			author:       normalizeTitle(metadata.Author),
+		})
+	}
+	return m
+}
+
+// Match 匹配摘录，返回对应的高亮
+func (m *Matcher) Match(clipping Clipping) (Highlight, error) {
+	if strings.TrimSpace(clipping.Text) == "" {
+		return Highlight{}, ErrEmptyClipping
+	}
+
+	candidates := m.rankByTitle(clipping)
+	for _, idx := range candidates {
+		if h, ok := m.locate(idx, clipping); ok {
+			return h, nil
+		}
+	}
+
+	if len(candidates) > 0 {
+		return Highlight{}, ErrTextNotFound
+	}
+
+	// 书名匹配失败时，较长的摘录仍可以直接在全文中查找
+	if len([]rune(clipping.Text)) >= minContentOnlyLength {
+		for idx := range m.entries {
+			if h, ok := m.locate(idx, clipping); ok {
+				return h, nil
+			}
+		}
+	}
+
+	return Highlight{}, ErrNoDocumentMatch
+}
+
+// rankByTitle 按书名和作者相似度排序候选文档
+func (m *Matcher) rankByTitle(clipping Clipping) []int {
+	title := normalizeTitle(clipping.Title)
+	author := normalizeTitle(clipping.Author)
+
+	type scored struct {
+		idx   int
+		score float64
+	}
+	var ranked []scored
+	for idx, entry := range m.entries {
+		score := titleSimilarity(title, entry.title)
+		if author != "" && entry.author != "" {
+			if titleSimilarity(author, entry.author) >= titleMatchThreshold {
+				score += 0.1
+			} else {
+				score -= 0.1
+			}
+		}
+		if score >= titleMatchThreshold {
+			ranked = append(ranked, scored{idx, score})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+
+	result := make([]int, len(ranked))
+	for i, r := range ranked {
+		result[i] = r.idx
+	}
+	return result
+}
+
+// locate 在指定文档中查找摘录文本
+func (m *Matcher) locate(idx int, clipping Clipping) (Highlight, bool) {
+	entry := &m.entries[idx]
+	if entry.content == nil {
+		content, err := entry.Document.GetContent()
+		if err != nil {
+			return Highlight{}, false
+		}
+		entry.content = newNormalizedText(content)
+		entry.pages = pageOffsets(entry)
+	}
+
+	start, end, ok := entry.content.find(clipping.Text)
+	if !ok {
+		return Highlight{}, false
+	}
+
+	return Highlight{
+		DocumentPath: entry.Path,
+		Start:        start,
+		End:          end,
+		PageNumber:   pageForOffset(entry.pages, start),
+		Text:         string(entry.content.original[start:end]),
+		Note:         clipping.Note,
+		CreatedAt:    clipping.AddedAt,
+		Source:       clipping.Source,
+	}, true
+}
+
+// pageOffsets 计算每页在全文中的起始rune偏移
+func pageOffsets(entry *matcherEntry) []int {
+	pages := entry.Document.GetPages()
+	offsets := make([]int, 0, pages)
+	offset := 0
+	for page := 1; page <= pages; page++ {
+		offsets = append(offsets, offset)
+		content, err := entry.Document.GetPage(page)
+		if err != nil {
+			break
+		}
+		offset += len([]rune(content))
+	}
+	return offsets
+}
+
+// pageForOffset 根据rune偏移计算页码
+func pageForOffset(pages []int, offset int) int {
+	if len(pages) == 0 {
+		return 1
+	}
+	return sort.Search(len(pages), func(i int) bool { return pages[i] > offset })
+}
+
+// normalizedText 折叠空白后的文本及其到原文的偏移映射
+type normalizedText struct {
+	content  string
+	original []rune
+	folded   []rune
+	mapping  []int // folded[i] 对应 original[mapping[i]]
+}
+
+func newNormalizedText(content string) *normalizedText {
+	nt := &normalizedText{content: content, original: []rune(content)}
+	nt.folded, nt.mapping = foldRunes(nt.original)
+	return nt
+}
+
+// foldRunes 小写化、统一引号并将连续空白折叠为单个空格
+func foldRunes(runes []rune) ([]rune, []int) {
+	folded := make([]rune, 0, len(runes))
+	mapping := make([]int, 0, len(runes))
+	space := true
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			if !space {
+				folded = append(folded, ' ')
+				mapping = append(mapping, i)
+			}
+			space = true
+			continue
+		}
+		space = false
+		folded = append(folded, foldRune(r))
+		mapping = append(mapping, i)
+	}
+	if n := len(folded); n > 0 && folded[n-1] == ' ' {
+		folded = folded[:n-1]
+		mapping = mapping[:n-1]
+	}
+	return folded, mapping
+}
+
+func foldRune(r rune) rune {
+	switch r {
+	case '‘', '’', '`':
+		return '\''
+	case '“', '”':
+		return '"'
+	case '—', '–':
+		return '-'
+	}
+	return unicode.ToLower(r)
+}
+
+// find 查找文本，依次尝试精确匹配、空白折叠匹配和锚点模糊匹配
+func (nt *normalizedText) find(text string) (start, end int, ok bool) {
+	if idx := strings.Index(nt.content, text); idx >= 0 {
+		start = len([]rune(nt.content[:idx]))
+		return start, start + len([]rune(text)), true
+	}
+
+	needle, _ := foldRunes([]rune(text))
+	if len(needle) == 0 {
+		return 0, 0, false
+	}
+
+	if pos := indexRunes(nt.folded, needle, 0); pos >= 0 {
+		return nt.toOriginal(pos, pos+len(needle))
+	}
+
+	return nt.fuzzyFind(needle)
+}
+
+// fuzzyFind 以摘录首尾片段为锚点定位候选区间，再用二元组相似度校验
+func (nt *normalizedText) fuzzyFind(needle []rune) (start, end int, ok bool) {
+	n := len(needle)
+	if n < anchorLength*2 {
+		return 0, 0, false
+	}
+
+	head := needle[:anchorLength]
+	tail := needle[n-anchorLength:]
+	bestScore := 0.0
+	bestStart, bestEnd := -1, -1
+
+	for pos := indexRunes(nt.folded, head, 0); pos >= 0; pos = indexRunes(nt.folded, head, pos+1) {
+		limit := pos + n*3/2
+		tailPos := lastIndexRunesBefore(nt.folded, tail, pos+anchorLength, limit)
+		candidateEnd := pos + n
+		if tailPos >= 0 {
+			candidateEnd = tailPos + anchorLength
+		}
+		if candidateEnd > len(nt.folded) {
+			candidateEnd = len(nt.folded)
+		}
+		if score := diceSimilarity(needle, nt.folded[pos:candidateEnd]); score > bestScore {
+			bestScore, bestStart, bestEnd = score, pos, candidateEnd
+		}
+	}
+
+	// 开头可能被改动，再从结尾锚点反向尝试
+	for pos := indexRunes(nt.folded, tail, 0); pos >= 0; pos = indexRunes(nt.folded, tail, pos+1) {
+		candidateEnd := pos + anchorLength
+		candidateStart := candidateEnd - n
+		if candidateStart < 0 {
+			candidateStart = 0
+		}
+		if score := diceSimilarity(needle, nt.folded[candidateStart:candidateEnd]); score > bestScore {
+			bestScore, bestStart, bestEnd = score, candidateStart, candidateEnd
+		}
+	}
+
+	if bestStart < 0 || bestScore < textMatchThreshold {
+		return 0, 0, false
+	}
+	return nt.toOriginal(bestStart, bestEnd)
+}
+
+// toOriginal 将折叠文本区间转换为原文rune区间
+func (nt *normalizedText) toOriginal(start, end int) (int, int, bool) {
+	if start >= end || end > len(nt.mapping) {
+		return 0, 0, false
+	}
+	return nt.mapping[start], nt.mapping[end-1] + 1, true
+}
+
+func indexRunes(haystack, needle []rune, from int) int {
+	for i := from; i+len(needle) <= len(haystack); i++ {
+		if equalRunes(haystack[i:i+len(needle)], needle) {
+			return i
+		}
+	}
+	return -1
+}
+
+func lastIndexRunesBefore(haystack, needle []rune, from, limit int) int {
+	if limit > len(haystack) {
+		limit = len(haystack)
+	}
+	for i := limit - len(needle); i >= from; i-- {
+		if equalRunes(haystack[i:i+len(needle)], needle) {
+			return i
+		}
+	}
+	return -1
+}
+
+func equalRunes(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTitle 规范化书名：小写、去掉标点和括号内的副标题
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range strings.ToLower(title) {
+		switch r {
+		case '(', '[', '（', '【':
+			depth++
+			continue
+		case ')', ']', '）', '】':
+			if depth > 0 {
+				depth--
+			}
+			continue
+		}
+		if depth > 0 {
+			continue
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// titleSimilarity 书名相似度，完全相同为1，包含关系为0.9，否则为二元组Dice系数
+func titleSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		return 0.9
+	}
+	return diceSimilarity([]rune(a), []rune(b))
+}
+
+// diceSimilarity 基于rune二元组的Dice相似度
+func diceSimilarity(a, b []rune) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		if equalRunes(a, b) {
+			return 1
+		}
+		return 0
+	}
+
+	bigrams := make(map[[2]rune]int, len(a))
+	for i := 0; i+1 < len(a); i++ {
+		bigrams[[2]rune{a[i], a[i+1]}]++
+	}
+
+	overlap := 0
+	for i := 0; i+1 < len(b); i++ {
+		key := [2]rune{b[i], b[i+1]}
+		if bigrams[key] > 0 {
+			bigrams[key]--
+			overlap++
+		}
+	}
+
+	return 2 * float64(overlap) / float64(len(a)-1+len(b)-1)
+}
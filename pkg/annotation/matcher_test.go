@@ -0,0 +1,44 @@
+package annotation
+
+import (
+	"ai-reader/pkg/document"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMatchPageNumberInMultibyteText(t *testing.T) {
+	// 各不相同的汉字，没有分页点，按字节分页时每页都在字符中间截断
+	var b strings.Builder
+	for i := 0; i < 2000; i++ {
+		b.WriteRune(rune(0x4e00 + i))
+	}
+	doc := document.NewTextDocument("潮汐", b.String())
+	matcher := NewMatcher([]LibraryEntry{{Path: "tides.txt", Document: doc}})
+	if doc.GetPages() < 3 {
+		t.Fatalf("document has %d pages, want several", doc.GetPages())
+	}
+
+	// 每页开头的文字应当落在这一页
+	for page := 1; page <= doc.GetPages(); page++ {
+		content, _ := doc.GetPage(page)
+		text := []rune(content)
+		if len(text) > 12 {
+			text = text[:12]
+		}
+		highlight, err := matcher.Match(Clipping{Title: "潮汐", Text: string(text)})
+		if err != nil {
+			t.Fatalf("page %d: %v", page, err)
+		}
+		if highlight.PageNumber != page || highlight.Text != string(text) {
+			t.Errorf("text from page %d matched page %d, %q", page, highlight.PageNumber, highlight.Text)
+		}
+	}
+}
+
+func TestMatchEmptyClipping(t *testing.T) {
+	matcher := NewMatcher(nil)
+	if _, err := matcher.Match(Clipping{Title: "潮汐", Note: "only a note"}); !errors.Is(err, ErrEmptyClipping) {
+		t.Fatalf("error = %v, want ErrEmptyClipping", err)
+	}
+}
@@ -0,0 +1,118 @@
+package annotation
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Store 基于JSON文件的高亮存储
+type Store struct {
+	storePath  string
+	highlights map[string][]Highlight // 按文档路径分组
+	mu         sync.RWMutex
+}
+
+// NewStore 创建高亮存储
+func NewStore(storePath string) *Store {
+	return &Store{
+		storePath:  storePath,
+		highlights: make(map[string][]Highlight),
+	}
+}
+
+func (s *Store) Add(highlight Highlight) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if highlight.ID == "" {
+		highlight.ID = HighlightID(highlight.DocumentPath, highlight.Start, highlight.End)
+	}
+
+	existing := s.highlights[highlight.DocumentPath]
+	for _, h := range existing {
+		if h.ID == highlight.ID {
+			return false
+		}
+	}
+
+	existing = append(existing, highlight)
+	sort.SliceStable(existing, func(i, j int) bool {
+		return existing[i].Start < existing[j].Start
+	})
+	s.highlights[highlight.DocumentPath] = existing
+	return true
+}
+
+func (s *Store) GetHighlights(documentPath string) []Highlight {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]Highlight, len(s.highlights[documentPath]))
+	copy(result, s.highlights[documentPath])
+	return result
+}
+
+func (s *Store) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for path, highlights := range s.highlights {
+		for i, h := range highlights {
+			if h.ID == id {
+				s.highlights[path] = append(highlights[:i], highlights[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (s *Store) Save() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// 确保存储目录存在
+	if err := os.MkdirAll(filepath.Dir(s.storePath), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s.highlights, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.storePath, data, 0644)
+}
+
+func (s *Store) Load() error {
+	// 存储文件不存在时保持为空
+	if _, err := os.Stat(s.storePath); os.IsNotExist(err) {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.storePath)
+	if err != nil {
+		return err
+	}
+
+	highlights := make(map[string][]Highlight)
+	if err := json.Unmarshal(data, &highlights); err != nil {
+		return fmt.Errorf("failed to parse highlights: %w", err)
+	}
+
+	s.mu.Lock()
+	s.highlights = highlights
+	s.mu.Unlock()
+	return nil
+}
+
+// HighlightID 根据文档路径和偏移生成稳定的高亮ID
+func HighlightID(documentPath string, start, end int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s:%d:%d", documentPath, start, end)))
+	return hex.EncodeToString(sum[:8])
+}
@@ -1,6 +1,7 @@
 package document
 
 import (
+	"ai-reader/pkg/textstats"
 	"io"
 )
 
@@ -30,16 +31,25 @@ type Document interface {
 
 // Metadata 文档元数据
 type Metadata struct {
-	Title       string
-	Author      string
-	Subject     string
-	Creator     string
-	CreatedAt   string
-	ModifiedAt  string
-	PageCount   int
-	WordCount   int
-	FileSize    int64
-	Format      string
+	Title          string
+	Author         string
+	Subject        string
+	Creator        string
+	CreatedAt      string
+	ModifiedAt     string
+	PageCount      int
+	WordCount      int // 每个CJK字计为一个词，加上拉丁文的词数
+	CJKCharCount   int
+	LatinWordCount int
+	SentenceCount  int
+	ParagraphCount int
+	FileSize       int64 // 文件在磁盘上的大小
+	Format         string
+}
+
+// StatsProvider 加载时已统计过全文的文档实现此接口，避免重复统计
+type StatsProvider interface {
+	GetTextStats() textstats.Stats
 }
 
 // SearchResult 搜索结果
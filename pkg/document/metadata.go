@@ -0,0 +1,14 @@
+package document
+
+import (
+	"ai-reader/pkg/textstats"
+)
+
+// applyTextStats 用全文统计结果填充元数据中的计数，各格式的加载器提取正文后都应调用
+func applyTextStats(meta *Metadata, stats textstats.Stats) {
+	meta.WordCount = stats.WordCount()
+	meta.CJKCharCount = stats.CJKCharacters
+	meta.LatinWordCount = stats.LatinWords
+	meta.SentenceCount = stats.Sentences
+	meta.ParagraphCount = stats.Paragraphs
+}
@@ -0,0 +1,88 @@
+package document
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// OutlineEntry 目录条目
+type OutlineEntry struct {
+	Title  string
+	Level  int // 从1开始，数字越大层级越深
+	Page   int
+	Offset int // 标题在页内的rune偏移
+}
+
+// Outliner 自带目录的文档实现此接口，如带书签的PDF、EPUB
+type Outliner interface {
+	GetOutline() []OutlineEntry
+}
+
+// maxHeadingLength 识别为标题的行的最大字符数
+const maxHeadingLength = 40
+
+// 标题识别规则，按顺序匹配
+var headingPatterns = []struct {
+	pattern *regexp.Regexp
+	level   int
+}{
+	{regexp.MustCompile(`^第[0-9零〇一二三四五六七八九十百千两]+[卷部篇集]`), 1},
+	{regexp.MustCompile(`^第[0-9零〇一二三四五六七八九十百千两]+[章回]`), 2},
+	{regexp.MustCompile(`^第[0-9零〇一二三四五六七八九十百千两]+节`), 3},
+	{regexp.MustCompile(`^(序[言章]?|前言|引子|楔子|尾声|后记|番外)(\s|$|[:：])`), 2},
+	{regexp.MustCompile(`(?i)^(part|book|volume)\s+([0-9]+|[ivxlc]+|[a-z]+)\b`), 1},
+	{regexp.MustCompile(`(?i)^(chapter|prologue|epilogue|preface|introduction)\b`), 2},
+}
+
+// markdownHeading Markdown 风格标题
+var markdownHeading = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+
+// ExtractOutline 获取文档目录。文档实现了 Outliner 时直接使用，否则按常见的章节标题格式识别
+func ExtractOutline(doc Document) []OutlineEntry {
+	if outliner, ok := doc.(Outliner); ok {
+		return outliner.GetOutline()
+	}
+
+	var entries []OutlineEntry
+	for page := 1; page <= doc.GetPages(); page++ {
+		content, err := doc.GetPage(page)
+		if err != nil {
+			continue
+		}
+
+		offset := 0
+		for _, line := range strings.SplitAfter(content, "\n") {
+			if title, level, ok := parseHeading(line); ok {
+				// 标题在行内的起点，跳过行首空白和Markdown的 “#”
+				start := strings.Index(line, title)
+				entries = append(entries, OutlineEntry{
+					Title:  title,
+					Level:  level,
+					Page:   page,
+					Offset: offset + utf8.RuneCountInString(line[:start]),
+				})
+			}
+			offset += utf8.RuneCountInString(line)
+		}
+	}
+	return entries
+}
+
+// parseHeading 判断一行是否为章节标题
+func parseHeading(line string) (string, int, bool) {
+	title := strings.TrimSpace(strings.Trim(strings.TrimSpace(line), "　"))
+	if title == "" || utf8.RuneCountInString(title) > maxHeadingLength {
+		return "", 0, false
+	}
+
+	if m := markdownHeading.FindStringSubmatch(title); m != nil {
+		return strings.TrimSpace(m[2]), len(m[1]), true
+	}
+	for _, h := range headingPatterns {
+		if h.pattern.MatchString(title) {
+			return title, h.level, true
+		}
+	}
+	return "", 0, false
+}
@@ -1,11 +1,13 @@
 package document
 
 import (
+	"ai-reader/pkg/textstats"
 	"bufio"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
@@ -15,6 +17,7 @@ type TextDocument struct {
 	content  string
 	pages    []string
 	metadata Metadata
+	stats    textstats.Stats
 }
 
 // NewTextDocument 创建新的文本文档
@@ -51,6 +54,11 @@ func (d *TextDocument) GetMetadata() Metadata {
 	return d.metadata
 }
 
+// GetTextStats 获取加载时统计的全文数据
+func (d *TextDocument) GetTextStats() textstats.Stats {
+	return d.stats
+}
+
 func (d *TextDocument) Search(query string) ([]SearchResult, error) {
 	var results []SearchResult
 	query = strings.ToLower(query)
@@ -109,6 +117,10 @@ func (d *TextDocument) generatePages() {
 				break
 			}
 		}
+		// 没有合适的分页点时退到字符边界，不切开多字节字符
+		for breakPoint > 0 && !utf8.RuneStart(content[breakPoint]) {
+			breakPoint--
+		}
 		
 		pages = append(pages, content[:breakPoint])
 		content = content[breakPoint:]
@@ -121,19 +133,19 @@ func (d *TextDocument) generatePages() {
 	d.pages = pages
 }
 
-// generateMetadata 生成文档元数据
+// generateMetadata 生成文档元数据，文件大小默认为内容的字节数，从文件加载时由加载器更新
 func (d *TextDocument) generateMetadata() {
-	wordCount := len(strings.Fields(d.content))
+	d.stats = textstats.Compute(d.content)
 	d.metadata = Metadata{
 		Title:     d.title,
 		Author:    "",
 		Subject:   "",
 		Creator:   "AI Reader",
 		PageCount: len(d.pages),
-		WordCount: wordCount,
 		FileSize:  int64(len(d.content)),
 		Format:    "text/plain",
 	}
+	applyTextStats(&d.metadata, d.stats)
 }
 
 // TxtLoader TXT文件加载器
@@ -155,12 +167,28 @@ func (l *TxtLoader) LoadFromFile(filename string) (Document, error) {
 	}
 	defer file.Close()
 	
-	return l.LoadFromReader(file, filename)
+	doc, err := l.load(file, filename)
+	if err != nil {
+		return nil, err
+	}
+	
+	// 使用磁盘上的文件信息
+	if info, err := file.Stat(); err == nil {
+		doc.metadata.FileSize = info.Size()
+		doc.metadata.ModifiedAt = info.ModTime().Format(time.RFC3339)
+	}
+	return doc, nil
 }
 
 func (l *TxtLoader) LoadFromReader(reader io.Reader, filename string) (Document, error) {
+	return l.load(reader, filename)
+}
+
+// load 读取文本并创建文档，文件大小按读取的原始字节数计算
+func (l *TxtLoader) load(reader io.Reader, filename string) (*TextDocument, error) {
+	counter := &countingReader{reader: reader}
 	var content strings.Builder
-	scanner := bufio.NewScanner(reader)
+	scanner := bufio.NewScanner(counter)
 	
 	for scanner.Scan() {
 		content.WriteString(scanner.Text())
@@ -182,7 +210,21 @@ func (l *TxtLoader) LoadFromReader(reader io.Reader, filename string) (Document,
 		title = title[:len(title)-len(ext)]
 	}
 	
-	return NewTextDocument(title, contentStr), nil
+	doc := NewTextDocument(title, contentStr)
+	doc.metadata.FileSize = counter.n
+	return doc, nil
+}
+
+// countingReader 统计读取的字节数
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
 }
 
 // 工具函数
@@ -0,0 +1,36 @@
+package document
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTextDocumentPages(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"short", "A single page."},
+		{"latin sentences", strings.Repeat("The moon pulls the sea. ", 200)},
+		{"cjk without breaks", strings.Repeat("月亮牵引着海水", 500)},
+		{"mixed widths", strings.Repeat("a中é😀", 700)},
+	}
+	for _, tt := range tests {
+		doc := NewTextDocument(tt.name, tt.content)
+		var joined strings.Builder
+		for page := 1; page <= doc.GetPages(); page++ {
+			content, err := doc.GetPage(page)
+			if err != nil {
+				t.Fatalf("%s: page %d: %v", tt.name, page, err)
+			}
+			if !utf8.ValidString(content) {
+				t.Errorf("%s: page %d splits a character", tt.name, page)
+			}
+			joined.WriteString(content)
+		}
+		if joined.String() != tt.content {
+			t.Errorf("%s: pages do not add up to the content", tt.name)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package extract
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 概念候选的加权
+const (
+	capitalizedWeight = 1.5 // 首字母大写的短语多为专有名词
+	minRepeats        = 2   // 普通短语和CJK词至少出现的次数
+)
+
+// concept 概念候选
+type concept struct {
+	text   string // 首次出现时的原文
+	count  int
+	weight float64
+}
+
+// Concepts 抽取概念候选：首字母大写的拉丁短语、重复出现的多词短语和重复出现的CJK多字词
+func Concepts(runes []rune, count int) []Keyword {
+	candidates := make(map[string]*concept)
+	add := func(key, text string, weight float64) {
+		if c, ok := candidates[key]; ok {
+			c.count++
+			return
+		}
+		candidates[key] = &concept{text: text, count: 1, weight: weight}
+	}
+
+	tokens := tokenize(runes)
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.cjk:
+			if !t.stop {
+				add(t.text, t.text, float64(t.span.Len())/2)
+			}
+		case t.capital && !t.stop:
+			// 连续的首字母大写词组成一个专有名词
+			end := i + 1
+			for end < len(tokens) && tokens[end].capital && !tokens[end].cjk && adjacent(runes, tokens[end-1], tokens[end]) {
+				end++
+			}
+			if end-i == 1 && sentenceInitial(runes, t.span.Start) {
+				continue
+			}
+			text := string(runes[t.span.Start:tokens[end-1].span.End])
+			add("cap:"+strings.ToLower(text), text, capitalizedWeight*float64(end-i))
+			i = end - 1
+		}
+	}
+	for phrase, c := range rakeCandidates(runes) {
+		if len(c.words) >= 2 && c.count >= minRepeats {
+			candidates[phrase] = &concept{text: phrase, count: c.count, weight: float64(len(c.words))}
+		}
+	}
+
+	scores := make(map[string]float64, len(candidates))
+	for key, c := range candidates {
+		if !strings.HasPrefix(key, "cap:") && c.count < minRepeats {
+			continue
+		}
+		scores[c.text] = float64(c.count) * c.weight
+	}
+	return top(scores, count)
+}
+
+// sentenceInitial 偏移处的词是否位于句首，句首的单个大写词不一定是专有名词
+func sentenceInitial(runes []rune, offset int) bool {
+	for i := offset - 1; i >= 0; i-- {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || isOpeningPunct(r):
+			continue
+		case isTerminal(r):
+			return true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isTerminal(r rune) bool {
+	switch r {
+	case '.', '!', '?', '。', '！', '？', '…', ':', '：':
+		return true
+	}
+	return false
+}
+
+func isOpeningPunct(r rune) bool {
+	switch r {
+	case '"', '\'', '“', '‘', '(', '（', '[', '「', '『', '《':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package extract
+
+import (
+	"ai-reader/pkg/segment"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Keyword 关键词或短语，Score归一化到0-1
+type Keyword struct {
+	Text  string
+	Score float64
+}
+
+// Corpus 计算逆文档频率的语料，通常以整个文档的各段落作为语料中的文档
+type Corpus struct {
+	docs int
+	df   map[string]int
+}
+
+// NewCorpus 由若干文本建立语料
+func NewCorpus(texts [][]rune) *Corpus {
+	c := &Corpus{docs: len(texts), df: make(map[string]int)}
+	for _, text := range texts {
+		seen := make(map[string]bool)
+		for _, w := range contentWords(tokenize(text)) {
+			if !seen[w] {
+				seen[w] = true
+				c.df[w]++
+			}
+		}
+	}
+	return c
+}
+
+// ParagraphCorpus 以文本的各段落为文档建立语料
+func ParagraphCorpus(runes []rune) *Corpus {
+	var texts [][]rune
+	for _, span := range segment.Paragraphs(runes) {
+		texts = append(texts, runes[span.Start:span.End])
+	}
+	return NewCorpus(texts)
+}
+
+// idf 平滑的逆文档频率
+func (c *Corpus) idf(term string) float64 {
+	return math.Log(float64(c.docs+1)/float64(c.df[term]+1)) + 1
+}
+
+// Keywords 按TF-IDF取前count个关键词。corpus为nil时以文本自身的句子为语料
+func Keywords(runes []rune, corpus *Corpus, count int) []Keyword {
+	if corpus == nil {
+		var texts [][]rune
+		for _, span := range segment.Sentences(runes) {
+			texts = append(texts, runes[span.Start:span.End])
+		}
+		corpus = NewCorpus(texts)
+	}
+
+	tf := make(map[string]int)
+	for _, w := range contentWords(tokenize(runes)) {
+		tf[w]++
+	}
+	scores := make(map[string]float64, len(tf))
+	for w, n := range tf {
+		scores[w] = float64(n) * corpus.idf(w)
+	}
+	return top(scores, count)
+}
+
+// Phrases 用RAKE抽取拉丁文的关键短语：停用词、标点和CJK文字把文本切成候选短语，
+// 词的得分为(度+频次)/频次，短语得分为其中各词得分之和
+func Phrases(runes []rune, count int) []Keyword {
+	candidates := rakeCandidates(runes)
+	freq := make(map[string]int)
+	degree := make(map[string]int)
+	scores := make(map[string]float64, len(candidates))
+	for _, c := range candidates {
+		for _, w := range c.words {
+			freq[w] += c.count
+			degree[w] += c.count * (len(c.words) - 1)
+		}
+	}
+	for phrase, c := range candidates {
+		score := 0.0
+		for _, w := range c.words {
+			score += float64(degree[w]+freq[w]) / float64(freq[w])
+		}
+		scores[phrase] = score
+	}
+	return top(scores, count)
+}
+
+// rakeCandidate RAKE候选短语
+type rakeCandidate struct {
+	words []string
+	count int
+}
+
+// maxPhraseWords 候选短语的最大词数，更长的通常不是术语
+const maxPhraseWords = 4
+
+// rakeCandidates 按停用词和标点切分出候选短语及其出现次数
+func rakeCandidates(runes []rune) map[string]*rakeCandidate {
+	candidates := make(map[string]*rakeCandidate)
+	tokens := tokenize(runes)
+
+	var run []token
+	flush := func() {
+		if len(run) > 0 && len(run) <= maxPhraseWords {
+			words := make([]string, len(run))
+			for i, t := range run {
+				words[i] = t.text
+			}
+			phrase := strings.Join(words, " ")
+			if c, ok := candidates[phrase]; ok {
+				c.count++
+			} else {
+				candidates[phrase] = &rakeCandidate{words: words, count: 1}
+			}
+		}
+		run = run[:0]
+	}
+	for i, t := range tokens {
+		if t.cjk || t.stop {
+			flush()
+			continue
+		}
+		if len(run) > 0 && !adjacent(runes, tokens[i-1], t) {
+			flush()
+		}
+		run = append(run, t)
+	}
+	flush()
+	return candidates
+}
+
+// top 按得分取前count项并归一化，得分相同时按文字排序保证结果稳定
+func top(scores map[string]float64, count int) []Keyword {
+	keywords := make([]Keyword, 0, len(scores))
+	for text, score := range scores {
+		if score > 0 {
+			keywords = append(keywords, Keyword{Text: text, Score: score})
+		}
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Text < keywords[j].Text
+	})
+	if len(keywords) > count {
+		keywords = keywords[:count]
+	}
+	if len(keywords) > 0 {
+		highest := keywords[0].Score
+		for i := range keywords {
+			keywords[i].Score /= highest
+		}
+	}
+	return keywords
+}
@@ -0,0 +1,37 @@
+package extract
+
+// englishStopwords 常见的英文虚词
+var englishStopwords = toSet(
+	"a", "about", "above", "after", "again", "against", "all", "also", "am", "an", "and", "any", "are", "as", "at",
+	"be", "because", "been", "before", "being", "below", "between", "both", "but", "by",
+	"can", "could", "did", "do", "does", "doing", "down", "during", "each", "either", "even", "ever", "every",
+	"few", "for", "from", "further", "had", "has", "have", "having", "he", "her", "here", "hers", "herself",
+	"him", "himself", "his", "how", "however", "i", "if", "in", "into", "is", "it", "its", "itself", "just",
+	"less", "like", "made", "make", "many", "may", "me", "might", "more", "most", "much", "must", "my", "myself",
+	"neither", "no", "nor", "not", "now", "of", "off", "often", "on", "once", "one", "only", "or", "other", "our",
+	"ours", "ourselves", "out", "over", "own", "per", "perhaps", "quite", "rather", "really", "said", "same",
+	"say", "says", "see", "seem", "seems", "shall", "she", "should", "since", "so", "some", "such",
+	"than", "that", "the", "their", "theirs", "them", "themselves", "then", "there", "these", "they", "this",
+	"those", "though", "through", "thus", "to", "too", "under", "until", "up", "upon", "us", "used", "using",
+	"very", "was", "we", "well", "were", "what", "when", "where", "whether", "which", "while", "who", "whom",
+	"whose", "why", "will", "with", "within", "without", "would", "yet", "you", "your", "yours", "yourself",
+	"don't", "can't", "won't", "it's", "i'm", "isn't", "doesn't", "didn't",
+)
+
+// chineseStopwords 常见的中文虚词和代词，单字已由分词结果排除
+var chineseStopwords = toSet(
+	"我们", "你们", "他们", "她们", "它们", "自己", "这个", "那个", "这些", "那些", "这样", "那样", "这里", "那里",
+	"什么", "怎么", "怎样", "为什么", "如何", "哪里", "哪些", "因为", "所以", "但是", "而且", "并且", "或者",
+	"如果", "虽然", "然而", "于是", "然后", "以及", "还是", "就是", "只是", "不是", "没有", "可以", "可能",
+	"已经", "一个", "一些", "一种", "一样", "其中", "之后", "之前", "以后", "以前", "时候", "的话", "不过",
+	"非常", "十分", "比较", "更加", "还有", "同时", "此外", "另外", "因此", "由于", "对于", "关于", "通过",
+	"进行", "成为", "作为", "认为", "以为", "觉得", "知道", "应该", "需要", "不能", "不会", "这种", "那种",
+)
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
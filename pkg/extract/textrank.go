@@ -0,0 +1,114 @@
+package extract
+
+import (
+	"ai-reader/pkg/segment"
+	"math"
+	"sort"
+	"strings"
+)
+
+// TextRank 参数
+const (
+	dampingFactor = 0.85
+	maxIterations = 50
+	convergence   = 1e-4
+)
+
+// Sentence 摘要句
+type Sentence struct {
+	Span  segment.Span
+	Text  string
+	Score float64
+}
+
+// Summarize 用TextRank抽取最重要的count个句子，按原文顺序返回。
+// 句子之间的相似度为共同实词数除以两句实词数的对数之和
+func Summarize(runes []rune, count int) []Sentence {
+	spans := segment.Sentences(runes)
+	if len(spans) == 0 || count <= 0 {
+		return nil
+	}
+
+	words := make([]map[string]bool, len(spans))
+	sizes := make([]int, len(spans))
+	for i, span := range spans {
+		content := contentWords(tokenize(runes[span.Start:span.End]))
+		words[i] = make(map[string]bool, len(content))
+		for _, w := range content {
+			words[i][w] = true
+		}
+		sizes[i] = len(content)
+	}
+
+	// 相似度矩阵和每句的出边权重之和
+	weights := make([][]float64, len(spans))
+	totals := make([]float64, len(spans))
+	for i := range spans {
+		weights[i] = make([]float64, len(spans))
+	}
+	for i := range spans {
+		for j := i + 1; j < len(spans); j++ {
+			w := similarity(words[i], words[j], sizes[i], sizes[j])
+			weights[i][j], weights[j][i] = w, w
+			totals[i] += w
+			totals[j] += w
+		}
+	}
+
+	scores := make([]float64, len(spans))
+	for i := range scores {
+		scores[i] = 1
+	}
+	for iter := 0; iter < maxIterations; iter++ {
+		delta := 0.0
+		next := make([]float64, len(spans))
+		for i := range spans {
+			sum := 0.0
+			for j := range spans {
+				if totals[j] > 0 && weights[j][i] > 0 {
+					sum += weights[j][i] / totals[j] * scores[j]
+				}
+			}
+			next[i] = 1 - dampingFactor + dampingFactor*sum
+			delta = math.Max(delta, math.Abs(next[i]-scores[i]))
+		}
+		scores = next
+		if delta < convergence {
+			break
+		}
+	}
+
+	sentences := make([]Sentence, len(spans))
+	for i, span := range spans {
+		sentences[i] = Sentence{
+			Span:  span,
+			Text:  strings.TrimSpace(string(runes[span.Start:span.End])),
+			Score: scores[i],
+		}
+	}
+	// 分数相同时靠前的句子优先
+	sort.SliceStable(sentences, func(i, j int) bool { return sentences[i].Score > sentences[j].Score })
+	if len(sentences) > count {
+		sentences = sentences[:count]
+	}
+	sort.Slice(sentences, func(i, j int) bool { return sentences[i].Span.Start < sentences[j].Span.Start })
+	return sentences
+}
+
+// similarity TextRank的句子相似度
+func similarity(a, b map[string]bool, sizeA, sizeB int) float64 {
+	overlap := 0
+	for w := range a {
+		if b[w] {
+			overlap++
+		}
+	}
+	if overlap == 0 {
+		return 0
+	}
+	denominator := math.Log(float64(sizeA)) + math.Log(float64(sizeB))
+	if denominator <= 0 {
+		denominator = 1
+	}
+	return float64(overlap) / denominator
+}
@@ -0,0 +1,72 @@
+package extract
+
+import (
+	"ai-reader/pkg/segment"
+	"strings"
+	"unicode"
+)
+
+// token 分词得到的词，拉丁词统一为小写
+type token struct {
+	text    string
+	span    segment.Span
+	cjk     bool
+	capital bool // 拉丁词首字母大写
+	stop    bool // 停用词、单个CJK字或纯数字，不作为关键词
+}
+
+// tokenize 分词并标记停用词
+func tokenize(runes []rune) []token {
+	spans := segment.Default().Words(runes)
+	tokens := make([]token, 0, len(spans))
+	for _, span := range spans {
+		word := runes[span.Start:span.End]
+		t := token{
+			span:    span,
+			cjk:     segment.IsCJK(word[0]),
+			capital: unicode.IsUpper(word[0]),
+		}
+		if t.cjk {
+			t.text = string(word)
+			t.stop = len(word) < 2 || chineseStopwords[t.text]
+		} else {
+			t.text = strings.ToLower(string(word))
+			t.stop = len(word) < 2 || englishStopwords[t.text] || isNumber(word)
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+// contentWords 非停用词
+func contentWords(tokens []token) []string {
+	var words []string
+	for _, t := range tokens {
+		if !t.stop {
+			words = append(words, t.text)
+		}
+	}
+	return words
+}
+
+// adjacent 两个拉丁词之间只有空白，可以组成短语
+func adjacent(runes []rune, a, b token) bool {
+	if a.cjk || b.cjk {
+		return false
+	}
+	for _, r := range runes[a.span.End:b.span.Start] {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumber(word []rune) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) && r != '.' && r != ',' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package fuzzy
+
+import (
+	"sort"
+	"unicode"
+)
+
+// 评分参数
+const (
+	scoreMatch       = 16 // 每个匹配字符的基础分
+	bonusConsecutive = 12 // 与上一个匹配字符相邻
+	bonusBoundary    = 24 // 匹配在词首（空白、标点之后或大小写交界处）
+	bonusFirstChar   = 16 // 匹配目标的第一个字符
+	penaltyGap       = 2  // 两个匹配之间每个跳过的字符
+	penaltyLeading   = 1  // 第一个匹配之前每个跳过的字符
+	maxLeadingGap    = 12 // 前导跳过扣分的上限
+)
+
+// Match 匹配结果
+type Match struct {
+	Score     int
+	Positions []int // 匹配字符在目标中的rune偏移
+}
+
+// Result 在多个目标中查找时的结果
+type Result struct {
+	Index int // 目标在输入中的下标
+	Match
+}
+
+// MatchString 检查pattern是否按顺序出现在text中（忽略大小写和pattern中的空白），并给出评分。
+// 连续匹配和词首匹配得分更高
+func MatchString(pattern, text string) (Match, bool) {
+	var needle []rune
+	for _, r := range pattern {
+		if !unicode.IsSpace(r) {
+			needle = append(needle, unicode.ToLower(r))
+		}
+	}
+	haystack := []rune(text)
+	if len(needle) == 0 {
+		return Match{}, true
+	}
+	if len(needle) > len(haystack) {
+		return Match{}, false
+	}
+
+	lower := make([]rune, len(haystack))
+	for i, r := range haystack {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// 以每个可能的首字符位置做一次贪心匹配，取最高分
+	best := Match{Score: -1 << 31}
+	found := false
+	for start := 0; start+len(needle) <= len(lower); start++ {
+		if lower[start] != needle[0] {
+			continue
+		}
+		positions, ok := greedy(haystack, lower, needle, start)
+		if !ok {
+			// 更靠后的起点也不可能匹配完整
+			break
+		}
+		if score := score(haystack, positions); score > best.Score {
+			best = Match{Score: score, Positions: positions}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// Find 在多个目标中查找，按得分从高到低返回匹配的目标，得分相同时保持原顺序
+func Find(pattern string, targets []string) []Result {
+	var results []Result
+	for i, target := range targets {
+		if match, ok := MatchString(pattern, target); ok {
+			results = append(results, Result{Index: i, Match: match})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// greedy 从start开始依次匹配needle中的字符，每个字符优先匹配词首
+func greedy(text, lower, needle []rune, start int) ([]int, bool) {
+	positions := make([]int, 0, len(needle))
+	positions = append(positions, start)
+	i := start + 1
+	for _, r := range needle[1:] {
+		next := -1
+		for j := i; j < len(lower); j++ {
+			if lower[j] != r {
+				continue
+			}
+			if next < 0 {
+				next = j
+			}
+			// 紧接上一个匹配时直接采用，否则尝试向后找一个词首
+			if j == i || isBoundary(text, j) {
+				next = j
+				break
+			}
+		}
+		if next < 0 {
+			return nil, false
+		}
+		positions = append(positions, next)
+		i = next + 1
+	}
+	return positions, true
+}
+
+func score(text []rune, positions []int) int {
+	total := 0
+	for k, pos := range positions {
+		total += scoreMatch
+		if pos == 0 {
+			total += bonusFirstChar
+		}
+		if isBoundary(text, pos) {
+			total += bonusBoundary
+		}
+		if k > 0 {
+			if gap := pos - positions[k-1] - 1; gap == 0 {
+				total += bonusConsecutive
+			} else {
+				total -= gap * penaltyGap
+			}
+		}
+	}
+
+	leading := positions[0]
+	if leading > maxLeadingGap {
+		leading = maxLeadingGap
+	}
+	total -= leading * penaltyLeading
+	return total
+}
+
+// isBoundary 判断位置是否为词首
+func isBoundary(text []rune, pos int) bool {
+	if pos == 0 {
+		return true
+	}
+	prev, cur := text[pos-1], text[pos]
+	switch {
+	case unicode.IsSpace(prev) || unicode.IsPunct(prev) || unicode.IsSymbol(prev):
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return true
+	case unicode.IsLetter(prev) != unicode.IsLetter(cur) && !unicode.Is(unicode.Han, cur):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package segment
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+//go:embed dict.txt
+var defaultDictionary string
+
+// Span 文本区间，以rune偏移表示 [Start,End)
+type Span struct {
+	Start int
+	End   int
+}
+
+// Len 区间长度
+func (s Span) Len() int {
+	return s.End - s.Start
+}
+
+// Contains 检查偏移是否落在区间内
+func (s Span) Contains(offset int) bool {
+	return offset >= s.Start && offset < s.End
+}
+
+// Segmenter 分词器：拉丁文按字母数字切分，CJK文本按词典双向最大匹配切分
+type Segmenter struct {
+	words      map[string]bool
+	maxWordLen int
+	mu         sync.RWMutex
+}
+
+// NewSegmenter 创建分词器并加载内置词典
+func NewSegmenter() *Segmenter {
+	s := &Segmenter{
+		words:      make(map[string]bool),
+		maxWordLen: 1,
+	}
+	s.LoadDictionary(strings.NewReader(defaultDictionary))
+	return s
+}
+
+var (
+	defaultSegmenter     *Segmenter
+	defaultSegmenterOnce sync.Once
+)
+
+// Default 返回共享的默认分词器
+func Default() *Segmenter {
+	defaultSegmenterOnce.Do(func() {
+		defaultSegmenter = NewSegmenter()
+	})
+	return defaultSegmenter
+}
+
+// LoadDictionary 加载词典，每行第一列为词，#开头的行为注释
+func (s *Segmenter) LoadDictionary(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word := strings.Fields(line)[0]
+		s.words[word] = true
+		if n := len([]rune(word)); n > s.maxWordLen {
+			s.maxWordLen = n
+		}
+	}
+	return scanner.Err()
+}
+
+// AddWord 添加单个词
+func (s *Segmenter) AddWord(word string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.words[word] = true
+	if n := len([]rune(word)); n > s.maxWordLen {
+		s.maxWordLen = n
+	}
+}
+
+// Words 切分出所有词的区间，空白和标点不作为词返回
+func (s *Segmenter) Words(runes []rune) []Span {
+	var spans []Span
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case IsCJK(r):
+			end := i
+			for end < len(runes) && IsCJK(runes[end]) {
+				end++
+			}
+			spans = append(spans, s.segmentCJK(runes, i, end)...)
+			i = end
+		case isWordRune(r):
+			end := i + 1
+			for end < len(runes) && (isWordRune(runes[end]) || isWordJoiner(runes, end)) {
+				end++
+			}
+			spans = append(spans, Span{i, end})
+			i = end
+		default:
+			i++
+		}
+	}
+	return spans
+}
+
+// WordAt 返回偏移所在的词；偏移落在空白或标点上时返回该字符本身
+func (s *Segmenter) WordAt(runes []rune, offset int) Span {
+	if offset < 0 || offset >= len(runes) {
+		return Span{offset, offset}
+	}
+
+	r := runes[offset]
+	switch {
+	case IsCJK(r):
+		// 只需要对所在的CJK连续片段分词
+		start, end := offset, offset
+		for start > 0 && IsCJK(runes[start-1]) {
+			start--
+		}
+		for end < len(runes) && IsCJK(runes[end]) {
+			end++
+		}
+		for _, span := range s.segmentCJK(runes, start, end) {
+			if span.Contains(offset) {
+				return span
+			}
+		}
+	case isWordRune(r):
+		start, end := offset, offset+1
+		for start > 0 && (isWordRune(runes[start-1]) || isWordJoiner(runes, start-1)) {
+			start--
+		}
+		for end < len(runes) && (isWordRune(runes[end]) || isWordJoiner(runes, end)) {
+			end++
+		}
+		return Span{start, end}
+	}
+
+	return Span{offset, offset + 1}
+}
+
+// segmentCJK 对CJK片段做双向最大匹配，取词数更少、单字更少的结果
+func (s *Segmenter) segmentCJK(runes []rune, start, end int) []Span {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	forward := s.forwardMatch(runes, start, end)
+	backward := s.backwardMatch(runes, start, end)
+
+	if len(forward) != len(backward) {
+		if len(forward) < len(backward) {
+			return forward
+		}
+		return backward
+	}
+	if countSingles(forward) < countSingles(backward) {
+		return forward
+	}
+	// 中文里逆向最大匹配通常更准确
+	return backward
+}
+
+func (s *Segmenter) forwardMatch(runes []rune, start, end int) []Span {
+	var spans []Span
+	for i := start; i < end; {
+		n := s.maxWordLen
+		if i+n > end {
+			n = end - i
+		}
+		for ; n > 1; n-- {
+			if s.words[string(runes[i:i+n])] {
+				break
+			}
+		}
+		spans = append(spans, Span{i, i + n})
+		i += n
+	}
+	return spans
+}
+
+func (s *Segmenter) backwardMatch(runes []rune, start, end int) []Span {
+	var spans []Span
+	for j := end; j > start; {
+		n := s.maxWordLen
+		if j-n < start {
+			n = j - start
+		}
+		for ; n > 1; n-- {
+			if s.words[string(runes[j-n:j])] {
+				break
+			}
+		}
+		spans = append(spans, Span{j - n, j})
+		j -= n
+	}
+
+	// 反转为正序
+	for i, k := 0, len(spans)-1; i < k; i, k = i+1, k-1 {
+		spans[i], spans[k] = spans[k], spans[i]
+	}
+	return spans
+}
+
+func countSingles(spans []Span) int {
+	count := 0
+	for _, span := range spans {
+		if span.Len() == 1 {
+			count++
+		}
+	}
+	return count
+}
+
+// IsCJK 判断是否为中日韩文字（不含标点）
+func IsCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// isWordRune 判断是否为拉丁等以空格分词的文字中的字符
+func isWordRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)) && !IsCJK(r)
+}
+
+// isWordJoiner 判断词内连接符，如 don't、well-known、3.14
+func isWordJoiner(runes []rune, i int) bool {
+	switch runes[i] {
+	case '\'', '’', '-', '.', '_':
+		return i > 0 && i+1 < len(runes) && isWordRune(runes[i-1]) && isWordRune(runes[i+1])
+	}
+	return false
+}
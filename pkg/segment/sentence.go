@@ -0,0 +1,158 @@
+package segment
+
+import "unicode"
+
+// Sentences 切分所有句子，句子不跨段落，首尾空白不计入句子
+func Sentences(runes []rune) []Span {
+	var spans []Span
+	for _, paragraph := range Paragraphs(runes) {
+		start := paragraph.Start
+		for i := paragraph.Start; i < paragraph.End; i++ {
+			if end, ok := sentenceEnd(runes, i, paragraph.End); ok {
+				if span := trimSpan(runes, Span{start, end}); span.Len() > 0 {
+					spans = append(spans, span)
+				}
+				start = end
+				i = end - 1
+			}
+		}
+		if span := trimSpan(runes, Span{start, paragraph.End}); span.Len() > 0 {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// SentenceAt 返回偏移所在的句子
+func SentenceAt(runes []rune, offset int) Span {
+	paragraph := ParagraphAt(runes, offset)
+	if paragraph.Len() == 0 {
+		return paragraph
+	}
+
+	// 向前找到上一句的结尾
+	start := paragraph.Start
+	for i := paragraph.Start; i < offset && i < paragraph.End; i++ {
+		if end, ok := sentenceEnd(runes, i, paragraph.End); ok && end <= offset {
+			start = end
+			i = end - 1
+		}
+	}
+
+	// 向后找到本句的结尾
+	end := paragraph.End
+	for i := offset; i < paragraph.End; i++ {
+		if e, ok := sentenceEnd(runes, i, paragraph.End); ok {
+			end = e
+			break
+		}
+	}
+
+	return trimSpan(runes, Span{start, end})
+}
+
+// sentenceEnd 判断i处是否为句末标点，返回句子结束位置（含后随的引号和括号）
+func sentenceEnd(runes []rune, i, limit int) (int, bool) {
+	r := runes[i]
+	switch r {
+	case '。', '！', '？', '；', '…', '!', '?':
+	case '.':
+		// 英文句点后需要跟空白或结束，避免把 3.14、e.g. 中间的点当作句末
+		if i+1 < limit && !unicode.IsSpace(runes[i+1]) && !isClosingPunct(runes[i+1]) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	end := i + 1
+	for end < limit && (isClosingPunct(runes[end]) || isTerminal(runes[end])) {
+		end++
+	}
+	return end, true
+}
+
+func isTerminal(r rune) bool {
+	switch r {
+	case '。', '！', '？', '…', '!', '?', '.':
+		return true
+	}
+	return false
+}
+
+func isClosingPunct(r rune) bool {
+	switch r {
+	case '"', '\'', '”', '’', '」', '』', '）', ')', '】', ']', '》':
+		return true
+	}
+	return false
+}
+
+// Paragraphs 切分所有段落。文本中有空行时以空行分段，否则每行为一段
+func Paragraphs(runes []rune) []Span {
+	blankLineMode := hasBlankLine(runes)
+
+	var spans []Span
+	start := 0
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && runes[i] != '\n' {
+			continue
+		}
+		if i < len(runes) && blankLineMode && !isBlankLineAfter(runes, i) {
+			continue
+		}
+		if span := trimSpan(runes, Span{start, i}); span.Len() > 0 {
+			spans = append(spans, span)
+		}
+		start = i + 1
+	}
+	return spans
+}
+
+// ParagraphAt 返回偏移所在的段落，偏移落在段落间的空白中时返回后一段
+func ParagraphAt(runes []rune, offset int) Span {
+	paragraphs := Paragraphs(runes)
+	for _, paragraph := range paragraphs {
+		if offset <= paragraph.End {
+			return paragraph
+		}
+	}
+	if n := len(paragraphs); n > 0 {
+		return paragraphs[n-1]
+	}
+	return Span{offset, offset}
+}
+
+// hasBlankLine 检查文本中是否存在空行
+func hasBlankLine(runes []rune) bool {
+	for i, r := range runes {
+		if r == '\n' && isBlankLineAfter(runes, i) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlankLineAfter 检查换行符之后是否紧跟一个空行
+func isBlankLineAfter(runes []rune, newline int) bool {
+	for j := newline + 1; j < len(runes); j++ {
+		switch {
+		case runes[j] == '\n':
+			return true
+		case !unicode.IsSpace(runes[j]):
+			return false
+		}
+	}
+	return false
+}
+
+// trimSpan 去掉区间首尾的空白
+func trimSpan(runes []rune, span Span) Span {
+	for span.Start < span.End && unicode.IsSpace(runes[span.Start]) {
+		span.Start++
+	}
+	for span.End > span.Start && unicode.IsSpace(runes[span.End-1]) {
+		span.End--
+	}
+	return span
+}
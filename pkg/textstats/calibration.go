@@ -0,0 +1,93 @@
+package textstats
+
+import (
+	"sync"
+	"time"
+)
+
+// 校准参数
+const (
+	calibrationRate = 0.2              // 每个样本对速度的影响，越大越快接近最近的阅读节奏
+	minSampleTime   = 10 * time.Second // 预计阅读时间太短的页面不作为样本
+	minPaceRatio    = 0.25             // 实际用时低于预计的这个比例，视为跳读
+	maxPaceRatio    = 4                // 实际用时超过预计的这个倍数，视为中途离开
+)
+
+// 阅读速度的合理范围，防止个别样本把速度带偏
+const (
+	minCJKCharsPerMinute   = 50
+	maxCJKCharsPerMinute   = 2000
+	minLatinWordsPerMinute = 40
+	maxLatinWordsPerMinute = 1500
+)
+
+// Calibrator 根据实际阅读用时校准阅读速度
+type Calibrator struct {
+	speed ReadingSpeed
+	mu    sync.RWMutex
+}
+
+// NewCalibrator 从已有的阅读速度开始校准
+func NewCalibrator(speed ReadingSpeed) *Calibrator {
+	defaults := DefaultReadingSpeed()
+	if speed.CJKCharsPerMinute <= 0 {
+		speed.CJKCharsPerMinute = defaults.CJKCharsPerMinute
+	}
+	if speed.LatinWordsPerMinute <= 0 {
+		speed.LatinWordsPerMinute = defaults.LatinWordsPerMinute
+	}
+	return &Calibrator{speed: speed}
+}
+
+// Speed 当前的阅读速度
+func (c *Calibrator) Speed() ReadingSpeed {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.speed
+}
+
+// Record 记录一次阅读：读完 stats 所统计的文本用了 elapsed。
+// 预计用时过短或实际用时明显异常的样本会被忽略，返回是否采用了样本
+func (c *Calibrator) Record(stats Stats, elapsed time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cjk, latin := c.speed.split(stats)
+	expected := time.Duration((cjk + latin) * float64(time.Minute))
+	if expected < minSampleTime {
+		return false
+	}
+	ratio := float64(elapsed) / float64(expected)
+	if ratio < minPaceRatio || ratio > maxPaceRatio {
+		return false
+	}
+
+	// 按预计用时的占比把实际用时分给两种文字，各自向观测到的速度靠近
+	minutes := elapsed.Minutes()
+	if cjk > 0 {
+		share := cjk / (cjk + latin)
+		observed := float64(stats.CJKCharacters) / (minutes * share)
+		c.speed.CJKCharsPerMinute = bound(
+			c.speed.CJKCharsPerMinute+calibrationRate*share*(observed-c.speed.CJKCharsPerMinute),
+			minCJKCharsPerMinute, maxCJKCharsPerMinute)
+	}
+	if latin > 0 {
+		share := latin / (cjk + latin)
+		observed := float64(stats.LatinWords) / (minutes * share)
+		c.speed.LatinWordsPerMinute = bound(
+			c.speed.LatinWordsPerMinute+calibrationRate*share*(observed-c.speed.LatinWordsPerMinute),
+			minLatinWordsPerMinute, maxLatinWordsPerMinute)
+	}
+	return true
+}
+
+func bound(value, low, high float64) float64 {
+	if value < low {
+		return low
+	}
+	if value > high {
+		return high
+	}
+	return value
+}
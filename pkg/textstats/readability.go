@@ -0,0 +1,94 @@
+package textstats
+
+import (
+	"ai-reader/pkg/segment"
+	"unicode"
+)
+
+// readability 计算可读性评分。拉丁文使用 Flesch Reading Ease，
+// CJK文本按平均句长和平均词长估算，混合文本按两者的阅读量加权
+func readability(runes []rune, sentences []segment.Span, stats Stats, cjkWords, cjkWordRunes, syllables int) float64 {
+	if stats.Words == 0 {
+		return 0
+	}
+
+	// 每个句子按主要文字分别计入两种评分
+	var latinSentences, cjkSentences int
+	for _, span := range sentences {
+		cjk, latin := 0, 0
+		for _, r := range runes[span.Start:span.End] {
+			if segment.IsCJK(r) {
+				cjk++
+			} else if unicode.IsLetter(r) {
+				latin++
+			}
+		}
+		if cjk >= latin {
+			cjkSentences++
+		} else {
+			latinSentences++
+		}
+	}
+
+	var latinScore, cjkScore float64
+	if stats.LatinWords > 0 {
+		sentences := float64(max(latinSentences, 1))
+		words := float64(stats.LatinWords)
+		latinScore = clamp(206.835 - 1.015*words/sentences - 84.6*float64(syllables)/words)
+	}
+	if cjkWords > 0 {
+		sentences := float64(max(cjkSentences, 1))
+		sentenceLength := float64(stats.CJKCharacters) / sentences
+		wordLength := float64(cjkWordRunes) / float64(cjkWords)
+		cjkScore = clamp(110 - sentenceLength - 20*wordLength)
+	}
+
+	// 按阅读时间加权，一个CJK字和一个拉丁词的阅读量不同
+	cjkWeight := float64(stats.CJKCharacters) / DefaultCJKCharsPerMinute
+	latinWeight := float64(stats.LatinWords) / DefaultLatinWordsPerMinute
+	return (cjkScore*cjkWeight + latinScore*latinWeight) / (cjkWeight + latinWeight)
+}
+
+// countSyllables 按元音组估算英文单词的音节数
+func countSyllables(word []rune) int {
+	count := 0
+	prevVowel := false
+	for _, r := range word {
+		vowel := isVowel(unicode.ToLower(r))
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+
+	// 词尾不发音的 e，如 "make"
+	if n := len(word); n > 2 && unicode.ToLower(word[n-1]) == 'e' && !isVowel(unicode.ToLower(word[n-2])) && count > 1 {
+		count--
+	}
+	return max(count, 1)
+}
+
+func isVowel(r rune) bool {
+	switch r {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+func clamp(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
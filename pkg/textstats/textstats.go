@@ -0,0 +1,103 @@
+package textstats
+
+import (
+	"ai-reader/pkg/segment"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 默认阅读速度
+const (
+	DefaultCJKCharsPerMinute   = 300 // 中日韩文字，每分钟字数
+	DefaultLatinWordsPerMinute = 230 // 拉丁等以空格分词的文字，每分钟词数
+)
+
+// ReadingSpeed 按文字分别计算的阅读速度
+type ReadingSpeed struct {
+	CJKCharsPerMinute   float64
+	LatinWordsPerMinute float64
+}
+
+// DefaultReadingSpeed 默认阅读速度
+func DefaultReadingSpeed() ReadingSpeed {
+	return ReadingSpeed{
+		CJKCharsPerMinute:   DefaultCJKCharsPerMinute,
+		LatinWordsPerMinute: DefaultLatinWordsPerMinute,
+	}
+}
+
+// Estimate 估算阅读时间
+func (s ReadingSpeed) Estimate(stats Stats) time.Duration {
+	cjk, latin := s.split(stats)
+	return time.Duration((cjk + latin) * float64(time.Minute))
+}
+
+// split 分别估算两种文字的阅读分钟数
+func (s ReadingSpeed) split(stats Stats) (cjk, latin float64) {
+	if s.CJKCharsPerMinute > 0 {
+		cjk = float64(stats.CJKCharacters) / s.CJKCharsPerMinute
+	}
+	if s.LatinWordsPerMinute > 0 {
+		latin = float64(stats.LatinWords) / s.LatinWordsPerMinute
+	}
+	return cjk, latin
+}
+
+// Stats 文本统计结果
+type Stats struct {
+	Characters    int // 非空白字符数，含标点
+	CJKCharacters int // 中日韩文字数，不含标点
+	LatinWords    int // 以空格分词的文字的词数
+	Words         int // 总词数，CJK文本按词典分词
+	UniqueWords   int // 不重复的词数，忽略大小写
+	Sentences     int
+	Paragraphs    int
+	ReadingTime   time.Duration // 按默认阅读速度估算
+	Readability   float64       // 可读性评分，0-100，越高越易读
+}
+
+// Compute 统计文本
+func Compute(text string) Stats {
+	runes := []rune(text)
+
+	var stats Stats
+	for _, r := range runes {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		stats.Characters++
+		if segment.IsCJK(r) {
+			stats.CJKCharacters++
+		}
+	}
+
+	vocabulary := make(map[string]bool)
+	var cjkWords, cjkWordRunes, syllables int
+	for _, span := range segment.Default().Words(runes) {
+		word := runes[span.Start:span.End]
+		if segment.IsCJK(word[0]) {
+			cjkWords++
+			cjkWordRunes += len(word)
+		} else {
+			stats.LatinWords++
+			syllables += countSyllables(word)
+		}
+		vocabulary[strings.ToLower(string(word))] = true
+	}
+	stats.Words = cjkWords + stats.LatinWords
+	stats.UniqueWords = len(vocabulary)
+
+	sentences := segment.Sentences(runes)
+	stats.Sentences = len(sentences)
+	stats.Paragraphs = len(segment.Paragraphs(runes))
+
+	stats.ReadingTime = DefaultReadingSpeed().Estimate(stats)
+	stats.Readability = readability(runes, sentences, stats, cjkWords, cjkWordRunes, syllables)
+	return stats
+}
+
+// WordCount 通行的字数统计：每个CJK字计为一个词，加上拉丁文的词数
+func (s Stats) WordCount() int {
+	return s.CJKCharacters + s.LatinWords
+}